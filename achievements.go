@@ -0,0 +1,119 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// Achievements endpoint
+	achievementsEndpoint = "https://achievements.xboxlive.com/users"
+)
+
+// GetAchievements returns one page of a user's achievements across all titles
+// Returns the achievements and the continuation token for the next page (empty when there are no more)
+func (c *Client) GetAchievements(ctx context.Context, xuid string, opts PageOptions) ([]*Achievement, string, error) {
+	if xuid == "" {
+		return nil, "", fmt.Errorf("XUID is required")
+	}
+
+	params, err := opts.query("maxItems", "continuationToken")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/xuid(%s)/achievements?%s", achievementsEndpoint, url.PathEscape(xuid), params.Encode())
+
+	var resp AchievementsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceAchievements, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get achievements: %w", err)
+	}
+
+	return resp.Achievements, resp.PagingInfo.ContinuationToken, nil
+}
+
+// recentAchievementsPageSize is the page size used when fetching recent unlocks
+const recentAchievementsPageSize = 100
+
+// GetRecentAchievements returns the achievements a user unlocked after since, across all titles, oldest first
+// Unlocks are fetched newest first and paging stops at the first one not after since, so incremental sync jobs
+// can pass the unlock time of the last achievement they stored and fetch only what is new. A zero since returns
+// every unlock
+func (c *Client) GetRecentAchievements(ctx context.Context, xuid string, since time.Time) ([]*Achievement, error) {
+	if xuid == "" {
+		return nil, fmt.Errorf("XUID is required")
+	}
+
+	var achievements []*Achievement
+	token := ""
+	for {
+		page, next, err := c.getUnlocksPage(ctx, xuid, recentAchievementsPageSize, token)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range page {
+			if a.Progression == nil || !a.Progression.TimeUnlocked.After(since) {
+				slices.Reverse(achievements)
+				return achievements, nil
+			}
+			achievements = append(achievements, a)
+		}
+
+		token = next
+		if token == "" {
+			slices.Reverse(achievements)
+			return achievements, nil
+		}
+	}
+}
+
+// titleAchievementsPageSize is the page size used when fetching a title's full achievement list
+const titleAchievementsPageSize = 1000
+
+// GetTitleAchievements returns the full achievement list of a title: names, descriptions, rewards, media, and rarity
+// The achievements service only serves title metadata in the context of a user, so the list is fetched for the
+// authenticated user and the user's progress is cleared from the results
+func (c *Client) GetTitleAchievements(ctx context.Context, titleID TitleID) ([]*Achievement, error) {
+	if titleID == 0 {
+		return nil, fmt.Errorf("title ID is required")
+	}
+
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var achievements []*Achievement
+	token := ""
+	for {
+		params := url.Values{}
+		params.Set("titleId", titleID.String())
+		params.Set("maxItems", strconv.Itoa(titleAchievementsPageSize))
+		if token != "" {
+			params.Set("continuationToken", token)
+		}
+
+		endpoint := fmt.Sprintf("%s/xuid(%s)/achievements?%s", achievementsEndpoint, url.PathEscape(claims.XUID), params.Encode())
+
+		var resp AchievementsResponse
+		if err := c.xblRequest(ctx, "GET", endpoint, ServiceAchievements, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get title achievements: %w", err)
+		}
+
+		for _, a := range resp.Achievements {
+			a.ProgressState = ""
+			a.Progression = nil
+		}
+		achievements = append(achievements, resp.Achievements...)
+
+		token = resp.PagingInfo.ContinuationToken
+		if token == "" {
+			return achievements, nil
+		}
+	}
+}
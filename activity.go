@@ -0,0 +1,34 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Activity feed endpoint
+	activityEndpoint = "https://avty.xboxlive.com/users"
+)
+
+// GetActivity returns one page of a user's activity history
+// Returns the activity items and the continuation token for the next page (empty when there are no more)
+func (c *Client) GetActivity(ctx context.Context, xuid string, opts PageOptions) ([]*ActivityItem, string, error) {
+	if xuid == "" {
+		return nil, "", fmt.Errorf("XUID is required")
+	}
+
+	params, err := opts.query("numItems", "contToken")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/xuid(%s)/activity/History?%s", activityEndpoint, url.PathEscape(xuid), params.Encode())
+
+	var resp ActivityResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceActivity, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	return resp.ActivityItems, resp.ContinuationToken, nil
+}
@@ -0,0 +1,224 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// Profile settings batch endpoint
+	profileSettingsBatchEndpoint = "https://profile.xboxlive.com/users/batch/profile/settings"
+
+	// maxProfileBatchSize is the largest number of users the profile settings endpoint accepts per request
+	maxProfileBatchSize = 100
+)
+
+// Alias is a gamertag a user was known by
+type Alias struct {
+	Gamertag  string    `json:"gamertag"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// AliasStore is an interface for persisting the gamertag history of users
+type AliasStore interface {
+	// Latest returns the most recently seen gamertag for a user
+	Latest(ctx context.Context, xuid string) (gamertag string, ok bool, err error)
+
+	// Record notes that a user was seen with a gamertag
+	Record(ctx context.Context, xuid string, gamertag string, seen time.Time) error
+
+	// History returns every gamertag a user has been seen with, oldest first
+	History(ctx context.Context, xuid string) ([]Alias, error)
+}
+
+// GamertagChangedFunc is called when a user is seen with a different gamertag than previously recorded
+type GamertagChangedFunc func(xuid string, oldGamertag string, newGamertag string)
+
+// ResolveGamertags returns the current gamertag for each XUID (map of XUID -> gamertag)
+// XUIDs that no longer resolve are omitted. When an alias store is configured, each result is
+// compared with the last recorded gamertag and Config.OnGamertagChanged is called for any that changed
+func (c *Client) ResolveGamertags(ctx context.Context, xuids []string) (map[string]string, error) {
+	users, err := c.getProfileSettings(ctx, xuids, []string{string(ProfileFieldGamertag)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve gamertags: %w", err)
+	}
+
+	result := make(map[string]string, len(users))
+	for _, user := range users {
+		if gamertag := user.Setting(string(ProfileFieldGamertag)); gamertag != "" {
+			result[user.ID] = gamertag
+		}
+	}
+
+	if err := c.trackAliases(ctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// trackAliases records resolved gamertags in the alias store and reports changes
+func (c *Client) trackAliases(ctx context.Context, gamertags map[string]string) error {
+	if c.aliases == nil {
+		return nil
+	}
+
+	return RecordAliases(ctx, c.aliases, gamertags, c.clock.Now(), c.onGamertagChanged)
+}
+
+// RecordAliases records resolved gamertags (map of XUID -> gamertag) in an alias store, calling onChanged (if non-nil)
+// for each user whose gamertag differs from the last one recorded
+// ResolveGamertags does this with Config.AliasStore; call it directly to track changes in a store of your own
+func RecordAliases(ctx context.Context, store AliasStore, gamertags map[string]string, seen time.Time, onChanged GamertagChangedFunc) error {
+	for xuid, gamertag := range gamertags {
+		old, ok, err := store.Latest(ctx, xuid)
+		if err != nil {
+			return fmt.Errorf("failed to read alias history: %w", err)
+		}
+		if err := store.Record(ctx, xuid, gamertag, seen); err != nil {
+			return fmt.Errorf("failed to record alias: %w", err)
+		}
+		if ok && old != gamertag && onChanged != nil {
+			onChanged(xuid, old, gamertag)
+		}
+	}
+
+	return nil
+}
+
+// MemoryAliasStore is an in-memory implementation of AliasStore
+type MemoryAliasStore struct {
+	mu      sync.Mutex
+	history map[string][]Alias
+}
+
+// NewMemoryAliasStore creates a new in-memory alias store
+func NewMemoryAliasStore() *MemoryAliasStore {
+	return &MemoryAliasStore{
+		history: make(map[string][]Alias),
+	}
+}
+
+// Latest returns the most recently seen gamertag for a user
+func (m *MemoryAliasStore) Latest(ctx context.Context, xuid string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.history[xuid]
+	if len(h) == 0 {
+		return "", false, nil
+	}
+	return h[len(h)-1].Gamertag, true, nil
+}
+
+// Record notes that a user was seen with a gamertag
+func (m *MemoryAliasStore) Record(ctx context.Context, xuid string, gamertag string, seen time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.history[xuid]
+	if len(h) > 0 && h[len(h)-1].Gamertag == gamertag {
+		h[len(h)-1].LastSeen = seen
+		return nil
+	}
+	m.history[xuid] = append(h, Alias{Gamertag: gamertag, FirstSeen: seen, LastSeen: seen})
+	return nil
+}
+
+// History returns every gamertag a user has been seen with, oldest first
+func (m *MemoryAliasStore) History(ctx context.Context, xuid string) ([]Alias, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := make([]Alias, len(m.history[xuid]))
+	copy(h, m.history[xuid])
+	return h, nil
+}
+
+// FileAliasStore is an AliasStore persisted as a JSON file, rewritten on every change
+type FileAliasStore struct {
+	mu       sync.Mutex
+	filePath string
+	history  map[string][]Alias
+}
+
+// NewFileAliasStore creates an alias store backed by a JSON file, loading any history it already holds
+func NewFileAliasStore(filePath string) (*FileAliasStore, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create alias store directory: %w", err)
+	}
+
+	store := &FileAliasStore{
+		filePath: filePath,
+		history:  make(map[string][]Alias),
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read alias store: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.history); err != nil {
+			return nil, fmt.Errorf("failed to parse alias store: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Latest returns the most recently seen gamertag for a user
+func (f *FileAliasStore) Latest(ctx context.Context, xuid string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h := f.history[xuid]
+	if len(h) == 0 {
+		return "", false, nil
+	}
+	return h[len(h)-1].Gamertag, true, nil
+}
+
+// Record notes that a user was seen with a gamertag
+func (f *FileAliasStore) Record(ctx context.Context, xuid string, gamertag string, seen time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h := f.history[xuid]
+	if len(h) > 0 && h[len(h)-1].Gamertag == gamertag {
+		h[len(h)-1].LastSeen = seen
+	} else {
+		f.history[xuid] = append(h, Alias{Gamertag: gamertag, FirstSeen: seen, LastSeen: seen})
+	}
+
+	return f.save()
+}
+
+// History returns every gamertag a user has been seen with, oldest first
+func (f *FileAliasStore) History(ctx context.Context, xuid string) ([]Alias, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h := make([]Alias, len(f.history[xuid]))
+	copy(h, f.history[xuid])
+	return h, nil
+}
+
+// save writes the history to disk
+func (f *FileAliasStore) save() error {
+	data, err := json.MarshalIndent(f.history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias history: %w", err)
+	}
+
+	if err := os.WriteFile(f.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write alias store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEvent records a single mutating operation performed by the client
+type AuditEvent struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	TargetXUID string    `json:"target_xuid,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+
+	// DryRun is true when the operation wasn't sent because Config.DryRun is set
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// AuditSink is an interface for recording audit events
+type AuditSink interface {
+	Record(ctx context.Context, event AuditEvent) error
+}
+
+// AuditSinkFunc adapts a function to the AuditSink interface
+type AuditSinkFunc func(ctx context.Context, event AuditEvent) error
+
+// Record calls f(ctx, event)
+func (f AuditSinkFunc) Record(ctx context.Context, event AuditEvent) error {
+	return f(ctx, event)
+}
+
+// JSONAuditSink writes audit events as newline-delimited JSON
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditSink creates an audit sink that writes one JSON object per event to w
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// Record writes the event
+func (s *JSONAuditSink) Record(ctx context.Context, event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(event)
+}
+
+// mutationKey is the context key marking requests made by a mutating operation
+type mutationKey struct{}
+
+// isMutation reports whether ctx belongs to a mutating operation
+func isMutation(ctx context.Context) bool {
+	return ctx.Value(mutationKey{}) != nil
+}
+
+// mutate runs a mutating operation and records it to the audit sink, if one is configured
+// Every write API must go through mutate so the audit trail is complete and Config.DryRun is honored;
+// fn must make its requests with the context it is passed
+func (c *Client) mutate(ctx context.Context, operation string, targetXUID string, fn func(ctx context.Context) error) error {
+	err := fn(context.WithValue(ctx, mutationKey{}, operation))
+
+	if c.audit == nil {
+		return err
+	}
+
+	event := AuditEvent{
+		Time:       c.clock.Now(),
+		Operation:  operation,
+		TargetXUID: targetXUID,
+		Success:    err == nil,
+		DryRun:     c.dryRun,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if auditErr := c.audit.Record(ctx, event); auditErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to record audit event: %w", auditErr))
+	}
+
+	return err
+}
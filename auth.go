@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -13,9 +13,12 @@ import (
 )
 
 const (
-	// OAuth endpoints
-	deviceCodeEndpoint = "https://login.microsoftonline.com/consumers/oauth2/v2.0/devicecode"
-	tokenEndpoint      = "https://login.microsoftonline.com/consumers/oauth2/v2.0/token"
+	// OAuth endpoints, formatted with the tenant
+	deviceCodeEndpoint = "https://login.microsoftonline.com/%s/oauth2/v2.0/devicecode"
+	tokenEndpoint      = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+
+	// DefaultTenant is the Microsoft Entra ID tenant used for personal Microsoft accounts
+	DefaultTenant = "consumers"
 
 	// Xbox endpoints
 	userAuthEndpoint = "https://user.auth.xboxlive.com/user/authenticate"
@@ -35,10 +38,10 @@ func (c *Client) authenticateDeviceCode(ctx context.Context) error {
 
 	// Display instructions to user
 	fmt.Printf("\n")
-	fmt.Printf("To sign in, use a web browser to open the page:\n")
+	fmt.Printf("%s\n", c.localizer.message(MsgSignInOpenPage))
 	fmt.Printf("    %s\n", deviceCode.VerificationURI)
 	fmt.Printf("\n")
-	fmt.Printf("And enter the code:\n")
+	fmt.Printf("%s\n", c.localizer.message(MsgSignInEnterCode))
 	fmt.Printf("    %s\n", deviceCode.UserCode)
 	fmt.Printf("\n")
 
@@ -49,7 +52,7 @@ func (c *Client) authenticateDeviceCode(ctx context.Context) error {
 	}
 
 	// Cache the tokens
-	notAfter := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	notAfter := c.clock.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
 	if err := c.cache.SetAccessToken(ctx, token.AccessToken, notAfter); err != nil {
 		return fmt.Errorf("failed to cache access token: %w", err)
 	}
@@ -57,7 +60,7 @@ func (c *Client) authenticateDeviceCode(ctx context.Context) error {
 		return fmt.Errorf("failed to cache refresh token: %w", err)
 	}
 
-	fmt.Printf("Authentication successful!\n\n")
+	fmt.Printf("%s\n\n", c.localizer.message(MsgSignInSuccess))
 	return nil
 }
 
@@ -67,21 +70,21 @@ func (c *Client) requestDeviceCode(ctx context.Context) (*DeviceCodeResponse, er
 	data.Set("client_id", c.clientID)
 	data.Set("scope", scopes)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", deviceCodeEndpoint, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf(deviceCodeEndpoint, c.tenant), strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("device code request failed: %s - %s", resp.Status, string(body))
+		body, truncated := captureErrorBody(resp.Body)
+		return nil, newXboxAPIError("device code request", resp, body, truncated)
 	}
 
 	var deviceCode DeviceCodeResponse
@@ -96,7 +99,7 @@ func (c *Client) requestDeviceCode(ctx context.Context) (*DeviceCodeResponse, er
 func (c *Client) pollForToken(ctx context.Context, deviceCode *DeviceCodeResponse) (*TokenResponse, error) {
 	interval := time.Duration(deviceCode.Interval) * time.Second
 	timeout := time.Duration(deviceCode.ExpiresIn) * time.Second
-	deadline := time.Now().Add(timeout)
+	deadline := c.clock.Now().Add(timeout)
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -106,8 +109,8 @@ func (c *Client) pollForToken(ctx context.Context, deviceCode *DeviceCodeRespons
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-ticker.C:
-			if time.Now().After(deadline) {
-				return nil, fmt.Errorf("device code expired")
+			if c.clock.Now().After(deadline) {
+				return nil, errors.New(c.localizer.message(MsgDeviceCodeExpired))
 			}
 
 			token, err := c.tryGetToken(ctx, deviceCode.DeviceCode)
@@ -131,21 +134,21 @@ func (c *Client) tryGetToken(ctx context.Context, deviceCode string) (*TokenResp
 	data.Set("client_id", c.clientID)
 	data.Set("device_code", deviceCode)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenEndpoint, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf(tokenEndpoint, c.tenant), strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-
 	if resp.StatusCode != http.StatusOK {
+		body, truncated := captureErrorBody(resp.Body)
+
 		// Parse error response
 		var errorResp struct {
 			Error            string `json:"error"`
@@ -154,11 +157,11 @@ func (c *Client) tryGetToken(ctx context.Context, deviceCode string) (*TokenResp
 		if err := json.Unmarshal(body, &errorResp); err == nil {
 			return nil, fmt.Errorf("%s: %s", errorResp.Error, errorResp.ErrorDescription)
 		}
-		return nil, fmt.Errorf("token request failed: %s - %s", resp.Status, string(body))
+		return nil, newXboxAPIError("token request", resp, body, truncated)
 	}
 
 	var token TokenResponse
-	if err := json.Unmarshal(body, &token); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
 		return nil, err
 	}
 
@@ -166,6 +169,7 @@ func (c *Client) tryGetToken(ctx context.Context, deviceCode string) (*TokenResp
 }
 
 // refreshAccessToken refreshes the access token using the refresh token
+// The caller must hold c.tokenMu
 func (c *Client) refreshAccessToken(ctx context.Context) error {
 	refreshToken, ok := c.cache.GetRefreshToken(ctx)
 	if !ok {
@@ -178,21 +182,21 @@ func (c *Client) refreshAccessToken(ctx context.Context) error {
 	data.Set("refresh_token", refreshToken)
 	data.Set("scope", scopes)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenEndpoint, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf(tokenEndpoint, c.tenant), strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("token refresh failed: %s - %s", resp.Status, string(body))
+		body, truncated := captureErrorBody(resp.Body)
+		return newXboxAPIError("token refresh", resp, body, truncated)
 	}
 
 	var token TokenResponse
@@ -201,7 +205,7 @@ func (c *Client) refreshAccessToken(ctx context.Context) error {
 	}
 
 	// Cache the new tokens
-	notAfter := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	notAfter := c.clock.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
 	if err := c.cache.SetAccessToken(ctx, token.AccessToken, notAfter); err != nil {
 		return err
 	}
@@ -238,15 +242,15 @@ func (c *Client) getXboxUserToken(ctx context.Context, accessToken string) (*Xbo
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-xbl-contract-version", "1")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("user token request failed: %s - %s", resp.Status, string(body))
+		body, truncated := captureErrorBody(resp.Body)
+		return nil, newXboxAPIError("user token request", resp, body, truncated)
 	}
 
 	var userToken XboxUserTokenResponse
@@ -257,14 +261,14 @@ func (c *Client) getXboxUserToken(ctx context.Context, accessToken string) (*Xbo
 	return &userToken, nil
 }
 
-// getXSTSToken exchanges the Xbox user token for an XSTS token
-func (c *Client) getXSTSToken(ctx context.Context, userToken string) (*XSTSTokenResponse, error) {
+// getXSTSToken exchanges the Xbox user token for an XSTS token for the given audience
+func (c *Client) getXSTSToken(ctx context.Context, userToken string, audience XSTSAudience) (*XSTSTokenResponse, error) {
 	reqBody := XSTSTokenRequest{
-		RelyingParty: "http://xboxlive.com",
+		RelyingParty: audience.RelyingParty,
 		TokenType:    "JWT",
 		Properties: XSTSTokenRequestProperties{
 			UserTokens: []string{userToken},
-			SandboxId:  "RETAIL",
+			SandboxId:  audience.SandboxID,
 		},
 	}
 
@@ -280,22 +284,24 @@ func (c *Client) getXSTSToken(ctx context.Context, userToken string) (*XSTSToken
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-xbl-contract-version", "1")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		body, truncated := captureErrorBody(resp.Body)
 
 		// Try to parse Xbox error response
 		var xboxErr XboxErrorResponse
 		if err := json.Unmarshal(body, &xboxErr); err == nil && xboxErr.XErr != 0 {
-			return nil, formatXboxError(xboxErr)
+			xerr := newXboxError(xboxErr)
+			xerr.description = c.localizerFor(ctx).xerrDescription(xerr.XErr)
+			return nil, xerr
 		}
 
-		return nil, fmt.Errorf("XSTS token request failed: %s - %s", resp.Status, string(body))
+		return nil, newXboxAPIError("XSTS token request", resp, body, truncated)
 	}
 
 	var xstsToken XSTSTokenResponse
@@ -306,45 +312,45 @@ func (c *Client) getXSTSToken(ctx context.Context, userToken string) (*XSTSToken
 	return &xstsToken, nil
 }
 
-// formatXboxError formats an Xbox error response into a user-friendly message
-func formatXboxError(err XboxErrorResponse) error {
-	switch err.XErr {
-	case 2148916233: // 0x8015DC0B
-		return fmt.Errorf("no Xbox account found: the Microsoft account you authenticated with doesn't have an Xbox Live profile. Create one at https://www.xbox.com/")
-	case 2148916235: // 0x8015DC0D
-		//lint:ignore ST1005 Xbox Live is a proper name
-		return fmt.Errorf("Xbox Live is not available in your country/region")
-	case 2148916236, 2148916237: // 0x8015DC0E, 0x8015DC0F
-		return fmt.Errorf("the account needs adult verification. Please verify your account at https://account.microsoft.com/")
-	case 2148916238: // 0x8015DC10
-		return fmt.Errorf("the account is a child account and cannot proceed unless the parent consents")
-	default:
-		if err.Message != "" {
-			//lint:ignore ST1005 Xbox is a proper name
-			return fmt.Errorf("Xbox error %d: %s", err.XErr, err.Message)
-		}
-		//lint:ignore ST1005 Xbox is a proper name
-		return fmt.Errorf("Xbox error code: %d (0x%X)", err.XErr, err.XErr)
-	}
+// ensureXSTSToken ensures we have a valid XSTS token for the default audience, refreshing if necessary
+func (c *Client) ensureXSTSToken(ctx context.Context) (string, string, error) {
+	return c.ensureXSTSTokenFor(ctx, DefaultAudience)
 }
 
-// ensureXSTSToken ensures we have a valid XSTS token, refreshing if necessary
-func (c *Client) ensureXSTSToken(ctx context.Context) (string, string, error) {
+// ensureXSTSTokenFor ensures we have a valid XSTS token for the given audience, refreshing if necessary
+func (c *Client) ensureXSTSTokenFor(ctx context.Context, audience XSTSAudience) (string, string, error) {
 	// Check if we have a valid cached XSTS token
-	if token, userHash, ok := c.cache.GetXSTSToken(ctx); ok {
+	if token, userHash, ok := c.getCachedXSTSToken(ctx, audience); ok {
 		return token, userHash, nil
 	}
 
+	// Serialize token chain acquisition so concurrent calls don't each exchange tokens
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	// Another goroutine may have obtained a token while we waited
+	if token, userHash, ok := c.getCachedXSTSToken(ctx, audience); ok {
+		return token, userHash, nil
+	}
+
+	return c.fetchXSTSTokenLocked(ctx, audience)
+}
+
+// fetchXSTSToken obtains a new XSTS token for the given audience, bypassing any cached XSTS token
+func (c *Client) fetchXSTSToken(ctx context.Context, audience XSTSAudience) (string, string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	return c.fetchXSTSTokenLocked(ctx, audience)
+}
+
+// fetchXSTSTokenLocked is fetchXSTSToken for callers holding c.tokenMu
+func (c *Client) fetchXSTSTokenLocked(ctx context.Context, audience XSTSAudience) (string, string, error) {
 	// Check if we have a valid cached user token
 	if userToken, ok := c.cache.GetUserToken(ctx); ok {
 		// Exchange for XSTS token
-		xstsResp, err := c.getXSTSToken(ctx, userToken)
+		xstsResp, err := c.getXSTSToken(ctx, userToken, audience)
 		if err == nil {
-			userHash := extractUserHash(xstsResp.DisplayClaims)
-			if err := c.cache.SetXSTSToken(ctx, xstsResp.Token, userHash, xstsResp.NotAfter); err != nil {
-				return "", "", err
-			}
-			return xstsResp.Token, userHash, nil
+			return c.storeXSTSToken(ctx, audience, xstsResp)
 		}
 	}
 
@@ -372,14 +378,39 @@ func (c *Client) ensureXSTSToken(ctx context.Context) (string, string, error) {
 	}
 
 	// Exchange user token for XSTS token
-	xstsResp, err := c.getXSTSToken(ctx, userTokenResp.Token)
+	xstsResp, err := c.getXSTSToken(ctx, userTokenResp.Token, audience)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get XSTS token: %w", err)
 	}
 
+	return c.storeXSTSToken(ctx, audience, xstsResp)
+}
+
+// getCachedXSTSToken returns the cached XSTS token for an audience
+// Only the default audience is cached unless the cache implements AudienceTokenCache
+func (c *Client) getCachedXSTSToken(ctx context.Context, audience XSTSAudience) (string, string, bool) {
+	if audience == DefaultAudience {
+		return c.cache.GetXSTSToken(ctx)
+	}
+	if ac, ok := c.cache.(AudienceTokenCache); ok {
+		return ac.GetXSTSTokenFor(ctx, audience)
+	}
+	return "", "", false
+}
+
+// storeXSTSToken caches an XSTS token for an audience and returns the token and user hash
+func (c *Client) storeXSTSToken(ctx context.Context, audience XSTSAudience, xstsResp *XSTSTokenResponse) (string, string, error) {
 	userHash := extractUserHash(xstsResp.DisplayClaims)
-	if err := c.cache.SetXSTSToken(ctx, xstsResp.Token, userHash, xstsResp.NotAfter); err != nil {
-		return "", "", err
+
+	if audience == DefaultAudience {
+		c.setClaims(parseXSTSClaims(xstsResp.DisplayClaims))
+		if err := c.cache.SetXSTSToken(ctx, xstsResp.Token, userHash, xstsResp.NotAfter); err != nil {
+			return "", "", err
+		}
+	} else if ac, ok := c.cache.(AudienceTokenCache); ok {
+		if err := ac.SetXSTSTokenFor(ctx, audience, xstsResp.Token, userHash, xstsResp.NotAfter); err != nil {
+			return "", "", err
+		}
 	}
 
 	return xstsResp.Token, userHash, nil
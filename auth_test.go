@@ -0,0 +1,189 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rewriteTransport sends every request to a test server, keeping its path
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient creates a client whose requests all go to server, with a memory token cache
+func newTestClient(t *testing.T, server *httptest.Server, cache *MemoryTokenCache) *Client {
+	t.Helper()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := New(Config{
+		ClientID:  "00000000-0000-0000-0000-000000000000",
+		Cache:     cache,
+		Transport: &rewriteTransport{target: target},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestEnsureXSTSTokenConcurrent(t *testing.T) {
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xsts/authorize" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.Error(w, "unexpected", http.StatusNotFound)
+			return
+		}
+		exchanges.Add(1)
+
+		// Hold the exchange open so the other callers pile up behind it
+		time.Sleep(50 * time.Millisecond)
+
+		_ = json.NewEncoder(w).Encode(XSTSTokenResponse{
+			NotAfter:      time.Now().Add(time.Hour),
+			Token:         "xsts-token",
+			DisplayClaims: XSTSTokenDisplayClaims{Xui: []map[string]interface{}{{"uhs": "user-hash", "xid": "2533274792093503"}}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	if err := cache.SetUserToken(ctx, "user-token", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	const callers = 50
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			token, userHash, err := client.ensureXSTSToken(ctx)
+			if err != nil {
+				t.Errorf("ensureXSTSToken: %v", err)
+				return
+			}
+			if token != "xsts-token" || userHash != "user-hash" {
+				t.Errorf("ensureXSTSToken = %q, %q; want xsts-token, user-hash", token, userHash)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := exchanges.Load(); n != 1 {
+		t.Errorf("%d XSTS exchanges for %d concurrent callers; want 1", n, callers)
+	}
+}
+
+func TestTokenChainSendsTraceID(t *testing.T) {
+	var traceIDs, userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceIDs = append(traceIDs, r.Header.Get(TraceIDHeader))
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		_ = json.NewEncoder(w).Encode(XSTSTokenResponse{
+			NotAfter:      time.Now().Add(time.Hour),
+			Token:         "xsts-token",
+			DisplayClaims: XSTSTokenDisplayClaims{Xui: []map[string]interface{}{{"uhs": "user-hash", "xid": "2533274792093503"}}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := WithTraceID(context.Background(), "trace-1909")
+	cache := NewMemoryTokenCache()
+	if err := cache.SetUserToken(ctx, "user-token", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	if _, _, err := client.ensureXSTSToken(ctx); err != nil {
+		t.Fatalf("ensureXSTSToken: %v", err)
+	}
+	if len(traceIDs) != 1 || traceIDs[0] != "trace-1909" {
+		t.Errorf("XSTS request trace IDs = %q; want [trace-1909]", traceIDs)
+	}
+	if len(userAgents) != 1 || userAgents[0] == "" {
+		t.Errorf("XSTS request sent without a User-Agent")
+	}
+}
+
+func TestAuthenticateWithRefreshTokenReplacesAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token"):
+			if got := r.FormValue("refresh_token"); got != "new-refresh" {
+				t.Errorf("redeemed refresh token %q; want new-refresh", got)
+			}
+			_ = json.NewEncoder(w).Encode(TokenResponse{AccessToken: "new-access", RefreshToken: "new-refresh-2", ExpiresIn: 3600})
+		case r.URL.Path == "/user/authenticate":
+			_ = json.NewEncoder(w).Encode(XboxUserTokenResponse{NotAfter: time.Now().Add(time.Hour), Token: "new-user"})
+		case r.URL.Path == "/xsts/authorize":
+			_ = json.NewEncoder(w).Encode(XSTSTokenResponse{
+				NotAfter:      time.Now().Add(time.Hour),
+				Token:         "new-xsts",
+				DisplayClaims: XSTSTokenDisplayClaims{Xui: []map[string]interface{}{{"uhs": "new-hash", "xid": "2"}}},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.Error(w, "unexpected", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// The cache still holds a valid chain for another account
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	expiry := time.Now().Add(time.Hour)
+	if err := cache.SetUserToken(ctx, "old-user", expiry); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SetXSTSToken(ctx, "old-xsts", "old-hash", expiry); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SetXSTSTokenFor(ctx, XSTSAudience{RelyingParty: "rp://other", SandboxID: "RETAIL"}, "old-other", "old-hash", expiry); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	if err := client.AuthenticateWithRefreshToken(ctx, "new-refresh"); err != nil {
+		t.Fatal(err)
+	}
+
+	token, userHash, err := client.ensureXSTSToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "new-xsts" || userHash != "new-hash" {
+		t.Errorf("XSTS token after bootstrap = %q, %q; want new-xsts, new-hash", token, userHash)
+	}
+	if _, _, ok := cache.GetXSTSTokenFor(ctx, XSTSAudience{RelyingParty: "rp://other", SandboxID: "RETAIL"}); ok {
+		t.Error("previous account's audience token survived the bootstrap")
+	}
+	if claims := client.getClaims(); claims == nil || claims.XUID != "2" {
+		t.Errorf("claims after bootstrap = %+v; want XUID 2", claims)
+	}
+}
@@ -0,0 +1,48 @@
+package xblive
+
+import (
+	"context"
+	"time"
+)
+
+// Broadcast describes a user who is currently broadcasting a title
+type Broadcast struct {
+	XUID      string    `json:"xuid"`
+	TitleID   string    `json:"titleId"`
+	TitleName string    `json:"titleName"`
+	Provider  string    `json:"provider"`
+	ChannelID string    `json:"channelId"`
+	Session   string    `json:"session"`
+	Viewers   int       `json:"viewers"`
+	Started   time.Time `json:"started"`
+}
+
+// GetBroadcasts returns the users among xuids who are currently broadcasting, with provider and channel info where available
+func (c *Client) GetBroadcasts(ctx context.Context, xuids []string) ([]*Broadcast, error) {
+	presence, err := c.GetPresence(ctx, xuids)
+	if err != nil {
+		return nil, err
+	}
+
+	var broadcasts []*Broadcast
+	for _, p := range presence {
+		for _, title := range p.ActiveTitles() {
+			if title.Activity == nil || title.Activity.Broadcast == nil {
+				continue
+			}
+			b := title.Activity.Broadcast
+			broadcasts = append(broadcasts, &Broadcast{
+				XUID:      p.XUID,
+				TitleID:   title.ID,
+				TitleName: title.Name,
+				Provider:  b.Provider,
+				ChannelID: b.ID,
+				Session:   b.Session,
+				Viewers:   b.Viewers,
+				Started:   b.Started,
+			})
+		}
+	}
+
+	return broadcasts, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -22,26 +23,78 @@ type TokenCache interface {
 	Clear(ctx context.Context) error
 }
 
+// XSTSAudience identifies the relying party and sandbox an XSTS token is issued for
+type XSTSAudience struct {
+	RelyingParty string
+	SandboxID    string
+}
+
+// DefaultAudience is the audience used for Xbox Live API calls
+var DefaultAudience = XSTSAudience{
+	RelyingParty: "http://xboxlive.com",
+	SandboxID:    "RETAIL",
+}
+
+// Key returns a string uniquely identifying the audience, suitable as a cache key
+func (a XSTSAudience) Key() string {
+	return a.RelyingParty + "|" + a.SandboxID
+}
+
+// AudienceTokenCache is an optional interface a TokenCache can implement to cache XSTS tokens for multiple audiences
+// Without it, only tokens for DefaultAudience are cached
+type AudienceTokenCache interface {
+	GetXSTSTokenFor(ctx context.Context, audience XSTSAudience) (token string, userHash string, ok bool)
+	SetXSTSTokenFor(ctx context.Context, audience XSTSAudience, token string, userHash string, notAfter time.Time) error
+}
+
+// TokenSnapshotter is an optional interface a TokenCache can implement to expose token expiry details
+// It is used by Client.TokenInfo
+type TokenSnapshotter interface {
+	Snapshot(ctx context.Context) (CachedTokens, error)
+}
+
 // FileTokenCache is a file-based implementation of TokenCache
+// It is safe for concurrent use by multiple goroutines
 type FileTokenCache struct {
+	mu       sync.Mutex
 	filePath string
 	tokens   *CachedTokens
+	clock    Clock
+	margin   time.Duration
+
+	// protect and unprotect, if set, transform the file contents on save and load (e.g. DPAPI encryption)
+	protect   func([]byte) ([]byte, error)
+	unprotect func([]byte) ([]byte, error)
 }
 
 // NewFileTokenCache creates a new file-based token cache in the default location (~/.xblive/tokens.json)
 func NewFileTokenCache() (*FileTokenCache, error) {
-	homeDir, err := os.UserHomeDir()
+	cacheDir, err := DefaultCacheDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	cacheDir := filepath.Join(homeDir, ".xblive")
 	filePath := filepath.Join(cacheDir, "tokens.json")
 	return NewFileTokenCacheWithPath(filePath)
 }
 
+// DefaultCacheDir returns the default token cache directory (~/.xblive)
+func DefaultCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".xblive"), nil
+}
+
 // NewFileTokenCacheWithPath creates a new file-based token cache at a custom path
 func NewFileTokenCacheWithPath(filePath string) (*FileTokenCache, error) {
+	return newFileTokenCache(filePath, nil, nil)
+}
+
+// newFileTokenCache creates a file-based token cache whose contents are optionally transformed on save and load
+func newFileTokenCache(filePath string, protect func([]byte) ([]byte, error), unprotect func([]byte) ([]byte, error)) (*FileTokenCache, error) {
 	cacheDir := filepath.Dir(filePath)
 	if err := os.MkdirAll(cacheDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
@@ -50,6 +103,11 @@ func NewFileTokenCacheWithPath(filePath string) (*FileTokenCache, error) {
 	cache := &FileTokenCache{
 		filePath: filePath,
 		tokens:   &CachedTokens{},
+		clock:    SystemClock,
+		margin:   DefaultExpirySkew,
+
+		protect:   protect,
+		unprotect: unprotect,
 	}
 
 	// Try to load existing tokens
@@ -58,6 +116,22 @@ func NewFileTokenCacheWithPath(filePath string) (*FileTokenCache, error) {
 	return cache, nil
 }
 
+// SetClock sets the clock used for expiry checks
+func (c *FileTokenCache) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock
+}
+
+// SetExpiryMargin sets how long before their actual expiry cached tokens are treated as expired
+func (c *FileTokenCache) SetExpiryMargin(margin time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.margin = margin
+}
+
 // load reads tokens from disk
 func (c *FileTokenCache) load() error {
 	data, err := os.ReadFile(c.filePath)
@@ -68,6 +142,13 @@ func (c *FileTokenCache) load() error {
 		return fmt.Errorf("failed to read token cache: %w", err)
 	}
 
+	if c.unprotect != nil {
+		data, err = c.unprotect(data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt token cache: %w", err)
+		}
+	}
+
 	if err := json.Unmarshal(data, c.tokens); err != nil {
 		return fmt.Errorf("failed to parse token cache: %w", err)
 	}
@@ -82,6 +163,13 @@ func (c *FileTokenCache) save() error {
 		return fmt.Errorf("failed to marshal tokens: %w", err)
 	}
 
+	if c.protect != nil {
+		data, err = c.protect(data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt token cache: %w", err)
+		}
+	}
+
 	if err := os.WriteFile(c.filePath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write token cache: %w", err)
 	}
@@ -91,10 +179,13 @@ func (c *FileTokenCache) save() error {
 
 // GetAccessToken returns the cached access token if valid
 func (c *FileTokenCache) GetAccessToken(ctx context.Context) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.tokens.AccessToken == "" {
 		return "", false
 	}
-	if time.Now().After(c.tokens.AccessTokenExpiry) {
+	if tokenExpired(c.clock.Now(), c.tokens.AccessTokenExpiry, c.margin) {
 		return "", false
 	}
 	return c.tokens.AccessToken, true
@@ -102,6 +193,9 @@ func (c *FileTokenCache) GetAccessToken(ctx context.Context) (string, bool) {
 
 // GetRefreshToken returns the cached refresh token
 func (c *FileTokenCache) GetRefreshToken(ctx context.Context) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.tokens.RefreshToken == "" {
 		return "", false
 	}
@@ -110,10 +204,13 @@ func (c *FileTokenCache) GetRefreshToken(ctx context.Context) (string, bool) {
 
 // GetUserToken returns the cached user token if valid
 func (c *FileTokenCache) GetUserToken(ctx context.Context) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.tokens.UserToken == "" {
 		return "", false
 	}
-	if time.Now().After(c.tokens.UserTokenExpiry) {
+	if tokenExpired(c.clock.Now(), c.tokens.UserTokenExpiry, c.margin) {
 		return "", false
 	}
 	return c.tokens.UserToken, true
@@ -121,10 +218,13 @@ func (c *FileTokenCache) GetUserToken(ctx context.Context) (string, bool) {
 
 // GetXSTSToken returns the cached XSTS token and user hash if valid
 func (c *FileTokenCache) GetXSTSToken(ctx context.Context) (token string, userHash string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.tokens.XSTSToken == "" || c.tokens.UserHash == "" {
 		return "", "", false
 	}
-	if time.Now().After(c.tokens.XSTSTokenExpiry) {
+	if tokenExpired(c.clock.Now(), c.tokens.XSTSTokenExpiry, c.margin) {
 		return "", "", false
 	}
 	return c.tokens.XSTSToken, c.tokens.UserHash, true
@@ -132,6 +232,9 @@ func (c *FileTokenCache) GetXSTSToken(ctx context.Context) (token string, userHa
 
 // SetAccessToken stores the access token
 func (c *FileTokenCache) SetAccessToken(ctx context.Context, token string, notAfter time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.tokens.AccessToken = token
 	c.tokens.AccessTokenExpiry = notAfter
 	return c.save()
@@ -139,12 +242,19 @@ func (c *FileTokenCache) SetAccessToken(ctx context.Context, token string, notAf
 
 // SetRefreshToken stores the refresh token
 func (c *FileTokenCache) SetRefreshToken(ctx context.Context, token string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.tokens.RefreshToken = token
+	c.tokens.RefreshTokenIssued = c.clock.Now()
 	return c.save()
 }
 
 // SetUserToken stores the user token
 func (c *FileTokenCache) SetUserToken(ctx context.Context, token string, notAfter time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.tokens.UserToken = token
 	c.tokens.UserTokenExpiry = notAfter
 	return c.save()
@@ -152,14 +262,72 @@ func (c *FileTokenCache) SetUserToken(ctx context.Context, token string, notAfte
 
 // SetXSTSToken stores the XSTS token and user hash
 func (c *FileTokenCache) SetXSTSToken(ctx context.Context, token string, userHash string, notAfter time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.tokens.XSTSToken = token
 	c.tokens.UserHash = userHash
 	c.tokens.XSTSTokenExpiry = notAfter
 	return c.save()
 }
 
+// GetXSTSTokenFor returns the cached XSTS token and user hash for an audience if valid
+func (c *FileTokenCache) GetXSTSTokenFor(ctx context.Context, audience XSTSAudience) (token string, userHash string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, found := c.tokens.AudienceXSTSTokens[audience.Key()]
+	if !found || cached.Token == "" || cached.UserHash == "" {
+		return "", "", false
+	}
+	if tokenExpired(c.clock.Now(), cached.Expiry, c.margin) {
+		return "", "", false
+	}
+	return cached.Token, cached.UserHash, true
+}
+
+// SetXSTSTokenFor stores the XSTS token and user hash for an audience
+func (c *FileTokenCache) SetXSTSTokenFor(ctx context.Context, audience XSTSAudience, token string, userHash string, notAfter time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokens.AudienceXSTSTokens == nil {
+		c.tokens.AudienceXSTSTokens = make(map[string]*CachedXSTSToken)
+	}
+	c.tokens.AudienceXSTSTokens[audience.Key()] = &CachedXSTSToken{
+		Token:    token,
+		Expiry:   notAfter,
+		UserHash: userHash,
+	}
+	return c.save()
+}
+
+// Snapshot returns a copy of the cached tokens
+func (c *FileTokenCache) Snapshot(ctx context.Context) (CachedTokens, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tokens.clone(), nil
+}
+
+// clone returns a deep copy of the tokens, so the audience tokens aren't shared
+func (t *CachedTokens) clone() CachedTokens {
+	tokens := *t
+	if t.AudienceXSTSTokens != nil {
+		tokens.AudienceXSTSTokens = make(map[string]*CachedXSTSToken, len(t.AudienceXSTSTokens))
+		for k, v := range t.AudienceXSTSTokens {
+			cached := *v
+			tokens.AudienceXSTSTokens[k] = &cached
+		}
+	}
+	return tokens
+}
+
 // Clear removes all cached tokens
 func (c *FileTokenCache) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.tokens = &CachedTokens{}
 	if err := os.Remove(c.filePath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove token cache: %w", err)
@@ -0,0 +1,11 @@
+//go:build !js
+
+package xblive
+
+// defaultTokenCache returns the file-based token cache used when Config.Cache is nil
+func defaultTokenCache(config Config) (TokenCache, error) {
+	if config.CachePath != "" {
+		return NewFileTokenCacheWithPath(config.CachePath)
+	}
+	return NewFileTokenCache()
+}
@@ -0,0 +1,95 @@
+//go:build windows
+
+package xblive
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	crypt32                = syscall.NewLazyDLL("crypt32.dll")
+	procCryptProtectData   = crypt32.NewProc("CryptProtectData")
+	procCryptUnprotectData = crypt32.NewProc("CryptUnprotectData")
+
+	kernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLocalFree = kernel32.NewProc("LocalFree")
+)
+
+// cryptProtectUIForbidden fails rather than prompting when DPAPI would need user interaction
+const cryptProtectUIForbidden = 0x1
+
+// DPAPITokenCache is a file-based token cache whose file is encrypted with the Windows Data Protection API
+// Only the Windows user that wrote the file can decrypt it. It is a middle ground between a plaintext file
+// and a full keyring integration, and is safe for concurrent use by multiple goroutines
+type DPAPITokenCache struct {
+	*FileTokenCache
+}
+
+// NewDPAPITokenCache creates a DPAPI-protected token cache in the default location (~/.xblive/tokens.dpapi)
+func NewDPAPITokenCache() (*DPAPITokenCache, error) {
+	cacheDir, err := DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDPAPITokenCacheWithPath(filepath.Join(cacheDir, "tokens.dpapi"))
+}
+
+// NewDPAPITokenCacheWithPath creates a DPAPI-protected token cache at a custom path
+func NewDPAPITokenCacheWithPath(filePath string) (*DPAPITokenCache, error) {
+	cache, err := newFileTokenCache(filePath, dpapiProtect, dpapiUnprotect)
+	if err != nil {
+		return nil, err
+	}
+	return &DPAPITokenCache{FileTokenCache: cache}, nil
+}
+
+// dataBlob is the Win32 DATA_BLOB structure
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+// newDataBlob wraps a byte slice in a DATA_BLOB
+func newDataBlob(data []byte) *dataBlob {
+	if len(data) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{cbData: uint32(len(data)), pbData: &data[0]}
+}
+
+// bytes copies the blob's contents and frees the memory DPAPI allocated for it
+func (b *dataBlob) bytes() []byte {
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(b.pbData)))
+
+	out := make([]byte, b.cbData)
+	copy(out, unsafe.Slice(b.pbData, b.cbData))
+	return out
+}
+
+// dpapiProtect encrypts data for the current Windows user
+func dpapiProtect(data []byte) ([]byte, error) {
+	var out dataBlob
+	r, _, err := procCryptProtectData.Call(
+		uintptr(unsafe.Pointer(newDataBlob(data))), 0, 0, 0, 0,
+		cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return nil, fmt.Errorf("CryptProtectData failed: %w", err)
+	}
+	return out.bytes(), nil
+}
+
+// dpapiUnprotect decrypts data encrypted by dpapiProtect
+func dpapiUnprotect(data []byte) ([]byte, error) {
+	var out dataBlob
+	r, _, err := procCryptUnprotectData.Call(
+		uintptr(unsafe.Pointer(newDataBlob(data))), 0, 0, 0, 0,
+		cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return nil, fmt.Errorf("CryptUnprotectData failed: %w", err)
+	}
+	return out.bytes(), nil
+}
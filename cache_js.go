@@ -0,0 +1,11 @@
+//go:build js
+
+package xblive
+
+import "fmt"
+
+// defaultTokenCache fails on js/wasm, where there is no file system to keep tokens in
+// Browser builds must set Config.Cache, e.g. to a MemoryTokenCache or a cache backed by localStorage
+func defaultTokenCache(config Config) (TokenCache, error) {
+	return nil, fmt.Errorf("a token cache is required on js/wasm: set Config.Cache")
+}
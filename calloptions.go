@@ -0,0 +1,121 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CallOptions overrides client settings for the calls made with a context; see WithCallOptions
+// Unset fields keep the client's settings, so one client can serve interactive and bulk work side by side
+type CallOptions struct {
+	// Profile applies a profile's timeout and retry policy in place of the client's (optional)
+	Profile TuningProfile
+
+	// Timeout bounds each Xbox Live API request, overriding the profile's (optional)
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a temporarily failing read is retried, overriding the profile's (optional)
+	// A negative value disables retries
+	MaxRetries int
+
+	// Language is the language tag sent as Accept-Language and used for XErr explanations,
+	// overriding Config.Language (optional)
+	Language string
+
+	// Sandbox is the sandbox the calls are authorized for, e.g. a development sandbox (optional, defaults to RETAIL)
+	// Each sandbox has its own XSTS token, obtained on first use
+	Sandbox string
+}
+
+// callOptionsKey is the context key for per-call option overrides
+type callOptionsKey struct{}
+
+// WithCallOptions returns a context whose calls use the given overrides
+// Options already on ctx are kept unless opts sets the same field, so overrides can be layered
+func WithCallOptions(ctx context.Context, opts CallOptions) context.Context {
+	merged := CallOptionsFromContext(ctx)
+	if opts.Profile != "" {
+		merged.Profile = opts.Profile
+	}
+	if opts.Timeout != 0 {
+		merged.Timeout = opts.Timeout
+	}
+	if opts.MaxRetries != 0 {
+		merged.MaxRetries = opts.MaxRetries
+	}
+	if opts.Language != "" {
+		merged.Language = opts.Language
+	}
+	if opts.Sandbox != "" {
+		merged.Sandbox = opts.Sandbox
+	}
+	return context.WithValue(ctx, callOptionsKey{}, merged)
+}
+
+// CallOptionsFromContext returns the overrides set on a context by WithCallOptions
+func CallOptionsFromContext(ctx context.Context) CallOptions {
+	opts, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+	return opts
+}
+
+// validate checks the overrides
+func (o CallOptions) validate() error {
+	if o.Profile != "" {
+		if _, ok := o.Profile.Tuning(); !ok {
+			return fmt.Errorf("unknown profile %q in call options", o.Profile)
+		}
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("call timeout must not be negative")
+	}
+	if o.Language != "" && !languagePattern.MatchString(o.Language) {
+		return fmt.Errorf("call language %q is not a language tag such as en or pt-BR", o.Language)
+	}
+	return nil
+}
+
+// callTuning returns the client's tuning with the context's overrides applied
+func (c *Client) callTuning(opts CallOptions) Tuning {
+	tuning := c.tuning
+	if t, ok := opts.Profile.Tuning(); ok {
+		tuning = t
+	}
+	if opts.Timeout > 0 {
+		tuning.Timeout = opts.Timeout
+	}
+	if opts.MaxRetries != 0 {
+		tuning.MaxRetries = max(opts.MaxRetries, 0)
+	}
+	return tuning
+}
+
+// callHTTPClient returns the HTTP client for a call, sharing the client's transport but with the call's timeout
+func (c *Client) callHTTPClient(timeout time.Duration) *http.Client {
+	if timeout == c.httpClient.Timeout {
+		return c.httpClient
+	}
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	return &httpClient
+}
+
+// callAudience returns the XSTS audience for a call's sandbox
+func callAudience(opts CallOptions) XSTSAudience {
+	audience := DefaultAudience
+	if opts.Sandbox != "" {
+		audience.SandboxID = opts.Sandbox
+	}
+	return audience
+}
+
+// localizerFor returns the localizer for calls made with a context
+// A call language other than the client's uses the catalogs alone, without Config.Messages
+func (c *Client) localizerFor(ctx context.Context) localizer {
+	language := CallOptionsFromContext(ctx).Language
+	if language == "" || language == c.localizer.language {
+		return c.localizer
+	}
+	return localizer{language: language}
+}
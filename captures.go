@@ -0,0 +1,57 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Capture metadata endpoints
+	screenshotsEndpoint = "https://screenshotsmetadata.xboxlive.com/users"
+	gameClipsEndpoint   = "https://gameclipsmetadata.xboxlive.com/users"
+)
+
+// GetScreenshots returns one page of a user's screenshot metadata
+// Returns the screenshots and the continuation token for the next page (empty when there are no more)
+func (c *Client) GetScreenshots(ctx context.Context, xuid string, opts PageOptions) ([]*Screenshot, string, error) {
+	if xuid == "" {
+		return nil, "", fmt.Errorf("XUID is required")
+	}
+
+	params, err := opts.query("maxItems", "continuationToken")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/xuid(%s)/screenshots?%s", screenshotsEndpoint, url.PathEscape(xuid), params.Encode())
+
+	var resp ScreenshotsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceScreenshots, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get screenshots: %w", err)
+	}
+
+	return resp.Screenshots, resp.PagingInfo.ContinuationToken, nil
+}
+
+// GetGameClips returns one page of a user's game clip metadata
+// Returns the clips and the continuation token for the next page (empty when there are no more)
+func (c *Client) GetGameClips(ctx context.Context, xuid string, opts PageOptions) ([]*GameClip, string, error) {
+	if xuid == "" {
+		return nil, "", fmt.Errorf("XUID is required")
+	}
+
+	params, err := opts.query("maxItems", "continuationToken")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/xuid(%s)/clips?%s", gameClipsEndpoint, url.PathEscape(xuid), params.Encode())
+
+	var resp GameClipsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceGameClips, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get game clips: %w", err)
+	}
+
+	return resp.GameClips, resp.PagingInfo.ContinuationToken, nil
+}
@@ -0,0 +1,120 @@
+package xblive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLanguage is the language of the built-in messages and the fallback for missing translations
+const DefaultLanguage = "en"
+
+// MessageID identifies a user-facing message in a Catalog
+type MessageID string
+
+// User-facing messages shown during authentication
+const (
+	MsgSignInOpenPage    MessageID = "signin.open_page"    // followed by the verification URL
+	MsgSignInEnterCode   MessageID = "signin.enter_code"   // followed by the user code
+	MsgSignInSuccess     MessageID = "signin.success"      // shown once the device code flow completes
+	MsgDeviceCodeExpired MessageID = "signin.code_expired" // error when the user doesn't finish signing in in time
+
+	MsgXErrAccountBanned           MessageID = "xerr.account_banned"
+	MsgXErrParentalRestriction     MessageID = "xerr.parental_restriction"
+	MsgXErrNoXboxAccount           MessageID = "xerr.no_xbox_account"
+	MsgXErrTermsNotAccepted        MessageID = "xerr.terms_not_accepted"
+	MsgXErrRegionBlocked           MessageID = "xerr.region_blocked"
+	MsgXErrAdultVerificationNeeded MessageID = "xerr.adult_verification_needed"
+	MsgXErrChildAccount            MessageID = "xerr.child_account"
+)
+
+// Catalog maps message IDs to the text shown to users in one language
+type Catalog map[MessageID]string
+
+// Catalogs holds the built-in message catalogs by language tag (e.g. "en", "de", "pt-BR")
+// Register translations here at init, or pass them per client with Config.Messages
+var Catalogs = map[string]Catalog{
+	DefaultLanguage: englishCatalog,
+}
+
+// englishCatalog is the built-in English text of every message
+var englishCatalog = Catalog{
+	MsgSignInOpenPage:    "To sign in, use a web browser to open the page:",
+	MsgSignInEnterCode:   "And enter the code:",
+	MsgSignInSuccess:     "Authentication successful!",
+	MsgDeviceCodeExpired: "device code expired",
+
+	MsgXErrAccountBanned:           "the account is banned from Xbox Live for violating the Community Standards",
+	MsgXErrParentalRestriction:     "the account is restricted and a guardian has not given permission to play online. A guardian can change this at https://account.microsoft.com/family/",
+	MsgXErrNoXboxAccount:           "no Xbox account found: the Microsoft account you authenticated with doesn't have an Xbox Live profile. Create one at https://www.xbox.com/",
+	MsgXErrTermsNotAccepted:        "the account has not accepted the Xbox Terms of Use. Sign in at https://www.xbox.com/ to accept them",
+	MsgXErrRegionBlocked:           "Xbox Live is not available in your country/region",
+	MsgXErrAdultVerificationNeeded: "the account needs adult verification. Please verify your account at https://account.microsoft.com/",
+	MsgXErrChildAccount:            "the account is a child account and cannot proceed unless the parent consents",
+}
+
+// xerrMessages maps known XErr codes to their description messages
+var xerrMessages = map[XErr]MessageID{
+	XErrAccountBanned:           MsgXErrAccountBanned,
+	XErrParentalRestriction:     MsgXErrParentalRestriction,
+	XErrNoXboxAccount:           MsgXErrNoXboxAccount,
+	XErrTermsNotAccepted:        MsgXErrTermsNotAccepted,
+	XErrRegionBlocked:           MsgXErrRegionBlocked,
+	XErrAdultVerificationNeeded: MsgXErrAdultVerificationNeeded,
+	XErrAgeVerificationNeeded:   MsgXErrAdultVerificationNeeded,
+	XErrChildAccount:            MsgXErrChildAccount,
+}
+
+// localizer looks up messages for a language, preferring per-client overrides
+type localizer struct {
+	language  string
+	overrides Catalog
+}
+
+// message returns the text of a message, formatted with args if any
+// Lookup order: the overrides, the catalog for the exact language, the catalog for its base language ("pt" for "pt-BR"), then English
+func (l localizer) message(id MessageID, args ...interface{}) string {
+	text, ok := l.overrides[id]
+	if !ok {
+		text, ok = lookupMessage(l.language, id)
+	}
+	if !ok {
+		text = englishCatalog[id]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// lookupMessage finds a message in the catalog for a language or its base language
+func lookupMessage(language string, id MessageID) (string, bool) {
+	if language == "" {
+		return "", false
+	}
+	if text, ok := Catalogs[language][id]; ok {
+		return text, true
+	}
+	base, _, found := strings.Cut(language, "-")
+	if !found {
+		return "", false
+	}
+	text, ok := Catalogs[base][id]
+	return text, ok
+}
+
+// xerrDescription returns the explanation of a known XErr code, or an empty string
+func (l localizer) xerrDescription(x XErr) string {
+	id, ok := xerrMessages[x]
+	if !ok {
+		return ""
+	}
+	return l.message(id)
+}
+
+// acceptLanguage returns the Accept-Language header value for the language
+func (l localizer) acceptLanguage() string {
+	if l.language == "" {
+		return "en-us"
+	}
+	return l.language
+}
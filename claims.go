@@ -0,0 +1,111 @@
+package xblive
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// XSTSClaims is the identity and entitlement data Xbox Live returns alongside an XSTS token
+type XSTSClaims struct {
+	XUID       string      `json:"xuid"`
+	Gamertag   string      `json:"gamertag"`
+	UserHash   string      `json:"userHash"`
+	AgeGroup   string      `json:"ageGroup"`
+	Privileges []Privilege `json:"privileges"`
+
+	// Raw holds every display claim as returned by the service
+	Raw map[string]interface{} `json:"raw"`
+}
+
+// Identity returns the authenticated user's XSTS claims (XUID, gamertag, age group, privileges)
+// Claims are remembered from the most recent XSTS exchange; if none has happened in this process, a new XSTS token is obtained
+func (c *Client) Identity(ctx context.Context) (*XSTSClaims, error) {
+	if claims := c.getClaims(); claims != nil {
+		return claims, nil
+	}
+
+	if _, _, err := c.fetchXSTSToken(ctx, DefaultAudience); err != nil {
+		return nil, err
+	}
+
+	claims := c.getClaims()
+	if claims == nil {
+		return nil, fmt.Errorf("XSTS response contained no display claims")
+	}
+	return claims, nil
+}
+
+// getClaims returns the remembered XSTS claims, or nil
+func (c *Client) getClaims() *XSTSClaims {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.claims
+}
+
+// setClaims remembers XSTS claims; nil claims are ignored
+func (c *Client) setClaims(claims *XSTSClaims) {
+	if claims == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claims = claims
+}
+
+// parseXSTSClaims converts XSTS display claims into typed claims, or nil if there are none
+func parseXSTSClaims(claims XSTSTokenDisplayClaims) *XSTSClaims {
+	if len(claims.Xui) == 0 {
+		return nil
+	}
+
+	xui := claims.Xui[0]
+	str := func(key string) string {
+		v, _ := xui[key].(string)
+		return v
+	}
+
+	parsed := &XSTSClaims{
+		XUID:     str("xid"),
+		Gamertag: str("gtg"),
+		UserHash: str("uhs"),
+		AgeGroup: str("agg"),
+		Raw:      xui,
+	}
+	for _, field := range strings.Fields(str("prv")) {
+		if p, err := strconv.Atoi(field); err == nil {
+			parsed.Privileges = append(parsed.Privileges, Privilege(p))
+		}
+	}
+
+	return parsed
+}
+
+// DecodeJWTClaims decodes (without verifying) the claims of a signed JWT
+// Xbox Live user and XSTS tokens for xboxlive.com are encrypted and can't be decoded; use Identity instead.
+// Tokens issued for some other relying parties are plain signed JWTs
+func DecodeJWTClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	switch len(parts) {
+	case 3:
+	case 5:
+		return nil, fmt.Errorf("token is encrypted (JWE) and cannot be decoded")
+	default:
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT payload: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse JWT claims: %w", err)
+	}
+
+	return claims, nil
+}
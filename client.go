@@ -2,56 +2,252 @@ package xblive
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
 var ErrNotFound = errors.New("not found")
+var ErrForbidden = errors.New("forbidden")
+var ErrConflict = errors.New("conflict")
 
 // Config contains configuration for the Xbox Live client
 type Config struct {
 	// ClientID is your Microsoft Entra ID application client ID (required)
 	ClientID string
 
+	// Tenant is the Microsoft Entra ID tenant to authenticate against (optional)
+	// If empty, defaults to DefaultTenant
+	Tenant string
+
+	// RedirectURI is the redirect URI registered for the application (optional)
+	// Required only for the authorization code flow (GenerateAuthURL / ExchangeCode)
+	RedirectURI string
+
 	// Cache is the token cache implementation to use (optional)
-	// If nil, defaults to file-based cache at ~/.xblive/tokens.json
+	// If nil, defaults to file-based cache at ~/.xblive/tokens.json. Required on js/wasm
 	Cache TokenCache
+
+	// CachePath is the path of the token cache file used by the default file-based cache (optional)
+	// If empty, defaults to ~/.xblive/tokens.json. Ignored when Cache is set
+	CachePath string
+
+	// CacheScope namespaces this client's tokens within a shared cache (optional)
+	// Every cache call carries the scope in its context; see TokenCacheScopeFromContext
+	CacheScope TokenCacheScope
+
+	// Audit receives a record of every mutating call (optional)
+	Audit AuditSink
+
+	// AliasStore records the gamertag history of users resolved by XUID (optional)
+	// If nil and OnGamertagChanged is set, an in-memory store is used
+	AliasStore AliasStore
+
+	// OnGamertagChanged is called when a resolved XUID has a different gamertag than previously recorded (optional)
+	OnGamertagChanged GamertagChangedFunc
+
+	// Logger receives diagnostic logging (optional)
+	// If nil, logging is discarded
+	Logger *slog.Logger
+
+	// Clock is the source of time for token expiry checks and refresh scheduling (optional)
+	// If nil, defaults to SystemClock. It is also applied to the cache if the cache has a SetClock method
+	Clock Clock
+
+	// ExpiryMargin is how long before their actual expiry cached tokens are treated as expired (optional)
+	// If zero, defaults to DefaultExpirySkew; a negative value disables the margin
+	// It is applied to the cache if the cache has a SetExpiryMargin method
+	ExpiryMargin time.Duration
+
+	// HTTPCache enables conditional requests for GET endpoints that return ETags (optional)
+	// Cached responses are revalidated with If-None-Match, and a 304 reuses the cached body
+	HTTPCache HTTPCache
+
+	// ContractVersions overrides the x-xbl-contract-version sent to individual services (optional)
+	// Services not listed use DefaultContractVersions
+	ContractVersions map[Service]string
+
+	// StrictDecode fails requests whose response contains fields the result type doesn't know, with a *SchemaDriftError (optional)
+	// Use it in tests and canaries to detect Xbox Live adding or renaming fields
+	StrictDecode bool
+
+	// OnUnknownFields is called with the unknown fields of each response, without failing the request (optional)
+	OnUnknownFields UnknownFieldsFunc
+
+	// Failover configures fallback addresses or pinned IPs for hosts with unreliable DNS, such as the auth endpoints (optional)
+	Failover *EndpointFailover
+
+	// AppName and AppVersion identify the application in the User-Agent header of every request (optional)
+	// Upstream service logs can then attribute traffic to your app, e.g. "MyBot/1.2 xblive-go"
+	AppName    string
+	AppVersion string
+
+	// Transport performs HTTP requests (optional, defaults to http.DefaultTransport)
+	// Browser builds typically set a ProxyTransport pointing at a CORS proxy. Ignored when Failover is set
+	Transport http.RoundTripper
+
+	// DryRun makes every write API (friends, invites, parties, gamerpics, ...) log the request it would send
+	// instead of sending it, and report success (optional)
+	// Reads are still sent. Audit events for dry-run operations have DryRun set
+	DryRun bool
+
+	// Quota caps the calls made per service per time window (optional)
+	// Calls over the cap fail locally with ErrQuotaExceeded instead of being sent
+	Quota *Quota
+
+	// Profile selects a preset of timeouts, retries, crawl concurrency, and rate limits (optional)
+	// ProfileInteractive, ProfileBulk, or ProfileServer; if empty, DefaultTuning is used. Quota, if set, replaces the profile's quota
+	Profile TuningProfile
+
+	// Tuning sets the timeouts, retries, crawl concurrency, and rate limits directly instead of Profile (optional)
+	// Start from a profile's settings with ProfileBulk.Tuning() and adjust them
+	Tuning *Tuning
+
+	// Language is the language tag of user-facing text such as sign-in prompts and XErr explanations,
+	// e.g. "de" or "pt-BR" (optional, defaults to English)
+	// It is also sent as Accept-Language so localized fields like achievement names come back in that language
+	Language string
+
+	// Messages overrides individual messages of the catalog for Language (optional)
+	// Missing messages fall back to Catalogs[Language], then its base language, then English
+	Messages Catalog
 }
 
 // Client is the main Xbox Live API client
+// A Client is safe for concurrent use by multiple goroutines
 type Client struct {
-	clientID   string
-	httpClient *http.Client
-	cache      TokenCache
+	clientID    string
+	tenant      string
+	redirectURI string
+	httpClient  *http.Client
+	cache       TokenCache
+	httpCache   HTTPCache
+	audit       AuditSink
+	logger      *slog.Logger
+	clock       Clock
+
+	contractVersions map[Service]string
+	strictDecode     bool
+	onUnknownFields  UnknownFieldsFunc
+	dryRun           bool
+	quota            *Quota
+	tuning           Tuning
+	localizer        localizer
+
+	aliases           AliasStore
+	onGamertagChanged GamertagChangedFunc
+
+	// tokenMu serializes token chain acquisition and refresh
+	tokenMu sync.Mutex
+
+	mu     sync.Mutex
+	claims *XSTSClaims
+
+	// sharedHTTPClient is set for ClientPool clients, whose HTTP client outlives them
+	sharedHTTPClient bool
+
+	// closeCtx is cancelled by Shutdown; background loops are tracked in background, and flushers are flushed on shutdown
+	closeCtx    context.Context
+	closeCancel context.CancelFunc
+	background  sync.WaitGroup
+	flushers    []Flusher
 }
 
 // New creates a new Xbox Live client
 func New(config Config) (*Client, error) {
-	if config.ClientID == "" {
-		return nil, fmt.Errorf("client ID is required")
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	// Use provided cache or default to file cache
 	cache := config.Cache
 	if cache == nil {
 		var err error
-		cache, err = NewFileTokenCache()
+		cache, err = defaultTokenCache(config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize token cache: %w", err)
 		}
 	}
 
-	return &Client{
-		clientID:   config.ClientID,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		cache:      cache,
-	}, nil
+	return newClient(config, cache, newHTTPClient(config)), nil
+}
+
+// newClient builds a client from a validated config, a resolved cache, and an HTTP client
+func newClient(config Config, cache TokenCache, httpClient *http.Client) *Client {
+	baseCache := cache
+	if config.CacheScope != "" {
+		cache = &scopedTokenCache{cache: cache, scope: config.CacheScope}
+	}
+
+	tenant := config.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	clock := config.Clock
+	if clock == nil {
+		clock = SystemClock
+	} else if cs, ok := cache.(clockSetter); ok {
+		cs.SetClock(clock)
+	}
+
+	if config.ExpiryMargin != 0 {
+		if ms, ok := cache.(expiryMarginSetter); ok {
+			ms.SetExpiryMargin(max(config.ExpiryMargin, 0))
+		}
+	}
+
+	aliases := config.AliasStore
+	if aliases == nil && config.OnGamertagChanged != nil {
+		aliases = NewMemoryAliasStore()
+	}
+
+	c := &Client{
+		clientID:    config.ClientID,
+		tenant:      tenant,
+		redirectURI: config.RedirectURI,
+		httpClient:  httpClient,
+		cache:       cache,
+		httpCache:   config.HTTPCache,
+		audit:       config.Audit,
+		logger:      logger,
+		clock:       clock,
+
+		contractVersions: config.ContractVersions,
+		strictDecode:     config.StrictDecode,
+		onUnknownFields:  config.OnUnknownFields,
+		dryRun:           config.DryRun,
+		quota:            config.quota(),
+		tuning:           config.tuning(),
+		localizer:        localizer{language: config.Language, overrides: config.Messages},
+
+		aliases:           aliases,
+		onGamertagChanged: config.OnGamertagChanged,
+	}
+	c.closeCtx, c.closeCancel = context.WithCancel(context.Background())
+
+	if c.quota != nil && config.Clock != nil {
+		c.quota.SetClock(clock)
+	}
+
+	for _, component := range []interface{}{baseCache, config.HTTPCache, config.Audit, aliases} {
+		if component != nil {
+			c.addFlusher(component)
+		}
+	}
+
+	return c
 }
 
 // Authenticate performs the OAuth device code flow
@@ -60,6 +256,52 @@ func (c *Client) Authenticate(ctx context.Context) error {
 	return c.authenticateDeviceCode(ctx)
 }
 
+// AuthenticateWithRefreshToken authenticates without user interaction using a pre-provisioned refresh token
+// The full token chain is resolved and cached as if Authenticate had been called. Any tokens already cached, which
+// may belong to another account, are discarded first
+func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return fmt.Errorf("refresh token is required")
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	// Still-valid user and XSTS tokens of the previous account would otherwise be used until they expire
+	if err := c.cache.Clear(ctx); err != nil {
+		return fmt.Errorf("failed to clear cached tokens: %w", err)
+	}
+	c.mu.Lock()
+	c.claims = nil
+	c.mu.Unlock()
+
+	if err := c.cache.SetRefreshToken(ctx, refreshToken); err != nil {
+		return fmt.Errorf("failed to cache refresh token: %w", err)
+	}
+
+	if err := c.refreshAccessToken(ctx); err != nil {
+		return fmt.Errorf("failed to redeem refresh token: %w", err)
+	}
+
+	if _, _, err := c.fetchXSTSTokenLocked(ctx, DefaultAudience); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// XSTSToken returns a valid XSTS token and user hash for an audience, resolving the token chain if necessary
+// Use this to call services with their own relying party (e.g. Minecraft) using the authenticated account
+func (c *Client) XSTSToken(ctx context.Context, audience XSTSAudience) (token string, userHash string, err error) {
+	if audience.RelyingParty == "" {
+		return "", "", fmt.Errorf("relying party is required")
+	}
+	if audience.SandboxID == "" {
+		audience.SandboxID = DefaultAudience.SandboxID
+	}
+	return c.ensureXSTSTokenFor(ctx, audience)
+}
+
 // ClearCache clears all cached authentication tokens
 func (c *Client) ClearCache(ctx context.Context) error {
 	return c.cache.Clear(ctx)
@@ -106,20 +348,47 @@ func (c *Client) LookupProfileByGamertag(ctx context.Context, gamertag string) (
 }
 
 // GamertagsToXUIDs converts multiple gamertags to XUIDs (batch lookup)
-// Returns: map of gamertag -> XUID, list of gamertags with no exact match, error
+// Repeated gamertags, including case and whitespace variants, are looked up once; exact matches are keyed by
+// every input form that matched, and fuzzy results by the found gamertag
+// Returns: map of gamertag -> XUID, list of input gamertags with no exact match, error
 func (c *Client) GamertagsToXUIDs(ctx context.Context, gamertags []string) (map[string]string, []string, error) {
 	if len(gamertags) == 0 {
 		return map[string]string{}, nil, nil
 	}
 
-	profiles, fuzzyOnly, err := c.searchGamertags(ctx, gamertags)
-	if err != nil {
-		return nil, nil, err
+	// Group the input forms by normalized gamertag, preserving first-seen order
+	var queries []string
+	forms := make(map[string][]string)
+	for _, gamertag := range gamertags {
+		key := normalizeGamertag(gamertag)
+		if _, ok := forms[key]; !ok {
+			queries = append(queries, gamertag)
+		}
+		if !slices.Contains(forms[key], gamertag) {
+			forms[key] = append(forms[key], gamertag)
+		}
 	}
 
 	result := make(map[string]string)
-	for _, profile := range profiles {
-		result[profile.Gamertag] = profile.XUID
+	var fuzzyOnly []string
+	for _, query := range queries {
+		profiles, exact, err := c.matchGamertag(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		inputs := forms[normalizeGamertag(query)]
+		if !exact {
+			for _, profile := range profiles {
+				result[profile.Gamertag] = profile.XUID
+			}
+			fuzzyOnly = append(fuzzyOnly, inputs...)
+			continue
+		}
+
+		for _, input := range inputs {
+			result[input] = profiles[0].XUID
+		}
 	}
 
 	return result, fuzzyOnly, nil
@@ -131,77 +400,68 @@ func (c *Client) GetProfile(ctx context.Context, xuid string) (*Profile, error)
 		return nil, fmt.Errorf("XUID is required")
 	}
 
-	// The search endpoint doesn't support XUID lookup directly
-	// We need to use the profile endpoint
-	// For now, return an error indicating this needs to be implemented
-	// In a real implementation, you would use:
-	// GET https://profile.xboxlive.com/users/xuid({xuid})/profile/settings
-	return nil, fmt.Errorf("GetProfile by XUID not yet implemented")
+	profiles, err := c.getPeopleByXUIDs(ctx, []string{xuid}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("%w: XUID '%s'", ErrNotFound, xuid)
+	}
+
+	return profiles[0], nil
 }
 
 // searchGamertags searches for gamertags and returns their profiles
 // Returns: profiles, list of gamertags with no exact/normalized match, error
 func (c *Client) searchGamertags(ctx context.Context, gamertags []string) ([]*Profile, []string, error) {
-	// Ensure we have a valid XSTS token
-	xstsToken, userHash, err := c.ensureXSTSToken(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	// The search endpoint accepts a single query, so we'll need to make multiple requests
 	// for true batch support. For now, we'll search for each gamertag individually
 	var allProfiles []*Profile
 	var fuzzyOnly []string
 
 	for _, gamertag := range gamertags {
-		// Try peoplehub endpoint for fuzzy matching
-		searchURL := fmt.Sprintf("https://peoplehub.xboxlive.com/users/me/people/search/decoration/detail?q=%s", url.QueryEscape(gamertag))
-
-		req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+		profiles, exact, err := c.matchGamertag(ctx, gamertag)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		// Set required headers
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("x-xbl-contract-version", "3")
-		req.Header.Set("Authorization", fmt.Sprintf("XBL3.0 x=%s;%s", userHash, xstsToken))
-		req.Header.Set("Accept-Language", "en-us")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, nil, fmt.Errorf("search request failed: %w", err)
+		allProfiles = append(allProfiles, profiles...)
+		if !exact {
+			fuzzyOnly = append(fuzzyOnly, gamertag)
 		}
+	}
 
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, nil, fmt.Errorf("search request failed: %s - %s", resp.Status, string(body))
-		}
+	return allProfiles, fuzzyOnly, nil
+}
 
-		var searchResp SearchResponse
-		if err := json.Unmarshal(body, &searchResp); err != nil {
-			return nil, nil, fmt.Errorf("failed to parse search response: %w", err)
-		}
+// matchGamertag searches for a gamertag
+// Returns: the profiles that match it exactly (ignoring case and whitespace), or all fuzzy results if none do; whether the match was exact; error
+func (c *Client) matchGamertag(ctx context.Context, gamertag string) ([]*Profile, bool, error) {
+	// Try peoplehub endpoint for fuzzy matching
+	searchResp, err := c.SearchPeople(ctx, gamertag, SearchOptions{})
+	if err != nil {
+		return nil, false, err
+	}
 
-		// If we find any matches only differ WRT the presence of whitespace, then return just those otherwise return all matches
-		normalizedQuery := strings.ReplaceAll(strings.ToLower(gamertag), " ", "")
-		matched := false
-		for _, profile := range searchResp.People {
-			normalizedGamertag := strings.ReplaceAll(strings.ToLower(profile.Gamertag), " ", "")
-			if normalizedGamertag == normalizedQuery {
-				allProfiles = append(allProfiles, profile)
-				matched = true
-			}
+	// If we find any matches only differ WRT the presence of whitespace, then return just those otherwise return all matches
+	normalizedQuery := normalizeGamertag(gamertag)
+	var matches []*Profile
+	for _, profile := range searchResp.People {
+		if normalizeGamertag(profile.Gamertag) == normalizedQuery {
+			matches = append(matches, profile)
 		}
+	}
 
-		if !matched {
-			// No exact match - return all fuzzy results
-			allProfiles = append(allProfiles, searchResp.People...)
-			fuzzyOnly = append(fuzzyOnly, gamertag)
-		}
+	if len(matches) == 0 {
+		// No exact match - return all fuzzy results
+		return searchResp.People, false, nil
 	}
 
-	return allProfiles, fuzzyOnly, nil
+	return matches, true, nil
+}
+
+// normalizeGamertag returns the form of a gamertag used to compare gamertags: folded (see partialFoldGamertag) without spaces
+func normalizeGamertag(gamertag string) string {
+	return strings.ReplaceAll(partialFoldGamertag(strings.TrimSpace(gamertag)), " ", "")
 }
@@ -0,0 +1,42 @@
+package xblive
+
+import "time"
+
+// DefaultExpirySkew is how long before its actual expiry a cached token is treated as expired
+// Refreshing early tolerates clock skew and keeps tokens from expiring mid-request
+const DefaultExpirySkew = 5 * time.Minute
+
+// Clock is the source of time used for token expiry checks and refresh scheduling
+// Tests can supply their own implementation to simulate expiry without sleeping
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the system time
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockSetter is implemented by token caches and quotas that can use a custom Clock
+type clockSetter interface {
+	SetClock(clock Clock)
+}
+
+// expiryMarginSetter is implemented by token caches whose expiry safety margin can be configured
+type expiryMarginSetter interface {
+	SetExpiryMargin(margin time.Duration)
+}
+
+// tokenExpired reports whether a token expiring at expiry should be treated as expired at now, given a safety margin
+func tokenExpired(now time.Time, expiry time.Time, margin time.Duration) bool {
+	return !now.Add(margin).Before(expiry)
+}
@@ -0,0 +1,157 @@
+package xblive
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock that only moves when the test advances it
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// expiringCache is a token cache whose expiry checks can be driven by a test clock
+type expiringCache interface {
+	TokenCache
+	clockSetter
+	expiryMarginSetter
+}
+
+// newExpiringCaches returns each token cache implementation with expiry checks, for tests to run against
+func newExpiringCaches(t *testing.T) map[string]expiringCache {
+	t.Helper()
+
+	fileCache, err := NewFileTokenCacheWithPath(filepath.Join(t.TempDir(), "tokens.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]expiringCache{
+		"memory": NewMemoryTokenCache(),
+		"file":   fileCache,
+	}
+}
+
+// cachedTokenValidity reports which of the cache's expiring tokens it still serves
+func cachedTokenValidity(ctx context.Context, cache TokenCache) (access, user, xsts bool) {
+	_, access = cache.GetAccessToken(ctx)
+	_, user = cache.GetUserToken(ctx)
+	_, _, xsts = cache.GetXSTSToken(ctx)
+	return access, user, xsts
+}
+
+func TestTokenCacheExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		margin time.Duration // zero keeps DefaultExpirySkew
+		valid  time.Duration // last offset from the start at which tokens expiring after an hour are served
+	}{
+		{name: "default skew", valid: time.Hour - DefaultExpirySkew - time.Second},
+		{name: "custom margin", margin: time.Minute, valid: time.Hour - time.Minute - time.Second},
+		{name: "no margin", margin: -1, valid: time.Hour - time.Second},
+	}
+
+	for _, tt := range tests {
+		for name, cache := range newExpiringCaches(t) {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				ctx := context.Background()
+				start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				clock := &manualClock{now: start}
+				cache.SetClock(clock)
+				if tt.margin != 0 {
+					cache.SetExpiryMargin(max(tt.margin, 0))
+				}
+
+				notAfter := start.Add(time.Hour)
+				if err := cache.SetAccessToken(ctx, "access", notAfter); err != nil {
+					t.Fatal(err)
+				}
+				if err := cache.SetUserToken(ctx, "user", notAfter); err != nil {
+					t.Fatal(err)
+				}
+				if err := cache.SetXSTSToken(ctx, "xsts", "hash", notAfter); err != nil {
+					t.Fatal(err)
+				}
+
+				clock.now = start.Add(tt.valid)
+				if access, user, xsts := cachedTokenValidity(ctx, cache); !access || !user || !xsts {
+					t.Errorf("at +%s: access %v, user %v, XSTS %v; want all valid", tt.valid, access, user, xsts)
+				}
+
+				clock.now = clock.now.Add(time.Second)
+				if access, user, xsts := cachedTokenValidity(ctx, cache); access || user || xsts {
+					t.Errorf("at +%s: access %v, user %v, XSTS %v; want all expired", tt.valid+time.Second, access, user, xsts)
+				}
+			})
+		}
+	}
+}
+
+func TestConfigClockAndMarginReachCache(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	cache := NewMemoryTokenCache()
+	client, err := New(Config{
+		ClientID:     "00000000-0000-0000-0000-000000000000",
+		Cache:        cache,
+		Clock:        clock,
+		ExpiryMargin: 10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := cache.SetXSTSToken(ctx, "xsts", "hash", start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = start.Add(49 * time.Minute)
+	if _, _, ok := client.getCachedXSTSToken(ctx, DefaultAudience); !ok {
+		t.Error("XSTS token expired 11 minutes before NotAfter; want valid until 10 minutes before")
+	}
+	clock.now = start.Add(50 * time.Minute)
+	if _, _, ok := client.getCachedXSTSToken(ctx, DefaultAudience); ok {
+		t.Error("XSTS token served 10 minutes before NotAfter; want expired by the margin")
+	}
+}
+
+func TestGamertagAliasesUseClientClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	aliases := NewMemoryAliasStore()
+	client, err := New(Config{
+		ClientID:   "00000000-0000-0000-0000-000000000000",
+		Cache:      NewMemoryTokenCache(),
+		Clock:      clock,
+		AliasStore: aliases,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.trackAliases(ctx, map[string]string{"2533274792093503": "MajorNelson"}); err != nil {
+		t.Fatal(err)
+	}
+	history, err := aliases.History(ctx, "2533274792093503")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || !history[0].FirstSeen.Equal(clock.now) || !history[0].LastSeen.Equal(clock.now) {
+		t.Errorf("alias history = %+v; want one alias seen at %s", history, clock.now)
+	}
+}
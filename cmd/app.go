@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// xboxClient is the subset of *xblive.Client the commands use
+// Commands depend on it rather than the concrete client so they can be run against a fake
+type xboxClient interface {
+	Authenticate(ctx context.Context) error
+	ClearCache(ctx context.Context) error
+	TokenInfo(ctx context.Context) (*xblive.TokenInfo, error)
+	Identity(ctx context.Context) (*xblive.XSTSClaims, error)
+	LookupProfileByGamertag(ctx context.Context, gamertag string) (*xblive.Profile, error)
+	GamertagsToXUIDs(ctx context.Context, gamertags []string) (map[string]string, []string, error)
+	ResolveGamertags(ctx context.Context, xuids []string) (map[string]string, error)
+	GetProfile(ctx context.Context, xuid string) (*xblive.Profile, error)
+	FindPeople(ctx context.Context, keyword string, opts xblive.SearchOptions) ([]*xblive.PersonMatch, string, error)
+	GetFriends(ctx context.Context) ([]*xblive.Profile, error)
+	AddFriend(ctx context.Context, xuid string) error
+	RemoveFriend(ctx context.Context, xuid string) error
+	GetPresence(ctx context.Context, xuids []string) ([]*xblive.Presence, error)
+	SetPresenceVisibility(ctx context.Context, mode xblive.PresenceVisibility) error
+	GetAchievements(ctx context.Context, xuid string, opts xblive.PageOptions) ([]*xblive.Achievement, string, error)
+	GetRecentAchievements(ctx context.Context, xuid string, since time.Time) ([]*xblive.Achievement, error)
+	GetActivity(ctx context.Context, xuid string, opts xblive.PageOptions) ([]*xblive.ActivityItem, string, error)
+	GetScreenshots(ctx context.Context, xuid string, opts xblive.PageOptions) ([]*xblive.Screenshot, string, error)
+	GetGameClips(ctx context.Context, xuid string, opts xblive.PageOptions) ([]*xblive.GameClip, string, error)
+	GetConversations(ctx context.Context, opts xblive.PageOptions) ([]*xblive.Conversation, string, error)
+	GetConversationMessages(ctx context.Context, conversationID string, opts xblive.PageOptions) ([]*xblive.Message, string, error)
+	GetMessages(ctx context.Context, xuid string, opts xblive.PageOptions) ([]*xblive.Message, string, error)
+	ServiceStatus(ctx context.Context) (*xblive.ServiceStatus, error)
+	ExportSocialGraph(ctx context.Context, depth int) (*xblive.SocialGraph, error)
+}
+
+// app carries the dependencies of the commands: the client, where prompts are answered, and where output and errors go
+type app struct {
+	client xboxClient
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// out and errOut print status lines to stdout and stderr, colored only on a terminal
+	out    *printer
+	errOut *printer
+
+	// name is the program name shown in usage messages
+	name string
+
+	// exit terminates the command and must not return; a harness can substitute one that panics
+	exit func(code int)
+
+	// jsonErrors selects structured JSON error output (--json-errors)
+	jsonErrors bool
+}
+
+// newApp creates an app writing to the process's stdout and stderr
+func newApp(client xboxClient, g globalFlags) *app {
+	return &app{
+		client:     client,
+		stdin:      os.Stdin,
+		stdout:     os.Stdout,
+		stderr:     os.Stderr,
+		out:        newPrinter(os.Stdout, g.noColor),
+		errOut:     newPrinter(os.Stderr, g.noColor),
+		name:       os.Args[0],
+		exit:       os.Exit,
+		jsonErrors: g.jsonErrors,
+	}
+}
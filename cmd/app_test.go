@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tadhunt/xblive"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testTraceID is the trace ID of every test run, so error output is stable
+const testTraceID = "test-trace"
+
+// fakeClient serves canned data for the commands under test
+// Methods a test doesn't expect fall through to the nil embedded interface and panic
+type fakeClient struct {
+	xboxClient
+
+	profiles []*xblive.Profile
+	friends  []*xblive.Profile
+	people   []*xblive.PersonMatch
+}
+
+func newFakeClient() *fakeClient {
+	nelson := &xblive.Profile{
+		XUID:          "2533274792093503",
+		Gamertag:      "MajorNelson",
+		DisplayName:   "Major Nelson",
+		GamerScore:    "123456",
+		XboxOneRep:    xblive.ReputationGoodPlayer,
+		PresenceState: xblive.PresenceOnline,
+	}
+	larry := &xblive.Profile{
+		XUID:          "2535405290784567",
+		Gamertag:      "Larry Hryb",
+		GamerScore:    "42",
+		PresenceState: xblive.PresenceOffline,
+	}
+	return &fakeClient{
+		profiles: []*xblive.Profile{nelson, larry},
+		friends:  []*xblive.Profile{larry, nelson},
+		people: []*xblive.PersonMatch{
+			{Profile: nelson, Field: "gamertag", Score: 1},
+			{Profile: larry, Field: "realName", Score: 0},
+		},
+	}
+}
+
+func (f *fakeClient) LookupProfileByGamertag(ctx context.Context, gamertag string) (*xblive.Profile, error) {
+	for _, p := range f.profiles {
+		if xblive.GamertagsEqual(p.Gamertag, gamertag) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: gamertag '%s'", xblive.ErrNotFound, gamertag)
+}
+
+func (f *fakeClient) GetProfile(ctx context.Context, xuid string) (*xblive.Profile, error) {
+	for _, p := range f.profiles {
+		if p.XUID == xuid {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: xuid %s", xblive.ErrNotFound, xuid)
+}
+
+func (f *fakeClient) GamertagsToXUIDs(ctx context.Context, gamertags []string) (map[string]string, []string, error) {
+	results := make(map[string]string)
+	for _, gt := range gamertags {
+		if p, err := f.LookupProfileByGamertag(ctx, gt); err == nil {
+			results[gt] = p.XUID
+		}
+	}
+	return results, nil, nil
+}
+
+func (f *fakeClient) FindPeople(ctx context.Context, keyword string, opts xblive.SearchOptions) ([]*xblive.PersonMatch, string, error) {
+	return f.people[:min(len(f.people), opts.MaxItems)], "", nil
+}
+
+func (f *fakeClient) GetFriends(ctx context.Context) ([]*xblive.Profile, error) {
+	return f.friends, nil
+}
+
+// exitCode is the panic value of the test app's exit hook
+type exitCode int
+
+// runApp runs the CLI with args against client, returning what it wrote and its exit status
+func runApp(t *testing.T, client xboxClient, jsonErrors bool, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	a := &app{
+		client:     client,
+		stdin:      strings.NewReader(""),
+		stdout:     &out,
+		stderr:     &errOut,
+		out:        newPrinter(&out, true),
+		errOut:     newPrinter(&errOut, true),
+		name:       "xblive",
+		exit:       func(code int) { panic(exitCode(code)) },
+		jsonErrors: jsonErrors,
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c, ok := r.(exitCode)
+				if !ok {
+					panic(r)
+				}
+				code = int(c)
+			}
+		}()
+		a.run(xblive.WithTraceID(context.Background(), testTraceID), args)
+	}()
+
+	return out.String(), errOut.String(), code
+}
+
+func TestCommandOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		jsonErrors bool
+	}{
+		{name: "help", args: []string{"help"}},
+		{name: "help_lookup", args: []string{"help", "lookup"}},
+		{name: "unknown_command", args: []string{"bogus"}},
+		{name: "lookup_gamertag", args: []string{"lookup", "majornelson"}},
+		{name: "lookup_xuid", args: []string{"lookup", "2533274792093503"}},
+		{name: "lookup_not_found", args: []string{"lookup", "Nobody"}},
+		{name: "lookup_not_found_json", args: []string{"lookup", "Nobody"}, jsonErrors: true},
+		{name: "lookup_fuzzy", args: []string{"lookup", "--fuzzy", "--max", "5", "MajrNelson"}},
+		{name: "profile", args: []string{"profile", "MajorNelson"}},
+		{name: "batch", args: []string{"batch", "MajorNelson, Larry Hryb, Nobody"}},
+		{name: "social_friends", args: []string{"social", "friends"}},
+		{name: "roster_empty", args: []string{"roster", "reconcile", "--desired", "testdata/roster_empty.csv", "--apply"}},
+		{name: "roster_remove_guard", args: []string{"roster", "reconcile", "--desired", "testdata/roster_nelson.csv", "--apply"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := runApp(t, newFakeClient(), tt.jsonErrors, tt.args...)
+			got := fmt.Sprintf("exit: %d\n-- stdout --\n%s-- stderr --\n%s", code, stdout, stderr)
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run go test -update): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s (run go test -update to accept):\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+func (a *app) handleCapturesList(ctx context.Context, inv *invocation) {
+	kind := inv.String("type", "all", "captures to list: all, screenshots, or clips")
+	inv.parse()
+
+	if *kind != "all" && *kind != "screenshots" && *kind != "clips" {
+		fmt.Fprintf(a.stderr, "Unknown capture type: %s (expected all, screenshots, or clips)\n", *kind)
+		a.exit(1)
+	}
+
+	identity, err := a.client.Identity(ctx)
+	if err != nil {
+		a.fatal(ctx, "Failed to list captures", err)
+	}
+	xuid := identity.XUID
+
+	fmt.Fprintf(a.stdout, "%-10s  %-20s  %-24s  %s\n", "TYPE", "DATE", "TITLE", "ID")
+
+	if *kind != "clips" {
+		screenshots, err := collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.Screenshot, string, error) {
+			return a.client.GetScreenshots(ctx, xuid, opts)
+		})
+		if err != nil {
+			a.fatal(ctx, "Failed to list screenshots", err)
+		}
+		for _, s := range screenshots {
+			fmt.Fprintf(a.stdout, "%-10s  %-20s  %-24s  %s\n", "screenshot", s.DateTaken.Format(time.RFC3339), s.TitleName, s.ScreenshotID)
+		}
+	}
+
+	if *kind != "screenshots" {
+		clips, err := collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.GameClip, string, error) {
+			return a.client.GetGameClips(ctx, xuid, opts)
+		})
+		if err != nil {
+			a.fatal(ctx, "Failed to list game clips", err)
+		}
+		for _, c := range clips {
+			fmt.Fprintf(a.stdout, "%-10s  %-20s  %-24s  %s\n", "clip", c.DateRecorded.Format(time.RFC3339), c.TitleName, c.GameClipID)
+		}
+	}
+}
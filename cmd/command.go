@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// command is a node in the CLI command tree
+// A command either has subcommands, a run function, or both; a group with a run function runs it when no subcommand is given
+type command struct {
+	name    string
+	args    string // argument synopsis shown in help, e.g. "<gamertag>"
+	summary string
+	nargs   int  // minimum number of positional arguments
+	offline bool // runs without an Xbox client
+
+	subcommands []*command
+	run         func(a *app, ctx context.Context, inv *invocation)
+}
+
+// invocation is a single run of a leaf command: its flags and, once parsed, its positional arguments
+type invocation struct {
+	*flag.FlagSet
+	app  *app
+	cmd  *command
+	path string
+	args []string
+}
+
+// find returns the subcommand with the given name, or nil
+func (c *command) find(name string) *command {
+	for _, sub := range c.subcommands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// runsOffline reports whether args select an offline command
+func runsOffline(root *command, args []string) bool {
+	cmd := root
+	for _, arg := range args {
+		sub := cmd.find(arg)
+		if sub == nil {
+			break
+		}
+		cmd = sub
+	}
+	return cmd.offline
+}
+
+// dispatch walks args down the command tree from root and runs the command they select
+func (a *app) dispatch(ctx context.Context, root *command, args []string) {
+	cmd, path := root, a.name
+	for len(args) > 0 {
+		sub := cmd.find(args[0])
+		if sub == nil {
+			break
+		}
+		cmd, path, args = sub, path+" "+sub.name, args[1:]
+	}
+
+	help := func(w io.Writer) {
+		if cmd == root {
+			printUsage(w, a.name)
+			return
+		}
+		printCommandHelp(w, cmd, path)
+	}
+
+	// Groups print their help unless they run something themselves and the next argument isn't an unknown subcommand
+	if cmd.run == nil || (cmd.subcommands != nil && len(args) > 0 && !strings.HasPrefix(args[0], "-")) {
+		if len(args) > 0 && isHelpFlag(args[0]) {
+			help(a.stdout)
+			a.exit(0)
+		}
+		if len(args) > 0 {
+			fmt.Fprintf(a.stderr, "Unknown command: %s %s\n", path, args[0])
+		}
+		help(a.stderr)
+		a.exit(1)
+	}
+
+	inv := &invocation{FlagSet: flag.NewFlagSet(path, flag.ContinueOnError), app: a, cmd: cmd, path: path, args: args}
+	inv.SetOutput(a.stderr)
+	inv.Usage = func() {
+		printCommandHelp(inv.Output(), cmd, path)
+		if inv.hasFlags() {
+			fmt.Fprintf(inv.Output(), "\nFlags:\n")
+			inv.PrintDefaults()
+		}
+	}
+	cmd.run(a, ctx, inv)
+}
+
+// hasFlags reports whether the command defined any flags
+func (inv *invocation) hasFlags() bool {
+	n := 0
+	inv.VisitAll(func(*flag.Flag) { n++ })
+	return n > 0
+}
+
+// parse parses the invocation's flags, which may be interspersed with positional arguments, and returns the positional arguments
+// Exits with a usage message if parsing fails or too few arguments are given
+func (inv *invocation) parse() []string {
+	var positional []string
+	args := inv.args
+	for {
+		if err := inv.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				inv.app.exit(0)
+			}
+			inv.app.exit(2)
+		}
+		args = inv.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < inv.cmd.nargs {
+		fmt.Fprintf(inv.app.stderr, "Error: %s requires %s\n", inv.path, inv.cmd.args)
+		inv.Usage()
+		inv.app.exit(1)
+	}
+	return positional
+}
+
+// printCommandHelp writes the synopsis, summary, and subcommands of a command
+func printCommandHelp(w io.Writer, cmd *command, path string) {
+	synopsis := path
+	if cmd.subcommands != nil {
+		synopsis += " <command>"
+	}
+	if cmd.args != "" {
+		synopsis += " " + cmd.args
+	}
+	fmt.Fprintf(w, "Usage: %s\n", synopsis)
+	if cmd.summary != "" {
+		fmt.Fprintf(w, "\n%s\n", cmd.summary)
+	}
+
+	if len(cmd.subcommands) > 0 {
+		fmt.Fprintf(w, "\nCommands:\n")
+		printCommandList(w, cmd.subcommands)
+	}
+}
+
+// printCommandList writes one aligned line per leaf command, with nested subcommands prefixed by their parents
+func printCommandList(w io.Writer, cmds []*command) {
+	type row struct{ synopsis, summary string }
+	var rows []row
+	var walk func(cmds []*command, prefix string)
+	walk = func(cmds []*command, prefix string) {
+		for _, cmd := range cmds {
+			name := prefix + cmd.name
+			if cmd.subcommands != nil {
+				walk(cmd.subcommands, name+" ")
+				continue
+			}
+			if cmd.args != "" {
+				name += " " + cmd.args
+			}
+			rows = append(rows, row{name, cmd.summary})
+		}
+	}
+	walk(cmds, "")
+
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r.synopsis))
+	}
+	for _, r := range rows {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, r.synopsis, r.summary)
+	}
+}
+
+// isHelpFlag reports whether arg asks for help
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help" || arg == "help"
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tadhunt/xblive"
+)
+
+// cliError is the structured form of a fatal error
+type cliError struct {
+	Message    string `json:"message"`
+	Error      string `json:"error"`
+	TraceID    string `json:"trace_id,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	CV         string `json:"cv,omitempty"`
+	XErr       string `json:"xerr,omitempty"`
+	Temporary  bool   `json:"temporary,omitempty"`
+}
+
+// globalFlags are the flags accepted before the command
+type globalFlags struct {
+	traceID    string
+	jsonErrors bool
+	noColor    bool
+}
+
+// fatal reports a failed operation on stderr, tagged with the trace ID, and exits
+func (a *app) fatal(ctx context.Context, message string, err error) {
+	traceID := xblive.TraceIDFromContext(ctx)
+
+	if !a.jsonErrors {
+		fmt.Fprintf(a.stderr, "%s: %v (trace-id: %s)\n", message, err, traceID)
+		a.exit(1)
+	}
+
+	out := cliError{
+		Message:   message,
+		Error:     err.Error(),
+		TraceID:   traceID,
+		Temporary: xblive.IsTemporary(err),
+	}
+
+	var apiErr *xblive.XboxAPIError
+	if errors.As(err, &apiErr) {
+		out.StatusCode = apiErr.StatusCode
+		out.CV = apiErr.CorrelationVector
+	}
+
+	var xboxErr *xblive.XboxError
+	if errors.As(err, &xboxErr) {
+		out.XErr = xboxErr.XErr.String()
+	}
+
+	_ = json.NewEncoder(a.stderr).Encode(out)
+	a.exit(1)
+}
+
+// parseGlobalFlags strips the global flags that precede the command
+// Returns: remaining arguments, global flags (with a generated trace ID if none was given), error
+func parseGlobalFlags(args []string) ([]string, globalFlags, error) {
+	var g globalFlags
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		flag := args[0]
+		args = args[1:]
+
+		switch {
+		case flag == "--json-errors":
+			g.jsonErrors = true
+		case flag == "--no-color":
+			g.noColor = true
+		case flag == "--trace-id":
+			if len(args) == 0 {
+				return nil, g, fmt.Errorf("--trace-id requires a value")
+			}
+			g.traceID = args[0]
+			args = args[1:]
+		case strings.HasPrefix(flag, "--trace-id="):
+			g.traceID = strings.TrimPrefix(flag, "--trace-id=")
+		default:
+			return nil, g, fmt.Errorf("unknown flag: %s", flag)
+		}
+	}
+
+	if g.traceID == "" {
+		g.traceID = xblive.NewTraceID()
+	}
+	return args, g, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+const (
+	// exportPacing is the delay between paged requests during export
+	exportPacing = 500 * time.Millisecond
+
+	// exportRetries is the number of times a temporarily failing request is retried
+	exportRetries = 3
+)
+
+func (a *app) handleExport(ctx context.Context, inv *invocation) {
+	out := inv.String("out", "xblive-export.zip", "path of the ZIP archive to write")
+	inv.parse()
+
+	identity, err := a.client.Identity(ctx)
+	if err != nil {
+		a.fatal(ctx, "Export failed", err)
+	}
+	xuid := identity.XUID
+
+	f, err := os.Create(*out)
+	if err != nil {
+		a.fatal(ctx, "Failed to create archive", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	steps := []struct {
+		name  string
+		fetch func() (interface{}, error)
+	}{
+		{"profile.json", func() (interface{}, error) { return a.client.GetProfile(ctx, xuid) }},
+		{"friends.json", func() (interface{}, error) { return a.client.GetFriends(ctx) }},
+		{"achievements.json", func() (interface{}, error) {
+			return collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.Achievement, string, error) {
+				return a.client.GetAchievements(ctx, xuid, opts)
+			})
+		}},
+		{"activity.json", func() (interface{}, error) {
+			return collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.ActivityItem, string, error) {
+				return a.client.GetActivity(ctx, xuid, opts)
+			})
+		}},
+		{"captures/screenshots.json", func() (interface{}, error) {
+			return collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.Screenshot, string, error) {
+				return a.client.GetScreenshots(ctx, xuid, opts)
+			})
+		}},
+		{"captures/gameclips.json", func() (interface{}, error) {
+			return collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.GameClip, string, error) {
+				return a.client.GetGameClips(ctx, xuid, opts)
+			})
+		}},
+	}
+
+	for _, step := range steps {
+		fmt.Fprintf(a.stderr, "Exporting %s...\n", step.name)
+		v, err := step.fetch()
+		if err != nil {
+			a.fatal(ctx, fmt.Sprintf("Export of %s failed", step.name), err)
+		}
+		if err := writeZipJSON(zw, step.name, v); err != nil {
+			a.fatal(ctx, fmt.Sprintf("Failed to write %s", step.name), err)
+		}
+	}
+
+	fmt.Fprintf(a.stderr, "Exporting messages...\n")
+	conversations, err := collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.Conversation, string, error) {
+		return a.client.GetConversations(ctx, opts)
+	})
+	if err != nil {
+		a.fatal(ctx, "Export of conversations failed", err)
+	}
+	if err := writeZipJSON(zw, "messages/conversations.json", conversations); err != nil {
+		a.fatal(ctx, "Failed to write conversations", err)
+	}
+
+	// Each conversation, one-to-one or group, is exported once under its own ID
+	exported := make(map[string]bool, len(conversations))
+	for _, conversation := range conversations {
+		id := conversation.ConversationID
+		if id == "" || exported[id] {
+			continue
+		}
+		exported[id] = true
+
+		messages, err := collectPages(ctx, func(opts xblive.PageOptions) ([]*xblive.Message, string, error) {
+			return a.client.GetConversationMessages(ctx, id, opts)
+		})
+		if err != nil {
+			a.fatal(ctx, fmt.Sprintf("Export of conversation %s failed", id), err)
+		}
+		if err := writeZipJSON(zw, fmt.Sprintf("messages/%s.json", url.PathEscape(id)), messages); err != nil {
+			a.fatal(ctx, "Failed to write messages", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		a.fatal(ctx, "Failed to finish archive", err)
+	}
+
+	a.out.success("Exported to %s", *out)
+}
+
+// collectPages fetches every page of a paged list API, pacing requests and retrying temporary failures
+func collectPages[T any](ctx context.Context, fetch func(xblive.PageOptions) ([]T, string, error)) ([]T, error) {
+	return xblive.Crawl(ctx, func(ctx context.Context, opts xblive.PageOptions) ([]T, string, error) {
+		return fetch(opts)
+	}, xblive.CrawlOptions{Pacing: exportPacing, MaxRetries: exportRetries})
+}
+
+// sleep waits for d or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// writeZipJSON writes v as indented JSON to a new file in the archive
+func writeZipJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// presenceExporter periodically samples presence and serves it in the Prometheus text exposition format
+type presenceExporter struct {
+	client   xboxClient
+	stderr   io.Writer
+	targets  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	metrics string
+}
+
+func (a *app) handleExporter(ctx context.Context, inv *invocation) {
+	targets := inv.String("targets", "friends", "users to export: 'friends' or a comma-separated list of XUIDs")
+	listen := inv.String("listen", ":9200", "address to serve metrics on")
+	interval := inv.Duration("interval", time.Minute, "time between presence samples")
+	inv.parse()
+
+	e := &presenceExporter{client: a.client, stderr: a.stderr, targets: *targets, interval: *interval}
+	go e.run(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.serveMetrics)
+
+	fmt.Fprintf(a.stderr, "Serving presence metrics for %s on %s/metrics\n", *targets, *listen)
+	if err := http.ListenAndServe(*listen, mux); err != nil {
+		a.fatal(ctx, "Exporter failed", err)
+	}
+}
+
+// run samples presence on every interval until the context is cancelled
+func (e *presenceExporter) run(ctx context.Context) {
+	for {
+		e.sample(ctx)
+		if err := sleep(ctx, e.interval); err != nil {
+			return
+		}
+	}
+}
+
+// sample fetches presence for the targets and renders the metrics
+// On failure the previous user metrics are kept and xblive_scrape_success reports 0
+func (e *presenceExporter) sample(ctx context.Context) {
+	gamertags, presence, err := e.fetch(ctx)
+
+	var b strings.Builder
+	if err != nil {
+		fmt.Fprintf(e.stderr, "Presence sample failed: %v\n", err)
+
+		e.mu.Lock()
+		b.WriteString(userMetrics(e.metrics))
+		e.mu.Unlock()
+	} else {
+		writeUserMetrics(&b, gamertags, presence)
+	}
+
+	success := 1
+	if err != nil {
+		success = 0
+	}
+	fmt.Fprintf(&b, "# HELP xblive_scrape_success Whether the last presence sample succeeded\n")
+	fmt.Fprintf(&b, "# TYPE xblive_scrape_success gauge\n")
+	fmt.Fprintf(&b, "xblive_scrape_success %d\n", success)
+	fmt.Fprintf(&b, "# HELP xblive_scrape_timestamp_seconds Unix time of the last presence sample\n")
+	fmt.Fprintf(&b, "# TYPE xblive_scrape_timestamp_seconds gauge\n")
+	fmt.Fprintf(&b, "xblive_scrape_timestamp_seconds %d\n", time.Now().Unix())
+
+	e.mu.Lock()
+	e.metrics = b.String()
+	e.mu.Unlock()
+}
+
+// fetch returns the targets' gamertags (XUID -> gamertag) and presence
+func (e *presenceExporter) fetch(ctx context.Context) (map[string]string, []*xblive.Presence, error) {
+	gamertags := make(map[string]string)
+
+	if e.targets == "friends" {
+		friends, err := e.client.GetFriends(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, friend := range friends {
+			gamertags[friend.XUID] = friend.Gamertag
+		}
+	} else {
+		var xuids []string
+		for _, xuid := range strings.Split(e.targets, ",") {
+			if xuid = strings.TrimSpace(xuid); xuid != "" {
+				xuids = append(xuids, xuid)
+			}
+		}
+		resolved, err := e.client.ResolveGamertags(ctx, xuids)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, xuid := range xuids {
+			gamertags[xuid] = resolved[xuid]
+		}
+	}
+
+	xuids := make([]string, 0, len(gamertags))
+	for xuid := range gamertags {
+		xuids = append(xuids, xuid)
+	}
+	sort.Strings(xuids)
+
+	presence, err := e.client.GetPresence(ctx, xuids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gamertags, presence, nil
+}
+
+// writeUserMetrics renders the per-user gauges
+func writeUserMetrics(b *strings.Builder, gamertags map[string]string, presence []*xblive.Presence) {
+	fmt.Fprintf(b, "# HELP xblive_user_online Whether the user is online\n")
+	fmt.Fprintf(b, "# TYPE xblive_user_online gauge\n")
+	for _, p := range presence {
+		online := 0
+		if p.IsOnline() {
+			online = 1
+		}
+		fmt.Fprintf(b, "xblive_user_online{xuid=\"%s\",gamertag=\"%s\"} %d\n", escapeLabel(p.XUID), escapeLabel(gamertags[p.XUID]), online)
+	}
+
+	fmt.Fprintf(b, "# HELP xblive_user_in_game Whether the user is running a title in the foreground\n")
+	fmt.Fprintf(b, "# TYPE xblive_user_in_game gauge\n")
+	for _, p := range presence {
+		for _, title := range p.ActiveTitles() {
+			if title.Placement == "Background" {
+				continue
+			}
+			fmt.Fprintf(b, "xblive_user_in_game{xuid=\"%s\",gamertag=\"%s\",title_id=\"%s\",title_name=\"%s\"} 1\n",
+				escapeLabel(p.XUID), escapeLabel(gamertags[p.XUID]), escapeLabel(title.ID), escapeLabel(title.Name))
+		}
+	}
+}
+
+// userMetrics returns the per-user portion of previously rendered metrics
+func userMetrics(metrics string) string {
+	if i := strings.Index(metrics, "# HELP xblive_scrape_success"); i >= 0 {
+		return metrics[:i]
+	}
+	return metrics
+}
+
+// labelEscaper escapes Prometheus label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// serveMetrics serves the most recently rendered metrics
+func (e *presenceExporter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	metrics := e.metrics
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, metrics)
+}
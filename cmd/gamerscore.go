@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// gamerscoreSample is one gamerscore observation, stored as a line of JSON in the sample database
+type gamerscoreSample struct {
+	Time       time.Time `json:"time"`
+	XUID       string    `json:"xuid"`
+	Gamertag   string    `json:"gamertag"`
+	Gamerscore int       `json:"gamerscore"`
+}
+
+func (a *app) handleGamerscoreTrack(ctx context.Context, inv *invocation) {
+	db := inv.String("db", "scores.ndjson", "path of the sample database (one JSON sample per line)")
+	interval := inv.Duration("interval", time.Hour, "time between samples")
+	once := inv.Bool("once", false, "take a single sample and exit")
+	gamertag := inv.parse()[0]
+
+	a.trackGamerscore(ctx, gamertag, *db, *interval, *once)
+}
+
+func (a *app) handleGamerscoreReport(ctx context.Context, inv *invocation) {
+	db := inv.String("db", "scores.ndjson", "path of the sample database (one JSON sample per line)")
+	user := inv.parse()[0]
+
+	a.reportGamerscore(ctx, user, *db)
+}
+
+// trackGamerscore samples a user's gamerscore into the database until interrupted
+func (a *app) trackGamerscore(ctx context.Context, gamertag string, db string, interval time.Duration, once bool) {
+	profile, err := a.client.LookupProfileByGamertag(ctx, gamertag)
+	if err != nil {
+		a.fatal(ctx, "Lookup failed", err)
+	}
+	xuid := profile.XUID
+
+	fmt.Fprintf(a.stderr, "Tracking gamerscore for %s (XUID %s) every %s into %s\n", profile.Gamertag, xuid, interval, db)
+
+	for {
+		// Sample by XUID so tracking survives gamertag changes
+		sample, err := a.sampleGamerscore(ctx, xuid)
+		if err != nil {
+			if once {
+				a.fatal(ctx, "Sample failed", err)
+			}
+			// A failed sample is skipped, the next tick tries again
+			a.errOut.warning("Sample failed, retrying in %s: %v", interval, err)
+		} else {
+			if err := appendSample(db, sample); err != nil {
+				a.fatal(ctx, "Failed to store sample", err)
+			}
+			fmt.Fprintf(a.stdout, "%s  %s  %d\n", sample.Time.Format(time.RFC3339), sample.Gamertag, sample.Gamerscore)
+		}
+
+		if once {
+			return
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return
+		}
+	}
+}
+
+// sampleGamerscore reads a user's current gamerscore
+func (a *app) sampleGamerscore(ctx context.Context, xuid string) (gamerscoreSample, error) {
+	profile, err := a.client.GetProfile(ctx, xuid)
+	if err != nil {
+		return gamerscoreSample{}, fmt.Errorf("profile lookup failed: %w", err)
+	}
+
+	score, err := strconv.Atoi(profile.GamerScore)
+	if err != nil {
+		return gamerscoreSample{}, fmt.Errorf("invalid gamerscore: %w", err)
+	}
+
+	return gamerscoreSample{Time: time.Now().UTC(), XUID: xuid, Gamertag: profile.Gamertag, Gamerscore: score}, nil
+}
+
+// reportGamerscore prints a delta report of the samples recorded for a gamertag or XUID
+func (a *app) reportGamerscore(ctx context.Context, user string, db string) {
+	samples, err := readSamples(db)
+	if err != nil {
+		a.fatal(ctx, "Failed to read samples", err)
+	}
+
+	// Accept either a XUID or any gamertag the user was recorded with
+	xuid := user
+	for _, s := range samples {
+		if xblive.GamertagsEqual(s.Gamertag, user) {
+			xuid = s.XUID
+		}
+	}
+
+	var matched []gamerscoreSample
+	for _, s := range samples {
+		if s.XUID == xuid {
+			matched = append(matched, s)
+		}
+	}
+	if len(matched) == 0 {
+		a.fatal(ctx, "Report failed", fmt.Errorf("no samples for %s in %s", user, db))
+	}
+
+	fmt.Fprintf(a.stdout, "%-20s  %-16s  %10s  %8s\n", "TIME", "GAMERTAG", "GAMERSCORE", "DELTA")
+	for i, s := range matched {
+		delta := ""
+		if i > 0 {
+			delta = fmt.Sprintf("%+d", s.Gamerscore-matched[i-1].Gamerscore)
+		}
+		fmt.Fprintf(a.stdout, "%-20s  %-16s  %10d  %8s\n", s.Time.Format(time.RFC3339), s.Gamertag, s.Gamerscore, delta)
+	}
+
+	first, last := matched[0], matched[len(matched)-1]
+	fmt.Fprintf(a.stdout, "\nTotal: %+d over %s (%d samples)\n", last.Gamerscore-first.Gamerscore, last.Time.Sub(first.Time).Round(time.Minute), len(matched))
+}
+
+// appendSample appends a sample to the database
+func appendSample(db string, sample gamerscoreSample) error {
+	f, err := os.OpenFile(db, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(sample); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readSamples reads every sample in the database, in the order recorded
+func readSamples(db string) ([]gamerscoreSample, error) {
+	f, err := os.Open(db)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []gamerscoreSample
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var s gamerscoreSample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("failed to parse sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// flakyClient fails its first profile lookups, then cancels tracking once enough samples were served
+type flakyClient struct {
+	*fakeClient
+
+	failures int
+	samples  int
+	cancel   context.CancelFunc
+}
+
+func (f *flakyClient) GetProfile(ctx context.Context, xuid string) (*xblive.Profile, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("503 service unavailable")
+	}
+	f.samples--
+	if f.samples == 0 {
+		f.cancel()
+	}
+	return f.fakeClient.GetProfile(ctx, xuid)
+}
+
+func TestTrackGamerscoreRetriesFailedSamples(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &flakyClient{fakeClient: newFakeClient(), failures: 2, samples: 2, cancel: cancel}
+	db := filepath.Join(t.TempDir(), "scores.ndjson")
+
+	var out, errOut bytes.Buffer
+	a := &app{
+		client: client,
+		stdout: &out,
+		stderr: &errOut,
+		out:    newPrinter(&out, true),
+		errOut: newPrinter(&errOut, true),
+		name:   "xblive",
+		exit:   func(code int) { t.Fatalf("tracking exited with status %d: %s", code, errOut.String()) },
+	}
+	a.trackGamerscore(ctx, "MajorNelson", db, time.Millisecond, false)
+
+	if n := strings.Count(errOut.String(), "Sample failed"); n != 2 {
+		t.Errorf("%d failed samples logged; want 2:\n%s", n, errOut.String())
+	}
+	samples, err := readSamples(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Errorf("%d samples stored; want 2", len(samples))
+	}
+}
@@ -4,168 +4,290 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/tadhunt/xblive"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		printUsage()
+	args, g, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printUsage(os.Stdout, os.Args[0])
 		os.Exit(1)
 	}
 
-	// Get client ID from environment variable
-	clientID := os.Getenv("XBLIVE_CLIENT_ID")
-	if clientID == "" {
-		fmt.Fprintf(os.Stderr, "Error: XBLIVE_CLIENT_ID environment variable is required\n")
-		fmt.Fprintf(os.Stderr, "Set it with: export XBLIVE_CLIENT_ID='your-client-id'\n")
+	if len(args) < 1 {
+		printUsage(os.Stdout, os.Args[0])
 		os.Exit(1)
 	}
 
-	// Create client
-	client, err := xblive.New(xblive.Config{
-		ClientID: clientID,
-	})
+	// Help and offline commands need no client
+	if isHelpFlag(args[0]) || runsOffline(newRootCommand(), args) {
+		newApp(nil, g).run(context.Background(), args)
+		return
+	}
+
+	// Create client from environment variables
+	client, err := xblive.NewFromEnv()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating client: %v\n", err)
+		if os.Getenv(xblive.EnvClientID) == "" {
+			fmt.Fprintf(os.Stderr, "Set it with: export XBLIVE_CLIENT_ID='your-client-id'\n")
+		}
 		os.Exit(1)
 	}
 
-	ctx := context.Background()
-	command := os.Args[1]
+	ctx := xblive.WithTraceID(context.Background(), g.traceID)
+	newApp(client, g).run(ctx, args)
+	client.Close()
+}
 
-	switch command {
-	case "auth":
-		handleAuth(ctx, client)
-	case "logout":
-		handleLogout(ctx, client)
-	case "lookup":
-		if len(os.Args) < 3 {
-			fmt.Fprintf(os.Stderr, "Error: gamertag required\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s lookup <gamertag>\n", os.Args[0])
-			os.Exit(1)
-		}
-		handleLookup(ctx, client, os.Args[2])
-	case "batch":
-		if len(os.Args) < 3 {
-			fmt.Fprintf(os.Stderr, "Error: gamertags required\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s batch <gamertag1,gamertag2,...>\n", os.Args[0])
-			os.Exit(1)
-		}
-		handleBatch(ctx, client, os.Args[2])
-	case "profile":
-		if len(os.Args) < 3 {
-			fmt.Fprintf(os.Stderr, "Error: gamertag required\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s profile <gamertag>\n", os.Args[0])
-			os.Exit(1)
-		}
-		handleProfile(ctx, client, os.Args[2])
-	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
-		printUsage()
-		os.Exit(1)
+// run dispatches a command and its arguments
+func (a *app) run(ctx context.Context, args []string) {
+	a.dispatch(ctx, newRootCommand(), args)
+}
+
+// newRootCommand builds the CLI command tree
+func newRootCommand() *command {
+	root := &command{
+		summary: "Xbox Live API CLI Tool",
+		subcommands: []*command{
+			{
+				name:    "auth",
+				summary: "Authenticate with Xbox Live (device code flow)",
+				run:     (*app).handleLogin,
+				subcommands: []*command{
+					{name: "login", summary: "Authenticate with Xbox Live (device code flow)", run: (*app).handleLogin},
+					{name: "logout", summary: "Clear cached authentication tokens", run: (*app).handleLogout},
+					{name: "tokens", summary: "Show validity and expiry of cached tokens", run: (*app).handleTokens},
+				},
+			},
+			{name: "lookup", args: "<gamertag|xuid>", nargs: 1, summary: "Convert a gamertag to XUID, or a XUID to gamertag", run: (*app).handleLookup},
+			{name: "profile", args: "<gamertag|xuid>", nargs: 1, summary: "Get full profile for a gamertag or XUID", run: (*app).handleProfile},
+			{name: "batch", args: "<gt1,gt2,...>", nargs: 1, summary: "Convert multiple gamertags to XUIDs", run: (*app).handleBatch},
+			{
+				name:    "social",
+				summary: "Friends and the social graph",
+				subcommands: []*command{
+					{name: "friends", summary: "List your friends", run: (*app).handleFriends},
+					{name: "graph", args: "<depth> <json|dot|graphml>", nargs: 2, summary: "Export the friend graph as json, dot, or graphml", run: (*app).handleGraph},
+					{name: "prune", summary: "Suggest and remove friends inactive for --inactive-days, confirming each (--dry-run, --yes)", run: (*app).handleSocialPrune},
+				},
+			},
+			{
+				name:    "roster",
+				summary: "Keep your friend list in sync with a roster",
+				subcommands: []*command{
+					{name: "reconcile", summary: "Diff your friend list against a CSV or JSON roster (--desired), applying it with --apply", run: (*app).handleRosterReconcile},
+				},
+			},
+			{
+				name:    "monitor",
+				summary: "Watch users for changes",
+				subcommands: []*command{
+					{name: "gamertags", summary: "Report gamertag changes of the XUIDs in a file (--xuids, --state, --webhook)", run: (*app).handleMonitorGamertags},
+				},
+			},
+			{
+				name:    "presence",
+				summary: "Your online presence",
+				subcommands: []*command{
+					{name: "set", args: "<online|offline>", nargs: 1, summary: "Appear online or offline to other users", run: (*app).handlePresenceSet},
+				},
+			},
+			{
+				name:    "captures",
+				summary: "Screenshots and game clips",
+				subcommands: []*command{
+					{name: "list", summary: "List your screenshots and game clips", run: (*app).handleCapturesList},
+				},
+			},
+			{name: "status", summary: "Check Xbox network health (exit 3 if impacted, 4 if only credentials fail)", run: (*app).handleStatus},
+			{name: "export", summary: "Export your profile, friends, messages, activity, captures, and achievements to a ZIP", run: (*app).handleExport},
+			{name: "exporter", summary: "Serve presence as Prometheus metrics", run: (*app).handleExporter},
+			{
+				name:    "serve",
+				summary: "Serve lookup, batch, profile, presence, and friends as a REST API (--api-keys, --client-ca)",
+				run:     (*app).handleServe,
+				subcommands: []*command{
+					{name: "openapi", summary: "Print the REST API's OpenAPI 3 document", offline: true, run: (*app).handleOpenAPI},
+				},
+			},
+			{
+				name:    "gamerscore",
+				summary: "Track gamerscore over time",
+				subcommands: []*command{
+					{name: "track", args: "<gamertag>", nargs: 1, summary: "Sample a user's gamerscore over time", run: (*app).handleGamerscoreTrack},
+					{name: "report", args: "<gamertag|xuid>", nargs: 1, summary: "Show gamerscore changes recorded in the sample database", run: (*app).handleGamerscoreReport},
+				},
+			},
+		},
 	}
+
+	root.subcommands = append(root.subcommands, &command{
+		name:    "help",
+		args:    "[command...]",
+		summary: "Show help for a command",
+		run: func(a *app, ctx context.Context, inv *invocation) {
+			a.dispatch(ctx, root, append(inv.args, "--help"))
+		},
+	})
+
+	return root
 }
 
-func printUsage() {
-	fmt.Printf("Xbox Live API CLI Tool\n\n")
-	fmt.Printf("Usage:\n")
-	fmt.Printf("  %s <command> [arguments]\n\n", os.Args[0])
-	fmt.Printf("Commands:\n")
-	fmt.Printf("  auth                    Authenticate with Xbox Live (device code flow)\n")
-	fmt.Printf("  logout                  Clear cached authentication tokens\n")
-	fmt.Printf("  lookup <gamertag>       Convert a gamertag to XUID\n")
-	fmt.Printf("  profile <gamertag>      Get full profile for a gamertag\n")
-	fmt.Printf("  batch <gt1,gt2,...>     Convert multiple gamertags to XUIDs\n\n")
-	fmt.Printf("Environment Variables:\n")
-	fmt.Printf("  XBLIVE_CLIENT_ID        Your Microsoft Entra ID application client ID (required)\n\n")
-	fmt.Printf("Examples:\n")
-	fmt.Printf("  export XBLIVE_CLIENT_ID='your-client-id'\n")
-	fmt.Printf("  %s auth\n", os.Args[0])
-	fmt.Printf("  %s lookup MajorNelson\n", os.Args[0])
-	fmt.Printf("  %s profile MajorNelson\n", os.Args[0])
-	fmt.Printf("  %s batch \"Player1,Player2,Player3\"\n", os.Args[0])
+// printUsage writes the command summary to w, using name as the program name
+func printUsage(w io.Writer, name string) {
+	fmt.Fprintf(w, "Xbox Live API CLI Tool\n\n")
+	fmt.Fprintf(w, "Usage:\n")
+	fmt.Fprintf(w, "  %s [global flags] <command> [arguments]\n\n", name)
+	fmt.Fprintf(w, "Global Flags:\n")
+	fmt.Fprintf(w, "  --trace-id <id>         Trace ID sent with every request and included in errors (default random)\n")
+	fmt.Fprintf(w, "  --json-errors           Write errors to stderr as JSON\n")
+	fmt.Fprintf(w, "  --no-color              Print plain markers instead of colored symbols (also NO_COLOR; automatic when not a terminal)\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	printCommandList(w, newRootCommand().subcommands)
+	fmt.Fprintf(w, "\nRun '%s help <command>' for a command's flags.\n\n", name)
+	fmt.Fprintf(w, "Environment Variables:\n")
+	fmt.Fprintf(w, "  XBLIVE_CLIENT_ID        Your Microsoft Entra ID application client ID (required)\n")
+	fmt.Fprintf(w, "  XBLIVE_TENANT           Microsoft Entra ID tenant (default consumers)\n")
+	fmt.Fprintf(w, "  XBLIVE_CACHE_DIR        Directory for the token cache (default ~/.xblive)\n")
+	fmt.Fprintf(w, "  XBLIVE_ACCOUNT          Named account whose tokens to use\n")
+	fmt.Fprintf(w, "  XBLIVE_LOG_LEVEL        Log level: debug, info, warn, error\n\n")
+	fmt.Fprintf(w, "Examples:\n")
+	fmt.Fprintf(w, "  export XBLIVE_CLIENT_ID='your-client-id'\n")
+	fmt.Fprintf(w, "  %s auth login\n", name)
+	fmt.Fprintf(w, "  %s lookup MajorNelson\n", name)
+	fmt.Fprintf(w, "  %s lookup MajorNelsn --fuzzy --max 5\n", name)
+	fmt.Fprintf(w, "  %s profile MajorNelson\n", name)
+	fmt.Fprintf(w, "  %s profile 2533274792093503\n", name)
+	fmt.Fprintf(w, "  %s batch \"Player1,Player2,Player3\"\n", name)
+	fmt.Fprintf(w, "  %s social graph 2 dot > friends.dot\n", name)
+	fmt.Fprintf(w, "  %s captures list --type screenshots\n", name)
+	fmt.Fprintf(w, "  %s presence set offline\n", name)
+	fmt.Fprintf(w, "  %s status --check-auth\n", name)
+	fmt.Fprintf(w, "  %s roster reconcile --desired roster.csv --apply\n", name)
+	fmt.Fprintf(w, "  %s monitor gamertags --xuids members.txt --state state.json --once\n", name)
+	fmt.Fprintf(w, "  %s export --out archive.zip\n", name)
+	fmt.Fprintf(w, "  %s social prune --inactive-days 365 --dry-run\n", name)
+	fmt.Fprintf(w, "  %s exporter --targets friends --listen :9200\n", name)
+	fmt.Fprintf(w, "  %s serve --listen :8080 --api-keys keys.txt\n", name)
+	fmt.Fprintf(w, "  %s gamerscore track MajorNelson --interval 1h --db scores.ndjson\n", name)
 }
 
-func handleAuth(ctx context.Context, client *xblive.Client) {
-	fmt.Printf("Starting authentication...\n")
-	if err := client.Authenticate(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
-		os.Exit(1)
+func (a *app) handleLogin(ctx context.Context, inv *invocation) {
+	inv.parse()
+
+	fmt.Fprintf(a.stdout, "Starting authentication...\n")
+	if err := a.client.Authenticate(ctx); err != nil {
+		a.fatal(ctx, "Authentication failed", err)
 	}
-	fmt.Printf("✓ Successfully authenticated!\n")
-	fmt.Printf("Tokens cached. You can now use lookup commands.\n")
+	a.out.success("Successfully authenticated!")
+	fmt.Fprintf(a.stdout, "Tokens cached. You can now use lookup commands.\n")
 }
 
-func handleLogout(ctx context.Context, client *xblive.Client) {
-	if err := client.ClearCache(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to clear cache: %v\n", err)
-		os.Exit(1)
+func (a *app) handleLogout(ctx context.Context, inv *invocation) {
+	inv.parse()
+
+	if err := a.client.ClearCache(ctx); err != nil {
+		a.fatal(ctx, "Failed to clear cache", err)
 	}
-	fmt.Printf("✓ Successfully logged out and cleared cached tokens.\n")
+	a.out.success("Successfully logged out and cleared cached tokens.")
 }
 
-func handleLookup(ctx context.Context, client *xblive.Client, gamertag string) {
-	fmt.Printf("Looking up gamertag: %s\n", gamertag)
+func (a *app) handleTokens(ctx context.Context, inv *invocation) {
+	inv.parse()
 
-	profile, err := client.LookupProfileByGamertag(ctx, gamertag)
+	info, err := a.client.TokenInfo(ctx)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Lookup failed: %v\n", err)
-		os.Exit(1)
+		a.fatal(ctx, "Failed to read token info", err)
 	}
 
-	fmt.Printf("\n✓ Found!\n")
-	fmt.Printf("  Gamertag: %s\n", profile.Gamertag)
-	fmt.Printf("  XUID:     %s\n", profile.XUID)
+	output, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		a.fatal(ctx, "Failed to format token info", err)
+	}
+	fmt.Fprintln(a.stdout, string(output))
 }
 
-func handleProfile(ctx context.Context, client *xblive.Client, gamertag string) {
-	fmt.Printf("Looking up profile for gamertag: %s\n", gamertag)
+func (a *app) handleBatch(ctx context.Context, inv *invocation) {
+	gamertags := strings.Split(inv.parse()[0], ",")
+	for i, gt := range gamertags {
+		gamertags[i] = strings.TrimSpace(gt)
+	}
+
+	fmt.Fprintf(a.stdout, "Looking up %d gamertags...\n", len(gamertags))
 
-	profile, err := client.LookupProfileByGamertag(ctx, gamertag)
+	results, fuzzyOnly, err := a.client.GamertagsToXUIDs(ctx, gamertags)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Profile lookup failed: %v\n", err)
-		os.Exit(1)
+		a.fatal(ctx, "Batch lookup failed", err)
 	}
 
-	fmt.Printf("\n✓ Profile found!\n\n")
+	fmt.Fprintln(a.stdout)
+	a.out.success("Results (%d found):", len(results))
 
 	// Pretty print as JSON
-	output, err := json.MarshalIndent(profile, "", "  ")
+	output, err := json.MarshalIndent(results, "", "  ")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to format profile: %v\n", err)
-		os.Exit(1)
+		a.fatal(ctx, "Failed to format results", err)
+	}
+	fmt.Fprintln(a.stdout, string(output))
+
+	if len(fuzzyOnly) > 0 {
+		fmt.Fprintln(a.stdout)
+		a.out.warning("No exact match (fuzzy results shown): %s", strings.Join(fuzzyOnly, ", "))
 	}
-	fmt.Println(string(output))
 }
 
-func handleBatch(ctx context.Context, client *xblive.Client, gamertagsStr string) {
-	gamertags := strings.Split(gamertagsStr, ",")
-	for i, gt := range gamertags {
-		gamertags[i] = strings.TrimSpace(gt)
+func (a *app) handleGraph(ctx context.Context, inv *invocation) {
+	args := inv.parse()
+	depthStr, format := args[0], args[1]
+
+	depth, err := strconv.Atoi(depthStr)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "Invalid depth: %s\n", depthStr)
+		a.exit(1)
+	}
+
+	var write func(*xblive.SocialGraph) error
+	switch format {
+	case "json":
+		write = func(g *xblive.SocialGraph) error { return g.WriteJSON(a.stdout) }
+	case "dot":
+		write = func(g *xblive.SocialGraph) error { return g.WriteDOT(a.stdout) }
+	case "graphml":
+		write = func(g *xblive.SocialGraph) error { return g.WriteGraphML(a.stdout) }
+	default:
+		fmt.Fprintf(a.stderr, "Unknown format: %s (expected json, dot, or graphml)\n", format)
+		a.exit(1)
 	}
 
-	fmt.Printf("Looking up %d gamertags...\n", len(gamertags))
+	fmt.Fprintf(a.stderr, "Walking social graph to depth %d...\n", depth)
 
-	results, fuzzyOnly, err := client.GamertagsToXUIDs(ctx, gamertags)
+	graph, err := a.client.ExportSocialGraph(ctx, depth)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Batch lookup failed: %v\n", err)
-		os.Exit(1)
+		a.fatal(ctx, "Graph export failed", err)
+	}
+
+	if err := write(graph); err != nil {
+		a.fatal(ctx, "Failed to write graph", err)
 	}
+}
 
-	fmt.Printf("\n✓ Results (%d found):\n", len(results))
+func (a *app) handleFriends(ctx context.Context, inv *invocation) {
+	inv.parse()
 
-	// Pretty print as JSON
-	output, err := json.MarshalIndent(results, "", "  ")
+	friends, err := a.client.GetFriends(ctx)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to format results: %v\n", err)
-		os.Exit(1)
+		a.fatal(ctx, "Failed to get friends", err)
 	}
-	fmt.Println(string(output))
 
-	if len(fuzzyOnly) > 0 {
-		fmt.Printf("\n⚠ No exact match (fuzzy results shown): %s\n", strings.Join(fuzzyOnly, ", "))
+	fmt.Fprintf(a.stdout, "%-16s  %-20s  %s\n", "XUID", "GAMERTAG", "PRESENCE")
+	for _, f := range friends {
+		fmt.Fprintf(a.stdout, "%-16s  %-20s  %s\n", f.XUID, f.Gamertag, f.PresenceState)
 	}
 }
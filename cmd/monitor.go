@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// exitGamertagChanged is the exit code of a single monitoring pass (--once) that saw a gamertag change
+const exitGamertagChanged = 3
+
+// gamertagChange is the webhook payload sent when a gamertag changes
+type gamertagChange struct {
+	XUID        string    `json:"xuid"`
+	OldGamertag string    `json:"old_gamertag"`
+	NewGamertag string    `json:"new_gamertag"`
+	Time        time.Time `json:"time"`
+}
+
+func (a *app) handleMonitorGamertags(ctx context.Context, inv *invocation) {
+	xuidsFile := inv.String("xuids", "", "file of XUIDs to monitor, one per line (required)")
+	state := inv.String("state", "gamertags.json", "path of the gamertag history state file")
+	interval := inv.Duration("interval", 15*time.Minute, "time between checks")
+	once := inv.Bool("once", false, "check once and exit, with status 3 if any gamertag changed")
+	webhook := inv.String("webhook", "", "URL to POST a JSON notification to for each change")
+	inv.parse()
+
+	if *xuidsFile == "" {
+		fmt.Fprintf(a.stderr, "Error: --xuids is required\n")
+		inv.Usage()
+		a.exit(1)
+	}
+
+	xuids, err := readXUIDs(*xuidsFile)
+	if err != nil {
+		a.fatal(ctx, "Failed to read XUIDs", err)
+	}
+
+	store, err := xblive.NewFileAliasStore(*state)
+	if err != nil {
+		a.fatal(ctx, "Failed to open state", err)
+	}
+
+	fmt.Fprintf(a.stderr, "Monitoring %d gamertags every %s\n", len(xuids), *interval)
+
+	for {
+		var changes []gamertagChange
+		gamertags, err := a.client.ResolveGamertags(ctx, xuids)
+		if err != nil {
+			a.fatal(ctx, "Failed to resolve gamertags", err)
+		}
+
+		now := time.Now().UTC()
+		err = xblive.RecordAliases(ctx, store, gamertags, now, func(xuid, oldGamertag, newGamertag string) {
+			changes = append(changes, gamertagChange{XUID: xuid, OldGamertag: oldGamertag, NewGamertag: newGamertag, Time: now})
+		})
+		if err != nil {
+			a.fatal(ctx, "Failed to record gamertags", err)
+		}
+
+		for _, change := range changes {
+			fmt.Fprintf(a.stdout, "%s  %s  %s -> %s\n", change.Time.Format(time.RFC3339), change.XUID, change.OldGamertag, change.NewGamertag)
+			if *webhook != "" {
+				if err := postWebhook(ctx, *webhook, change); err != nil {
+					fmt.Fprintf(a.stderr, "Webhook failed: %v\n", err)
+				}
+			}
+		}
+
+		if *once {
+			if len(changes) > 0 {
+				a.exit(exitGamertagChanged)
+			}
+			return
+		}
+		if err := sleep(ctx, *interval); err != nil {
+			return
+		}
+	}
+}
+
+// readXUIDs reads one XUID per line, skipping blank lines and # comments
+func readXUIDs(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var xuids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		xuids = append(xuids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(xuids) == 0 {
+		return nil, fmt.Errorf("no XUIDs in %s", path)
+	}
+	return xuids, nil
+}
+
+// postWebhook POSTs a JSON payload to a webhook URL
+func postWebhook(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+//go:generate go run . serve openapi --out openapi.json
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// openAPIPath serves the OpenAPI document; it is not behind API key or rate limit checks
+const openAPIPath = "/openapi.json"
+
+// openAPIVersion is the version of the REST API the document describes
+const openAPIVersion = "1.0.0"
+
+// openAPIHandler serves the OpenAPI document
+func openAPIHandler() http.Handler {
+	doc := openAPIDocument()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, doc)
+	})
+}
+
+func (a *app) handleOpenAPI(ctx context.Context, inv *invocation) {
+	out := inv.String("out", "", "file to write the document to (default: stdout)")
+	inv.parse()
+
+	data, err := json.MarshalIndent(openAPIDocument(), "", "  ")
+	if err != nil {
+		a.fatal(ctx, "Failed to encode OpenAPI document", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		a.stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		a.fatal(ctx, "Failed to write OpenAPI document", err)
+	}
+}
+
+// openAPIDocument builds an OpenAPI 3 document from serveRoutes, describing response types by reflection
+func openAPIDocument() map[string]interface{} {
+	schemas := newSchemaSet()
+	errorResponse := map[string]interface{}{
+		"description": "Error",
+		"content":     jsonContent(schemas.of(reflect.TypeOf(serveError{}))),
+	}
+
+	paths := make(map[string]interface{})
+	for _, rt := range serveRoutes {
+		path := rt.path
+		var params []interface{}
+		for _, p := range rt.params {
+			if p.in == "path" {
+				path += "{" + p.name + "}"
+			}
+			params = append(params, map[string]interface{}{
+				"name":        p.name,
+				"in":          p.in,
+				"description": p.description,
+				"required":    true,
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+		}
+
+		op := map[string]interface{}{
+			"operationId": rt.name,
+			"summary":     rt.summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Success",
+					"content":     jsonContent(schemas.of(reflect.TypeOf(rt.response))),
+				},
+				"304":     map[string]interface{}{"description": "Not modified since the ETag given in If-None-Match"},
+				"400":     errorResponse,
+				"401":     errorResponse,
+				"404":     errorResponse,
+				"429":     errorResponse,
+				"default": errorResponse,
+			},
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		paths[path] = map[string]interface{}{"get": op}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "xblive REST API",
+			"description": "Xbox Live lookups proxied by xblive serve",
+			"version":     openAPIVersion,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.named,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearer": []string{}},
+			map[string]interface{}{"apiKey": []string{}},
+		},
+	}
+}
+
+// jsonContent is a response content map for a JSON schema
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// schemaSet collects the named struct schemas referenced from a document
+type schemaSet struct {
+	named map[string]interface{}
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{named: make(map[string]interface{})}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// of returns the schema of a type, adding named structs to the set and referring to them
+func (s *schemaSet) of(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() == "":
+		return s.object(t)
+	case t.Kind() == reflect.Struct:
+		name := schemaName(t)
+		if _, ok := s.named[name]; !ok {
+			s.named[name] = nil // placeholder, so recursive types terminate
+			s.named[name] = s.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.of(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// object returns the schema of a struct's JSON fields; fields without omitempty that can't be null are required
+func (s *schemaSet) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	s.fields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// nullable reports whether a type can encode as JSON null
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// fields adds a struct's JSON fields to properties, flattening embedded structs as encoding/json does
+func (s *schemaSet) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.fields(ft, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = s.of(field.Type)
+		if !strings.Contains(opts, "omitempty") && !nullable(field.Type) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName names a struct's schema, exporting the CLI's own unexported response type names
+func schemaName(t reflect.Type) string {
+	return strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+}
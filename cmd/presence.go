@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tadhunt/xblive"
+)
+
+func (a *app) handlePresenceSet(ctx context.Context, inv *invocation) {
+	mode, err := xblive.ParsePresenceVisibility(inv.parse()[0])
+	if err != nil {
+		fmt.Fprintf(a.stderr, "Error: %v\n", err)
+		a.exit(1)
+	}
+
+	if err := a.client.SetPresenceVisibility(ctx, mode); err != nil {
+		a.fatal(ctx, "Failed to set presence", err)
+	}
+
+	if mode == xblive.PresenceAppearOffline {
+		a.out.success("You now appear offline.")
+		return
+	}
+	a.out.success("You now appear online.")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ANSI escape sequences used when color is enabled
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// printer writes status lines, with colored symbols on a terminal and plain ASCII markers elsewhere
+// Plain output keeps CI logs and redirected files free of escape codes and non-ASCII symbols
+type printer struct {
+	w     io.Writer
+	color bool
+}
+
+// newPrinter creates a printer for w, using color only if w is a terminal and color isn't disabled
+func newPrinter(w io.Writer, noColor bool) *printer {
+	return &printer{w: w, color: !noColor && colorEnabled() && isTerminal(w)}
+}
+
+// success prints a line marking a completed operation
+func (p *printer) success(format string, args ...interface{}) {
+	p.status(ansiGreen, "✓", "[ok]", format, args...)
+}
+
+// failure prints a line marking a failed operation
+func (p *printer) failure(format string, args ...interface{}) {
+	p.status(ansiRed, "✗", "[fail]", format, args...)
+}
+
+// warning prints a line marking something that needs attention
+func (p *printer) warning(format string, args ...interface{}) {
+	p.status(ansiYellow, "⚠", "[warn]", format, args...)
+}
+
+// status prints a message prefixed with a colored symbol, or with a plain marker when color is off
+func (p *printer) status(color, symbol, marker, format string, args ...interface{}) {
+	prefix := marker
+	if p.color {
+		prefix = color + symbol + ansiReset
+	}
+	fmt.Fprintf(p.w, "%s %s\n", prefix, fmt.Sprintf(format, args...))
+}
+
+// colorEnabled reports whether the environment allows color (see https://no-color.org)
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
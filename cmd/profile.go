@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tadhunt/xblive"
+)
+
+// addByFlag defines the --by flag selecting how a user argument is interpreted
+func addByFlag(inv *invocation) *string {
+	return inv.String("by", "auto", "interpret the argument as a 'gamertag', 'xuid' (decimal, 0x hex, or Floodgate UUID), or detect it ('auto')")
+}
+
+// looksLikeXUID reports whether id is a XUID rather than a gamertag
+// Gamertags cannot start with a digit, so an argument starting with one is a XUID in one of the forms ParseXUID accepts
+func looksLikeXUID(id string) bool {
+	return id != "" && id[0] >= '0' && id[0] <= '9'
+}
+
+// resolveProfile looks up a user given either a gamertag or a XUID
+// by is "gamertag", "xuid", or "auto" to detect which from the argument
+func (a *app) resolveProfile(ctx context.Context, id string, by string) (*xblive.Profile, error) {
+	switch by {
+	case "auto":
+		if looksLikeXUID(id) {
+			return a.profileByXUID(ctx, id)
+		}
+		return a.client.LookupProfileByGamertag(ctx, id)
+	case "xuid":
+		return a.profileByXUID(ctx, id)
+	case "gamertag":
+		return a.client.LookupProfileByGamertag(ctx, id)
+	default:
+		return nil, fmt.Errorf("invalid --by value %q: expected gamertag, xuid, or auto", by)
+	}
+}
+
+// profileByXUID gets a profile by a decimal, hex (0x...), or Floodgate UUID XUID
+func (a *app) profileByXUID(ctx context.Context, id string) (*xblive.Profile, error) {
+	xuid, err := xblive.ParseXUID(id)
+	if err != nil {
+		return nil, err
+	}
+	return a.client.GetProfile(ctx, xuid)
+}
+
+// exitNoExactMatch is the exit code of lookup --fuzzy when only ranked candidates were found
+const exitNoExactMatch = 3
+
+func (a *app) handleLookup(ctx context.Context, inv *invocation) {
+	by := addByFlag(inv)
+	fuzzy := inv.Bool("fuzzy", false, "when no gamertag matches exactly, show ranked candidates from people search (exit status 3)")
+	maxItems := inv.Int("max", 10, "maximum number of candidates shown with --fuzzy")
+	friends := inv.Bool("friends", false, "search only your friends for --fuzzy candidates")
+	id := inv.parse()[0]
+
+	if *maxItems <= 0 {
+		fmt.Fprintf(a.stderr, "Error: --max must be positive\n")
+		inv.Usage()
+		a.exit(1)
+	}
+
+	fmt.Fprintf(a.stdout, "Looking up: %s\n", id)
+
+	profile, err := a.resolveProfile(ctx, id, *by)
+	if err != nil {
+		if !*fuzzy || !errors.Is(err, xblive.ErrNotFound) || *by == "xuid" || (*by == "auto" && looksLikeXUID(id)) {
+			a.fatal(ctx, "Lookup failed", err)
+		}
+		scope := xblive.SearchScopeGlobal
+		if *friends {
+			scope = xblive.SearchScopeFriends
+		}
+		a.printCandidates(ctx, id, xblive.SearchOptions{MaxItems: *maxItems, Scope: scope})
+		a.exit(exitNoExactMatch)
+	}
+
+	fmt.Fprintln(a.stdout)
+	a.out.success("Found!")
+	fmt.Fprintf(a.stdout, "  Gamertag:       %s\n", profile.Gamertag)
+	fmt.Fprintf(a.stdout, "  XUID:           %s\n", profile.XUID)
+	if hex, err := xblive.XUIDToHex(profile.XUID); err == nil {
+		fmt.Fprintf(a.stdout, "  Hex:            %s\n", hex)
+	}
+	if uuid, err := xblive.XUIDToFloodgateUUID(profile.XUID); err == nil {
+		fmt.Fprintf(a.stdout, "  Floodgate UUID: %s\n", uuid)
+	}
+}
+
+// printCandidates lists the people search results for a gamertag with no exact match, best match first
+func (a *app) printCandidates(ctx context.Context, gamertag string, opts xblive.SearchOptions) {
+	matches, _, err := a.client.FindPeople(ctx, gamertag, opts)
+	if err != nil {
+		a.fatal(ctx, "Candidate search failed", err)
+	}
+	if len(matches) == 0 {
+		a.fatal(ctx, "Lookup failed", fmt.Errorf("%w: no gamertag or candidates match '%s'", xblive.ErrNotFound, gamertag))
+	}
+
+	fmt.Fprintln(a.stdout)
+	a.out.warning("No exact match; %d candidates:", len(matches))
+	for i, m := range matches {
+		matched := m.Field
+		if matched == "" {
+			matched = "search"
+		}
+		fmt.Fprintf(a.stdout, "  %2d. %-16s %-20s (matched %s)\n", i+1, m.Profile.Gamertag, m.Profile.XUID, matched)
+	}
+}
+
+func (a *app) handleProfile(ctx context.Context, inv *invocation) {
+	by := addByFlag(inv)
+	id := inv.parse()[0]
+
+	fmt.Fprintf(a.stdout, "Looking up profile for: %s\n", id)
+
+	profile, err := a.resolveProfile(ctx, id, *by)
+	if err != nil {
+		a.fatal(ctx, "Profile lookup failed", err)
+	}
+
+	fmt.Fprintln(a.stdout)
+	a.out.success("Profile found!")
+	fmt.Fprintln(a.stdout)
+
+	// Pretty print as JSON
+	output, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		a.fatal(ctx, "Failed to format profile", err)
+	}
+	fmt.Fprintln(a.stdout, string(output))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// pruneCandidate is a friend with no sign of activity since the cutoff
+type pruneCandidate struct {
+	profile  *xblive.Profile
+	lastSeen *xblive.PresenceLastSeen // nil when presence doesn't say
+	hidden   bool                     // achievements are hidden by the friend's privacy settings
+}
+
+func (a *app) handleSocialPrune(ctx context.Context, inv *invocation) {
+	inactiveDays := inv.Int("inactive-days", 365, "suggest friends with no presence or achievement activity in this many days")
+	dryRun := inv.Bool("dry-run", false, "only list the suggestions; remove nothing")
+	yes := inv.Bool("yes", false, "remove every suggestion without prompting")
+	keep := inv.String("keep", "", "comma-separated XUIDs never to suggest")
+	keepHidden := inv.Bool("keep-hidden", false, "don't suggest friends whose achievements are hidden, since their activity can't be checked")
+	inv.parse()
+
+	if *inactiveDays <= 0 {
+		fmt.Fprintf(a.stderr, "Error: --inactive-days must be positive\n")
+		inv.Usage()
+		a.exit(1)
+	}
+	if !*dryRun && !*yes && !a.canPrompt() {
+		fmt.Fprintf(a.stderr, "Error: confirming removals needs a terminal; pass --dry-run or --yes\n")
+		a.exit(1)
+	}
+
+	keepXUIDs := make(map[string]bool)
+	for _, xuid := range strings.Split(*keep, ",") {
+		if xuid = strings.TrimSpace(xuid); xuid != "" {
+			keepXUIDs[xuid] = true
+		}
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -*inactiveDays)
+	candidates, err := a.pruneCandidates(ctx, cutoff, keepXUIDs, *keepHidden)
+	if err != nil {
+		a.fatal(ctx, "Failed to check friends", err)
+	}
+
+	fmt.Fprintf(a.stdout, "%d friends inactive for %d days:\n", len(candidates), *inactiveDays)
+	for _, c := range candidates {
+		fmt.Fprintf(a.stdout, "  %-16s %-20s %s\n", c.profile.XUID, c.profile.Gamertag, describeLastSeen(c))
+	}
+	if *dryRun || len(candidates) == 0 {
+		return
+	}
+
+	input := bufio.NewReader(a.stdin)
+	removeAll := *yes
+	removed := 0
+	for _, c := range candidates {
+		if !removeAll {
+			answer := a.prompt(input, fmt.Sprintf("Remove %s (%s)? [y/N/a(ll)/q(uit)] ", c.profile.Gamertag, describeLastSeen(c)))
+			switch answer {
+			case "y", "yes":
+			case "a", "all":
+				removeAll = true
+			case "q", "quit":
+				fmt.Fprintf(a.stdout, "Removed %d of %d\n", removed, len(candidates))
+				return
+			default:
+				continue
+			}
+		}
+
+		if err := a.client.RemoveFriend(ctx, c.profile.XUID); err != nil {
+			a.errOut.failure("remove %s %s: %v", c.profile.XUID, c.profile.Gamertag, err)
+			continue
+		}
+		a.out.success("removed %s %s", c.profile.XUID, c.profile.Gamertag)
+		removed++
+	}
+	fmt.Fprintf(a.stdout, "Removed %d of %d\n", removed, len(candidates))
+}
+
+// pruneCandidates returns the friends with no presence or achievement activity since cutoff, longest inactive first
+// Favorites and the XUIDs in keep are never suggested. Presence is checked first; achievements are only fetched
+// for friends presence can't vouch for
+func (a *app) pruneCandidates(ctx context.Context, cutoff time.Time, keep map[string]bool, keepHidden bool) ([]*pruneCandidate, error) {
+	friends, err := a.client.GetFriends(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var xuids []string
+	for _, f := range friends {
+		xuids = append(xuids, f.XUID)
+	}
+	presenceOf := make(map[string]*xblive.Presence)
+	if len(xuids) > 0 {
+		presence, err := a.client.GetPresence(ctx, xuids)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range presence {
+			presenceOf[p.XUID] = p
+		}
+	}
+
+	var candidates []*pruneCandidate
+	for _, f := range friends {
+		if f.IsFavorite || keep[f.XUID] {
+			continue
+		}
+
+		c := &pruneCandidate{profile: f}
+		if p := presenceOf[f.XUID]; p != nil {
+			if p.State.IsOnline() {
+				continue
+			}
+			if p.LastSeen != nil && !p.LastSeen.Timestamp.IsZero() {
+				if p.LastSeen.Timestamp.After(cutoff) {
+					continue
+				}
+				c.lastSeen = p.LastSeen
+			}
+		}
+
+		unlocks, err := a.client.GetRecentAchievements(ctx, f.XUID, cutoff)
+		switch {
+		case errors.Is(err, xblive.ErrForbidden):
+			if keepHidden {
+				continue
+			}
+			c.hidden = true
+		case err != nil:
+			return nil, fmt.Errorf("failed to check achievements of %s: %w", f.Gamertag, err)
+		case len(unlocks) > 0:
+			continue
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	// Longest inactive first; friends with no known last-seen time sort first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		x, y := candidates[i], candidates[j]
+		if x.lastSeen == nil || y.lastSeen == nil {
+			return x.lastSeen == nil && y.lastSeen != nil
+		}
+		return x.lastSeen.Timestamp.Before(y.lastSeen.Timestamp)
+	})
+	return candidates, nil
+}
+
+// describeLastSeen summarizes what is known of a candidate's last activity
+func describeLastSeen(c *pruneCandidate) string {
+	s := "last seen: unknown"
+	if c.lastSeen != nil {
+		s = "last seen " + c.lastSeen.Timestamp.Format("2006-01-02")
+		if c.lastSeen.TitleName != "" {
+			s += " in " + c.lastSeen.TitleName
+		}
+	}
+	if c.hidden {
+		s += ", achievements hidden"
+	}
+	return s
+}
+
+// canPrompt reports whether the app can ask the user questions
+func (a *app) canPrompt() bool {
+	f, ok := a.stdin.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// prompt asks a question and returns the lowercased answer; end of input answers no
+func (a *app) prompt(input *bufio.Reader, question string) string {
+	fmt.Fprint(a.stdout, question)
+	line, err := input.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(a.stdout)
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(line))
+}
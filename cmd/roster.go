@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tadhunt/xblive/social"
+)
+
+// defaultMaxRemovePercent is the share of the friend list roster reconcile removes without --force
+const defaultMaxRemovePercent = 25
+
+// rosterEntry is a member of a desired roster, identified by XUID or gamertag
+type rosterEntry struct {
+	XUID     string `json:"xuid"`
+	Gamertag string `json:"gamertag"`
+}
+
+func (a *app) handleRosterReconcile(ctx context.Context, inv *invocation) {
+	desired := inv.String("desired", "", "roster file: CSV with an xuid or gamertag column, or a JSON array of XUIDs or {\"xuid\",\"gamertag\"} objects (required)")
+	apply := inv.Bool("apply", false, "apply the changes; without it only the diff is printed")
+	keep := inv.String("keep", "", "comma-separated XUIDs never to remove")
+	allowEmpty := inv.Bool("allow-empty", false, "accept an empty roster, which removes every friend not kept")
+	maxRemove := inv.Int("max-remove", defaultMaxRemovePercent, "percentage of friends --apply may remove without --force")
+	force := inv.Bool("force", false, "apply even if more than --max-remove percent of friends would be removed")
+	inv.parse()
+
+	if *desired == "" {
+		fmt.Fprintf(a.stderr, "Error: --desired is required\n")
+		inv.Usage()
+		a.exit(1)
+	}
+
+	if *maxRemove < 0 || *maxRemove > 100 {
+		fmt.Fprintf(a.stderr, "Error: --max-remove must be between 0 and 100\n")
+		inv.Usage()
+		a.exit(1)
+	}
+
+	entries, err := readRoster(*desired)
+	if err != nil {
+		a.fatal(ctx, "Failed to read roster", err)
+	}
+	xuids := a.rosterXUIDs(ctx, entries)
+
+	// A truncated or empty roster file would otherwise wipe the whole friend list
+	if len(xuids) == 0 && !*allowEmpty {
+		a.fatal(ctx, "Refusing to reconcile", fmt.Errorf("roster %s is empty; pass --allow-empty to remove every friend", *desired))
+	}
+
+	var keepXUIDs []string
+	if *keep != "" {
+		keepXUIDs = strings.Split(*keep, ",")
+	}
+
+	opts := social.Options{
+		DryRun: true,
+		Keep:   keepXUIDs,
+	}
+	result, err := social.Sync(ctx, a.client, xuids, opts)
+	if err != nil {
+		a.fatal(ctx, "Failed to compute roster diff", err)
+	}
+
+	plan := result.Plan
+	for _, change := range plan.Remove {
+		fmt.Fprintf(a.stdout, "- %s %s\n", change.XUID, change.Gamertag)
+	}
+	for _, change := range plan.Add {
+		fmt.Fprintf(a.stdout, "+ %s\n", change.XUID)
+	}
+	fmt.Fprintf(a.stdout, "%d to add, %d to remove, %d unchanged\n", len(plan.Add), len(plan.Remove), plan.Unchanged)
+
+	if !*apply || plan.Empty() {
+		return
+	}
+
+	if friends := len(plan.Remove) + plan.Unchanged; !*force && len(plan.Remove)*100 > friends**maxRemove {
+		a.fatal(ctx, "Refusing to apply roster", fmt.Errorf("it would remove %d of %d friends, more than %d%%; pass --force to apply", len(plan.Remove), friends, *maxRemove))
+	}
+
+	opts.DryRun = false
+	opts.OnChange = func(change social.Change, err error) {
+		if err != nil {
+			a.errOut.failure("%s %s: %v", change.Action, change.XUID, err)
+			return
+		}
+		a.out.success("%s %s", change.Action, change.XUID)
+	}
+	if _, err := social.Sync(ctx, a.client, xuids, opts); err != nil {
+		a.fatal(ctx, "Roster reconcile failed", err)
+	}
+}
+
+// rosterXUIDs returns the XUIDs of a roster, resolving entries given only by gamertag
+func (a *app) rosterXUIDs(ctx context.Context, entries []rosterEntry) []string {
+	var xuids, gamertags []string
+	for _, e := range entries {
+		switch {
+		case e.XUID != "":
+			xuids = append(xuids, e.XUID)
+		case e.Gamertag != "":
+			gamertags = append(gamertags, e.Gamertag)
+		}
+	}
+	if len(gamertags) == 0 {
+		return xuids
+	}
+
+	resolved, fuzzyOnly, err := a.client.GamertagsToXUIDs(ctx, gamertags)
+	if err != nil {
+		a.fatal(ctx, "Failed to resolve roster gamertags", err)
+	}
+	if len(fuzzyOnly) > 0 {
+		a.fatal(ctx, "Failed to resolve roster gamertags", fmt.Errorf("no exact match for %s", strings.Join(fuzzyOnly, ", ")))
+	}
+	for _, gt := range gamertags {
+		xuid, ok := resolved[gt]
+		if !ok {
+			a.fatal(ctx, "Failed to resolve roster gamertags", fmt.Errorf("gamertag not found: %s", gt))
+		}
+		xuids = append(xuids, xuid)
+	}
+	return xuids
+}
+
+// readRoster reads a CSV or JSON roster file, chosen by extension
+func readRoster(path string) ([]rosterEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return readJSONRoster(f)
+	}
+	return readCSVRoster(f)
+}
+
+// readJSONRoster reads a JSON array of XUID strings or roster entry objects
+func readJSONRoster(r io.Reader) ([]rosterEntry, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON roster: %w", err)
+	}
+
+	entries := make([]rosterEntry, 0, len(raw))
+	for _, item := range raw {
+		var xuid string
+		if err := json.Unmarshal(item, &xuid); err == nil {
+			entries = append(entries, rosterEntry{XUID: xuid})
+			continue
+		}
+		var e rosterEntry
+		if err := json.Unmarshal(item, &e); err != nil {
+			return nil, fmt.Errorf("invalid JSON roster entry %s: %w", item, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// readCSVRoster reads a CSV roster whose header has an xuid and/or gamertag column
+func readCSVRoster(r io.Reader) ([]rosterEntry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV roster: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	xuidCol, gamertagCol := -1, -1
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "xuid":
+			xuidCol = i
+		case "gamertag":
+			gamertagCol = i
+		}
+	}
+	if xuidCol < 0 && gamertagCol < 0 {
+		return nil, fmt.Errorf("CSV roster needs an xuid or gamertag column")
+	}
+
+	entries := make([]rosterEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var e rosterEntry
+		if xuidCol >= 0 && xuidCol < len(record) {
+			e.XUID = strings.TrimSpace(record[xuidCol])
+		}
+		if gamertagCol >= 0 && gamertagCol < len(record) {
+			e.Gamertag = strings.TrimSpace(record[gamertagCol])
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// maxServeBatch caps the number of gamertags or XUIDs accepted by one batch request
+const maxServeBatch = 100
+
+// route is a REST proxy endpoint
+// A path ending in "/" takes the rest of the URL path as its single path parameter
+type route struct {
+	name     string // operation ID in the OpenAPI document
+	path     string
+	summary  string
+	params   []routeParam
+	response interface{}   // zero value of the response type, described in the OpenAPI document
+	ttl      time.Duration // how long responses are cached and may be cached by clients; 0 disables caching
+	private  bool          // responses describe the authenticated account, so shared caches must not store them
+	handle   func(ctx context.Context, client xboxClient, r *http.Request, param string) (interface{}, error)
+}
+
+// routeParam is a path or query parameter of a route
+type routeParam struct {
+	name        string
+	in          string // "path" or "query"
+	description string
+}
+
+// serveRoutes are the endpoints of the REST proxy
+var serveRoutes = []*route{
+	{
+		name:     "lookup",
+		ttl:      time.Hour,
+		path:     "/v1/lookup/",
+		summary:  "Convert a gamertag to a XUID",
+		params:   []routeParam{{name: "gamertag", in: "path", description: "Gamertag to look up"}},
+		response: lookupResult{},
+		handle:   serveLookup,
+	},
+	{
+		name:     "batch",
+		ttl:      time.Hour,
+		path:     "/v1/batch",
+		summary:  "Convert gamertags to XUIDs",
+		params:   []routeParam{{name: "gamertags", in: "query", description: "Comma-separated gamertags"}},
+		response: batchResult{},
+		handle:   serveBatch,
+	},
+	{
+		name:     "profile",
+		ttl:      5 * time.Minute,
+		path:     "/v1/profile/",
+		summary:  "Get a profile by XUID",
+		params:   []routeParam{{name: "xuid", in: "path", description: "XUID in decimal, hex, or Floodgate UUID form"}},
+		response: &xblive.Profile{},
+		handle:   serveProfile,
+	},
+	{
+		name:     "presence",
+		ttl:      30 * time.Second,
+		path:     "/v1/presence",
+		summary:  "Get the presence of users",
+		params:   []routeParam{{name: "xuids", in: "query", description: "Comma-separated XUIDs"}},
+		response: []*xblive.Presence{},
+		handle:   servePresence,
+	},
+	{
+		name:     "friends",
+		ttl:      time.Minute,
+		private:  true,
+		path:     "/v1/friends",
+		summary:  "List the authenticated user's friends",
+		response: []*xblive.Profile{},
+		handle:   serveFriends,
+	},
+}
+
+// lookupResult is the response of the lookup endpoint
+type lookupResult struct {
+	Gamertag string `json:"gamertag"`
+	XUID     string `json:"xuid"`
+}
+
+// batchResult is the response of the batch endpoint
+type batchResult struct {
+	Results   map[string]string `json:"results"`
+	FuzzyOnly []string          `json:"fuzzyOnly"`
+}
+
+// serveError is the JSON body of an error response
+type serveError struct {
+	Error string `json:"error"`
+}
+
+func (a *app) handleServe(ctx context.Context, inv *invocation) {
+	listen := inv.String("listen", "127.0.0.1:8080", "address to serve the REST API on")
+	keysFile := inv.String("api-keys", "", "file of API keys, one 'name key [requests-per-minute]' per line")
+	tlsCert := inv.String("tls-cert", "", "TLS certificate file")
+	tlsKey := inv.String("tls-key", "", "TLS private key file")
+	clientCA := inv.String("client-ca", "", "CA file for verifying client certificates (mTLS); requires --tls-cert and --tls-key")
+	rate := inv.Int("rate", 60, "requests per minute allowed per API key or client certificate without its own limit; 0 for unlimited")
+	insecure := inv.Bool("insecure-no-auth", false, "allow serving on a non-loopback address without API keys or mTLS")
+	ttls := inv.String("ttl", "", "per-route cache TTL overrides, e.g. 'profile=10m,presence=0'; 'all=0' disables caching")
+	inv.parse()
+
+	if err := parseTTLs(*ttls, serveRoutes); err != nil {
+		a.fatal(ctx, "Invalid serve configuration", err)
+	}
+
+	auth, err := newServeAuth(serveAuthConfig{
+		keysFile:    *keysFile,
+		tlsCert:     *tlsCert,
+		tlsKey:      *tlsKey,
+		clientCA:    *clientCA,
+		defaultRate: *rate,
+	})
+	if err != nil {
+		a.fatal(ctx, "Invalid serve configuration", err)
+	}
+	if !auth.enabled() && !*insecure && !isLoopback(*listen) {
+		fmt.Fprintf(a.stderr, "Error: refusing to serve an authenticated Xbox proxy on %s without --api-keys or --client-ca\n", *listen)
+		fmt.Fprintf(a.stderr, "Bind to a loopback address, configure access control, or pass --insecure-no-auth\n")
+		a.exit(1)
+	}
+
+	cache := newResponseCache()
+	mux := http.NewServeMux()
+	mux.Handle(openAPIPath, openAPIHandler())
+	for _, rt := range serveRoutes {
+		mux.Handle(rt.path, auth.wrap(a.routeHandler(rt, cache, auth.enabled())))
+	}
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		TLSConfig:         auth.tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Fprintf(a.stderr, "Serving the REST API on %s (%s)\n", *listen, auth.describe())
+	if auth.tlsConfig != nil {
+		err = server.ListenAndServeTLS(*tlsCert, *tlsKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil {
+		a.fatal(ctx, "Server failed", err)
+	}
+}
+
+// routeHandler adapts a route to an HTTP handler writing JSON, serving repeat requests from cache for the route's TTL
+// authenticated reports whether the server requires API keys or client certificates
+func (a *app) routeHandler(rt *route, cache *responseCache, authenticated bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSON(w, http.StatusMethodNotAllowed, serveError{Error: "method not allowed"})
+			return
+		}
+
+		var param string
+		if strings.HasSuffix(rt.path, "/") {
+			param = strings.TrimPrefix(r.URL.Path, rt.path)
+			if param == "" || strings.Contains(param, "/") {
+				writeJSON(w, http.StatusNotFound, serveError{Error: "not found"})
+				return
+			}
+		} else if r.URL.Path != rt.path {
+			writeJSON(w, http.StatusNotFound, serveError{Error: "not found"})
+			return
+		}
+
+		key := cacheKey(rt, param, r)
+		now := time.Now()
+		if entry, ok := cache.get(key, now); ok {
+			writeCached(w, r, rt, entry, true, now, authenticated)
+			return
+		}
+
+		body, err := encodeRoute(r.Context(), rt, a.client, r, param)
+		if err != nil {
+			w.Header().Set("Cache-Control", "no-store")
+			writeJSON(w, serveStatus(err), serveError{Error: err.Error()})
+			return
+		}
+
+		now = time.Now()
+		entry := cachedResponse{body: body, etag: etagOf(body), expires: now.Add(rt.ttl)}
+		if rt.ttl > 0 {
+			cache.put(key, entry, now)
+		}
+		writeCached(w, r, rt, entry, false, now, authenticated)
+	})
+}
+
+// encodeRoute runs a route's handler and encodes its result
+func encodeRoute(ctx context.Context, rt *route, client xboxClient, r *http.Request, param string) ([]byte, error) {
+	result, err := rt.handle(ctx, client, r, param)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return append(body, '\n'), nil
+}
+
+// serveStatus maps an error to an HTTP status code
+func serveStatus(err error) int {
+	var badRequest *badRequestError
+	switch {
+	case errors.As(err, &badRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, xblive.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, xblive.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, xblive.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case xblive.IsTemporary(err):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadGateway
+}
+
+// badRequestError is a problem with the request itself
+type badRequestError struct {
+	msg string
+}
+
+func (e *badRequestError) Error() string {
+	return e.msg
+}
+
+// listParam returns a required comma-separated query parameter
+func listParam(r *http.Request, name string) ([]string, error) {
+	var values []string
+	for _, v := range strings.Split(r.URL.Query().Get(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, &badRequestError{msg: fmt.Sprintf("%s is required", name)}
+	}
+	if len(values) > maxServeBatch {
+		return nil, &badRequestError{msg: fmt.Sprintf("at most %d %s are allowed per request", maxServeBatch, name)}
+	}
+	return values, nil
+}
+
+func serveLookup(ctx context.Context, client xboxClient, r *http.Request, gamertag string) (interface{}, error) {
+	profile, err := client.LookupProfileByGamertag(ctx, gamertag)
+	if err != nil {
+		return nil, err
+	}
+	return lookupResult{Gamertag: profile.Gamertag, XUID: profile.XUID}, nil
+}
+
+func serveBatch(ctx context.Context, client xboxClient, r *http.Request, _ string) (interface{}, error) {
+	gamertags, err := listParam(r, "gamertags")
+	if err != nil {
+		return nil, err
+	}
+	results, fuzzyOnly, err := client.GamertagsToXUIDs(ctx, gamertags)
+	if err != nil {
+		return nil, err
+	}
+	if fuzzyOnly == nil {
+		fuzzyOnly = []string{}
+	}
+	return batchResult{Results: results, FuzzyOnly: fuzzyOnly}, nil
+}
+
+func serveProfile(ctx context.Context, client xboxClient, r *http.Request, xuid string) (interface{}, error) {
+	xuid, err := xblive.ParseXUID(xuid)
+	if err != nil {
+		return nil, &badRequestError{msg: err.Error()}
+	}
+	return client.GetProfile(ctx, xuid)
+}
+
+func servePresence(ctx context.Context, client xboxClient, r *http.Request, _ string) (interface{}, error) {
+	xuids, err := listParam(r, "xuids")
+	if err != nil {
+		return nil, err
+	}
+	return client.GetPresence(ctx, xuids)
+}
+
+func serveFriends(ctx context.Context, client xboxClient, r *http.Request, _ string) (interface{}, error) {
+	return client.GetFriends(ctx)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// isLoopback reports whether a listen address only accepts local connections
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
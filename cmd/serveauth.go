@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// serveAuthConfig configures access control for the REST proxy
+type serveAuthConfig struct {
+	keysFile    string
+	tlsCert     string
+	tlsKey      string
+	clientCA    string
+	defaultRate int
+}
+
+// apiKey is a configured API key, kept only as a hash
+type apiKey struct {
+	name string
+	hash [sha256.Size]byte
+	rate int
+}
+
+// serveAuth authenticates REST proxy requests by API key or client certificate and rate-limits each caller
+type serveAuth struct {
+	keys        []apiKey
+	tlsConfig   *tls.Config
+	requireCert bool
+	defaultRate int
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newServeAuth loads API keys and TLS settings
+func newServeAuth(config serveAuthConfig) (*serveAuth, error) {
+	if config.defaultRate < 0 {
+		return nil, fmt.Errorf("--rate must not be negative")
+	}
+	if (config.tlsCert == "") != (config.tlsKey == "") {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	if config.clientCA != "" && config.tlsCert == "" {
+		return nil, fmt.Errorf("--client-ca requires --tls-cert and --tls-key")
+	}
+
+	auth := &serveAuth{defaultRate: config.defaultRate, buckets: make(map[string]*tokenBucket)}
+
+	if config.keysFile != "" {
+		keys, err := readAPIKeys(config.keysFile)
+		if err != nil {
+			return nil, err
+		}
+		auth.keys = keys
+	}
+
+	if config.tlsCert != "" {
+		auth.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if config.clientCA != "" {
+		pem, err := os.ReadFile(config.clientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", config.clientCA)
+		}
+		auth.tlsConfig.ClientCAs = pool
+		auth.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		auth.requireCert = true
+	}
+
+	return auth, nil
+}
+
+// readAPIKeys reads "name key [requests-per-minute]" lines, skipping blank lines and # comments
+func readAPIKeys(path string) ([]apiKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	defer f.Close()
+
+	var keys []apiKey
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("%s:%d: expected 'name key [requests-per-minute]'", path, n)
+		}
+		if names[fields[0]] {
+			return nil, fmt.Errorf("%s:%d: duplicate key name %s", path, n, fields[0])
+		}
+		names[fields[0]] = true
+
+		key := apiKey{name: fields[0], hash: sha256.Sum256([]byte(fields[1])), rate: -1}
+		if len(fields) == 3 {
+			rate, err := strconv.Atoi(fields[2])
+			if err != nil || rate < 0 {
+				return nil, fmt.Errorf("%s:%d: invalid rate %q", path, n, fields[2])
+			}
+			key.rate = rate
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no API keys in %s", path)
+	}
+	return keys, nil
+}
+
+// enabled reports whether any access control is configured
+func (s *serveAuth) enabled() bool {
+	return len(s.keys) > 0 || s.requireCert
+}
+
+// describe summarizes the access control for the startup message
+func (s *serveAuth) describe() string {
+	var parts []string
+	if len(s.keys) > 0 {
+		parts = append(parts, fmt.Sprintf("%d API keys", len(s.keys)))
+	}
+	if s.requireCert {
+		parts = append(parts, "client certificates")
+	}
+	if len(parts) == 0 {
+		return "no authentication"
+	}
+	return "authenticated by " + strings.Join(parts, " or ")
+}
+
+// wrap authenticates and rate-limits requests before passing them to next
+// With both API keys and mTLS configured, a verified client certificate or a valid API key is enough
+func (s *serveAuth) wrap(next http.Handler) http.Handler {
+	if !s.enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, rate, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="xblive"`)
+			writeJSON(w, http.StatusUnauthorized, serveError{Error: "missing or invalid API key"})
+			return
+		}
+		if wait := s.take(caller, rate); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeJSON(w, http.StatusTooManyRequests, serveError{Error: "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate identifies the caller by API key (Authorization: Bearer or X-API-Key) or verified client certificate
+// Returns: caller identity, requests per minute allowed, whether the caller is authenticated
+func (s *serveAuth) authenticate(r *http.Request) (string, int, bool) {
+	if token := requestAPIKey(r); token != "" {
+		hash := sha256.Sum256([]byte(token))
+		for _, key := range s.keys {
+			if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 {
+				rate := key.rate
+				if rate < 0 {
+					rate = s.defaultRate
+				}
+				return "key:" + key.name, rate, true
+			}
+		}
+		return "", 0, false
+	}
+
+	if s.requireCert && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return "cert:" + r.TLS.PeerCertificates[0].Subject.CommonName, s.defaultRate, true
+	}
+	return "", 0, false
+}
+
+// requestAPIKey returns the API key a request carries, if any
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
+
+// take spends one request from a caller's budget, returning how long to wait if it is exhausted
+func (s *serveAuth) take(caller string, perMinute int) time.Duration {
+	if perMinute == 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[caller]
+	if !ok {
+		b = &tokenBucket{tokens: float64(perMinute), last: time.Now()}
+		s.buckets[caller] = b
+	}
+	return b.take(perMinute, time.Now())
+}
+
+// tokenBucket allows bursts of up to a minute's worth of requests, refilling continuously
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take spends a token if one is available, or returns the time until one will be
+func (b *tokenBucket) take(perMinute int, now time.Time) time.Duration {
+	rate := float64(perMinute) / time.Minute.Seconds()
+	b.tokens = math.Min(float64(perMinute), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
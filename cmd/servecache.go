@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxServeCacheEntries bounds the response cache; once full, new responses are served but not cached until entries expire
+const maxServeCacheEntries = 10000
+
+// cachedResponse is a successful response body with its validator and expiry
+type cachedResponse struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// responseCache holds encoded route responses until their route's TTL passes
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]cachedResponse)}
+}
+
+// get returns an unexpired response
+func (c *responseCache) get(key string, now time.Time) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return cachedResponse{}, false
+	}
+	if !now.Before(entry.expires) {
+		delete(c.entries, key)
+		return cachedResponse{}, false
+	}
+	return entry, true
+}
+
+// put stores a response, sweeping expired entries when the cache is full
+func (c *responseCache) put(key string, entry cachedResponse, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxServeCacheEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxServeCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = entry
+}
+
+// cacheKey identifies a route response by its parameter and query
+func cacheKey(rt *route, param string, r *http.Request) string {
+	return rt.name + "\x00" + param + "\x00" + r.URL.Query().Encode()
+}
+
+// etagOf is a strong validator for a response body
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// cacheControl is the Cache-Control header for a response with the given time left to live
+// Responses about the authenticated account, and every response when the server requires API keys or client
+// certificates, are private so a shared cache can't hand them to unauthenticated callers; public lookups on an open
+// server may be cached by shared caches and CDNs
+func cacheControl(rt *route, remaining time.Duration, authenticated bool) string {
+	if rt.ttl <= 0 {
+		return "no-store"
+	}
+	scope := "public"
+	if rt.private || authenticated {
+		scope = "private"
+	}
+	return fmt.Sprintf("%s, max-age=%d", scope, int(remaining.Seconds()))
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCached writes a successful response with caching headers, or 304 if the client already has it
+// When the server authenticates callers, responses vary by the credentials they were requested with
+func writeCached(w http.ResponseWriter, r *http.Request, rt *route, entry cachedResponse, hit bool, now time.Time, authenticated bool) {
+	h := w.Header()
+	h.Set("ETag", entry.etag)
+	h.Set("Cache-Control", cacheControl(rt, entry.expires.Sub(now), authenticated))
+	if authenticated {
+		h.Set("Vary", "Authorization, X-API-Key")
+	}
+	if rt.ttl > 0 {
+		h.Set("Age", strconv.Itoa(int((rt.ttl - entry.expires.Sub(now)).Seconds())))
+		if hit {
+			h.Set("X-Cache", "HIT")
+		} else {
+			h.Set("X-Cache", "MISS")
+		}
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), entry.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(entry.body)
+}
+
+// parseTTLs applies "route=duration" overrides, comma-separated, to the routes' TTLs
+func parseTTLs(spec string, routes []*route) error {
+	if spec == "" {
+		return nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return fmt.Errorf("invalid TTL %q: expected route=duration", item)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return fmt.Errorf("invalid TTL %q for %s", value, name)
+		}
+
+		found := false
+		for _, rt := range routes {
+			if rt.name == name || name == "all" {
+				rt.ttl = ttl
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown route %q in TTLs", name)
+		}
+	}
+	return nil
+}
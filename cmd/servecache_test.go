@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteCacheHeaders(t *testing.T) {
+	var lookup, friends *route
+	for _, rt := range serveRoutes {
+		switch rt.name {
+		case "lookup":
+			lookup = rt
+		case "friends":
+			friends = rt
+		}
+	}
+
+	tests := []struct {
+		name          string
+		rt            *route
+		path          string
+		authenticated bool
+		scope         string
+		vary          string
+	}{
+		{name: "open lookup", rt: lookup, path: "/v1/lookup/MajorNelson", scope: "public"},
+		{name: "open friends", rt: friends, path: friends.path, scope: "private"},
+		{name: "authenticated lookup", rt: lookup, path: "/v1/lookup/MajorNelson", authenticated: true, scope: "private", vary: "Authorization, X-API-Key"},
+		{name: "authenticated friends", rt: friends, path: friends.path, authenticated: true, scope: "private", vary: "Authorization, X-API-Key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &app{client: newFakeClient()}
+			handler := a.routeHandler(tt.rt, newResponseCache(), tt.authenticated)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+
+			if got := w.Header().Get("Cache-Control"); !strings.HasPrefix(got, tt.scope+", max-age=") {
+				t.Errorf("Cache-Control = %q; want %s", got, tt.scope)
+			}
+			if got := w.Header().Get("Vary"); got != tt.vary {
+				t.Errorf("Vary = %q; want %q", got, tt.vary)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// exitServiceImpacted is the status command's exit code when the Xbox network is degraded
+	exitServiceImpacted = 3
+
+	// exitAuthFailed is the status command's exit code when the Xbox network is up but authentication fails
+	exitAuthFailed = 4
+)
+
+func (a *app) handleStatus(ctx context.Context, inv *invocation) {
+	checkAuth := inv.Bool("check-auth", false, "also verify the cached credentials")
+	inv.parse()
+
+	status, err := a.client.ServiceStatus(ctx)
+	if err != nil {
+		a.fatal(ctx, "Failed to get service status", err)
+	}
+
+	if status.Healthy() {
+		a.out.success("Xbox network is up")
+	} else {
+		a.out.warning("Xbox network: %s", status.Overall())
+		for _, category := range status.Impacted() {
+			fmt.Fprintf(a.stdout, "  %s: %s\n", category.Name, category.Status.Name)
+			for _, scenario := range category.Scenarios {
+				for _, incident := range scenario.Incidents {
+					fmt.Fprintf(a.stdout, "    %s: %s\n", scenario.Name, incident.Message)
+				}
+			}
+		}
+	}
+
+	if *checkAuth {
+		if _, err := a.client.Identity(ctx); err != nil {
+			a.out.failure("Credentials: %v", err)
+			if status.Healthy() {
+				a.exit(exitAuthFailed)
+			}
+		} else {
+			a.out.success("Credentials are valid")
+		}
+	}
+
+	if !status.Healthy() {
+		a.exit(exitServiceImpacted)
+	}
+}
@@ -0,0 +1,148 @@
+package xblive
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// maxExpiryMargin bounds Config.ExpiryMargin: access tokens live about an hour, so a larger margin
+// would treat every token as expired the moment it was issued
+const maxExpiryMargin = time.Hour
+
+var (
+	// clientIDPattern matches Entra ID application IDs (GUIDs) and legacy 16-hex-digit Microsoft account client IDs
+	clientIDPattern = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16})$`)
+
+	// tenantPattern matches tenant names (consumers, common, organizations), tenant GUIDs, and tenant domain names
+	tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*$`)
+
+	// languagePattern matches BCP 47 language tags such as "en", "de-DE", or "zh-Hant-TW"
+	languagePattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+)
+
+// Validate checks a Config for missing, malformed, and conflicting settings
+// Every problem found is reported, joined into one error. New and NewClientPool call it
+func (c Config) Validate() error {
+	var errs []error
+
+	switch {
+	case c.ClientID == "":
+		errs = append(errs, fmt.Errorf("client ID is required"))
+	case !clientIDPattern.MatchString(c.ClientID):
+		errs = append(errs, fmt.Errorf("client ID %q is not an application ID (a GUID such as 00000000-0000-0000-0000-000000000000)", c.ClientID))
+	}
+
+	if c.Tenant != "" && !tenantPattern.MatchString(c.Tenant) {
+		errs = append(errs, fmt.Errorf("tenant %q must be a tenant name, GUID, or domain", c.Tenant))
+	}
+
+	if c.RedirectURI != "" {
+		if u, err := url.Parse(c.RedirectURI); err != nil || u.Scheme == "" {
+			errs = append(errs, fmt.Errorf("redirect URI %q must be an absolute URI", c.RedirectURI))
+		}
+	}
+
+	if c.Cache != nil && c.CachePath != "" {
+		errs = append(errs, fmt.Errorf("cache path is ignored when a cache is set; set only one"))
+	}
+	if c.Transport != nil && c.Failover != nil {
+		errs = append(errs, fmt.Errorf("transport is ignored when failover is set; set only one"))
+	}
+	if c.AppVersion != "" && c.AppName == "" {
+		errs = append(errs, fmt.Errorf("app version is set without an app name"))
+	}
+
+	if c.ExpiryMargin >= maxExpiryMargin {
+		errs = append(errs, fmt.Errorf("expiry margin %s must be less than %s", c.ExpiryMargin, maxExpiryMargin))
+	}
+
+	for service, version := range c.ContractVersions {
+		if _, ok := DefaultContractVersions[service]; !ok {
+			errs = append(errs, fmt.Errorf("contract version set for unknown service %q", service))
+		}
+		if n, err := strconv.Atoi(version); err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("contract version %q for %s must be a positive integer", version, service))
+		}
+	}
+
+	if c.Failover != nil {
+		errs = append(errs, c.Failover.validate()...)
+	}
+	if c.Quota != nil {
+		errs = append(errs, c.Quota.validate()...)
+	}
+	if c.Profile != "" {
+		if _, ok := c.Profile.Tuning(); !ok {
+			errs = append(errs, fmt.Errorf("unknown profile %q: must be %s, %s, or %s", c.Profile, ProfileInteractive, ProfileBulk, ProfileServer))
+		}
+		if c.Tuning != nil {
+			errs = append(errs, fmt.Errorf("profile is ignored when tuning is set; set only one"))
+		}
+	}
+	if c.Tuning != nil {
+		errs = append(errs, c.Tuning.validate()...)
+	}
+
+	if c.Language != "" && !languagePattern.MatchString(c.Language) {
+		errs = append(errs, fmt.Errorf("language %q is not a language tag such as en or pt-BR", c.Language))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validate checks the failover hosts and addresses
+func (f *EndpointFailover) validate() []error {
+	var errs []error
+	if f.Cooldown < 0 {
+		errs = append(errs, fmt.Errorf("failover cooldown must not be negative"))
+	}
+	for host, addrs := range f.Hosts {
+		if host == "" || !tenantPattern.MatchString(host) {
+			errs = append(errs, fmt.Errorf("failover host %q is not a host name", host))
+		}
+		if len(addrs) == 0 {
+			errs = append(errs, fmt.Errorf("failover host %s has no fallback addresses", host))
+		}
+		for _, addr := range addrs {
+			if !validFailoverAddress(addr) {
+				errs = append(errs, fmt.Errorf("failover address %q for %s must be an IP or host name with an optional :port", addr, host))
+			}
+		}
+	}
+	return errs
+}
+
+// validFailoverAddress reports whether addr is an IP or host name, with an optional port
+func validFailoverAddress(addr string) bool {
+	host := addr
+	if h, port, err := net.SplitHostPort(addr); err == nil {
+		n, err := strconv.Atoi(port)
+		if err != nil || n <= 0 || n > 65535 {
+			return false
+		}
+		host = h
+	}
+	return net.ParseIP(host) != nil || tenantPattern.MatchString(host)
+}
+
+// validate checks the quota window and limits
+func (q *Quota) validate() []error {
+	var errs []error
+	if q.Window < 0 {
+		errs = append(errs, fmt.Errorf("quota window must not be negative"))
+	}
+	if q.DefaultLimit < 0 {
+		errs = append(errs, fmt.Errorf("quota default limit must not be negative"))
+	}
+	for service, limit := range q.Limits {
+		if limit < 0 {
+			errs = append(errs, fmt.Errorf("quota limit for %s must not be negative", service))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// Remote management (SmartGlass) endpoint
+	consoleEndpoint = "https://xccs.xboxlive.com"
+
+	// consoleSourceID identifies the sender of remote management commands
+	consoleSourceID = "com.microsoft.smartglass"
+)
+
+// Console is a console registered to the authenticated user
+type Console struct {
+	ID                      string `json:"id"`
+	Name                    string `json:"name"`
+	ConsoleType             string `json:"consoleType"`
+	PowerState              string `json:"powerState"`
+	ConsoleStreamingEnabled bool   `json:"consoleStreamingEnabled"`
+	RemoteManagementEnabled bool   `json:"remoteManagementEnabled"`
+}
+
+// ConsoleCommand is a remote management command
+type ConsoleCommand struct {
+	Type       string
+	Command    string
+	Parameters []interface{}
+}
+
+// ConsoleRecordClip records a game clip of the foreground game; see RecordConsoleClip
+var ConsoleRecordClip = ConsoleCommand{Type: "Game", Command: "RecordGameDvr"}
+
+// maxConsoleClip is the longest clip a console can record from its buffer
+const maxConsoleClip = 5 * time.Minute
+
+// ListConsoles returns the consoles registered to the authenticated user
+// Consoles accept commands only when RemoteManagementEnabled is set ("Remote features" in the console's settings)
+func (c *Client) ListConsoles(ctx context.Context) ([]*Console, error) {
+	var resp ConsoleListResponse
+	endpoint := consoleEndpoint + "/lists/devices?queryCurrentDevice=false&includeStorageDevices=true"
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceConsoles, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list consoles: %w", err)
+	}
+	return resp.Result, nil
+}
+
+// SendConsoleCommand sends a remote management command to a registered console
+// The console must be on, or in instant-on mode, with remote features enabled. Returns the operation ID
+func (c *Client) SendConsoleCommand(ctx context.Context, consoleID string, command ConsoleCommand) (string, error) {
+	if consoleID == "" {
+		return "", fmt.Errorf("console ID is required")
+	}
+	if command.Type == "" || command.Command == "" {
+		return "", fmt.Errorf("command type and name are required")
+	}
+
+	sessionID, err := newSessionName()
+	if err != nil {
+		return "", err
+	}
+	parameters := command.Parameters
+	if parameters == nil {
+		parameters = []interface{}{}
+	}
+
+	reqBody := ConsoleCommandRequest{
+		Destination:  "Xbox",
+		Type:         command.Type,
+		Command:      command.Command,
+		SessionID:    sessionID,
+		SourceID:     consoleSourceID,
+		Parameters:   parameters,
+		LinkedXboxID: consoleID,
+	}
+
+	var resp ConsoleCommandResponse
+	err = c.mutate(ctx, "console_command", "", func(ctx context.Context) error {
+		if err := c.xblRequest(ctx, "POST", consoleEndpoint+"/commands", ServiceConsoles, reqBody, &resp); err != nil {
+			return fmt.Errorf("failed to send %s command to console: %w", command.Command, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Status != nil && resp.Status.ErrorCode != "" && resp.Status.ErrorCode != "OK" {
+		return "", fmt.Errorf("console rejected %s command: %s %s", command.Command, resp.Status.ErrorCode, resp.Status.ErrorMessage)
+	}
+
+	return resp.OpID, nil
+}
+
+// RecordConsoleClip saves the last length of gameplay on a console as a game clip, like pressing "record what happened"
+// The clip appears in GetGameClips once the console uploads it. Screenshots can't be triggered remotely and must be
+// taken on the console itself. Returns the operation ID
+func (c *Client) RecordConsoleClip(ctx context.Context, consoleID string, length time.Duration) (string, error) {
+	if length <= 0 || length > maxConsoleClip {
+		return "", fmt.Errorf("clip length must be between 0 and %s", maxConsoleClip)
+	}
+
+	command := ConsoleRecordClip
+	command.Parameters = []interface{}{map[string]int{
+		"startTimeDelta": -int(length.Seconds()),
+		"endTimeDelta":   0,
+	}}
+	return c.SendConsoleCommand(ctx, consoleID, command)
+}
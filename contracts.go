@@ -0,0 +1,110 @@
+package xblive
+
+//go:generate go run ./internal/gencoverage
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Service identifies an Xbox Live service; all of a service's endpoints share one contract version
+type Service string
+
+const (
+	ServiceAchievements     Service = "achievements"
+	ServiceActivity         Service = "activity"
+	ServiceConsoles         Service = "consoles"
+	ServiceGameClips        Service = "gameclips"
+	ServiceGamerpics        Service = "gamerpics"
+	ServiceLeaderboards     Service = "leaderboards"
+	ServiceMessaging        Service = "messaging"
+	ServicePeopleHub        Service = "peoplehub"
+	ServicePresence         Service = "presence"
+	ServiceProfile          Service = "profile"
+	ServiceScreenshots      Service = "screenshots"
+	ServiceSessionDirectory Service = "sessiondirectory"
+	ServiceSocial           Service = "social"
+	ServiceStats            Service = "stats"
+	ServiceTitleStorage     Service = "titlestorage"
+	ServiceTournaments      Service = "tournaments"
+	ServiceUserSearch       Service = "usersearch"
+)
+
+// DefaultContractVersions is the x-xbl-contract-version sent to each service
+var DefaultContractVersions = map[Service]string{
+	ServiceAchievements:     "2",
+	ServiceActivity:         "3",
+	ServiceConsoles:         "4",
+	ServiceGameClips:        "1",
+	ServiceGamerpics:        "1",
+	ServiceLeaderboards:     "3",
+	ServiceMessaging:        "1",
+	ServicePeopleHub:        "3",
+	ServicePresence:         "3",
+	ServiceProfile:          "2",
+	ServiceScreenshots:      "5",
+	ServiceSessionDirectory: "107",
+	ServiceSocial:           "2",
+	ServiceStats:            "1",
+	ServiceTitleStorage:     "1",
+	ServiceTournaments:      "1",
+	ServiceUserSearch:       "1",
+}
+
+// contractVersionsKey is the context key for per-call contract version overrides
+type contractVersionsKey struct{}
+
+// WithContractVersion returns a context that overrides the contract version sent to a service
+// It takes precedence over Config.ContractVersions and DefaultContractVersions for calls made with the context
+func WithContractVersion(ctx context.Context, service Service, version string) context.Context {
+	parent, _ := ctx.Value(contractVersionsKey{}).(map[Service]string)
+	versions := make(map[Service]string, len(parent)+1)
+	for s, v := range parent {
+		versions[s] = v
+	}
+	versions[service] = version
+	return context.WithValue(ctx, contractVersionsKey{}, versions)
+}
+
+// contractVersion returns the contract version to send to a service
+// Per-call overrides win over the client's configured versions, which win over the defaults
+func (c *Client) contractVersion(ctx context.Context, service Service) string {
+	if versions, ok := ctx.Value(contractVersionsKey{}).(map[Service]string); ok {
+		if v, ok := versions[service]; ok {
+			return v
+		}
+	}
+	if v, ok := c.contractVersions[service]; ok {
+		return v
+	}
+	return DefaultContractVersions[service]
+}
+
+// SupportedAPI describes an Xbox Live service this version of the client calls
+type SupportedAPI struct {
+	Service Service `json:"service"`
+
+	// ContractVersion is the x-xbl-contract-version sent by default
+	ContractVersion string `json:"contract_version"`
+
+	// Methods are the exported Client methods that call the service
+	Methods []string `json:"methods"`
+}
+
+// SupportedAPIs describes the services, default contract versions, and client methods this version of the library supports
+// Downstream tooling can use it to feature-detect at runtime across library versions
+func SupportedAPIs() []SupportedAPI {
+	apis := make([]SupportedAPI, 0, len(serviceMethods))
+	for service, methods := range serviceMethods {
+		apis = append(apis, SupportedAPI{
+			Service:         service,
+			ContractVersion: DefaultContractVersions[service],
+			Methods:         slices.Clone(methods),
+		})
+	}
+	slices.SortFunc(apis, func(a, b SupportedAPI) int {
+		return strings.Compare(string(a.Service), string(b.Service))
+	})
+	return apis
+}
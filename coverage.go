@@ -0,0 +1,24 @@
+// Code generated by go run ./internal/gencoverage; DO NOT EDIT.
+
+package xblive
+
+// serviceMethods lists the exported Client methods that call each service
+var serviceMethods = map[Service][]string{
+	ServiceAchievements:     {"GetAchievements", "GetRecentAchievements", "GetTitleAchievements"},
+	ServiceActivity:         {"GetActivity"},
+	ServiceConsoles:         {"ListConsoles", "RecordConsoleClip", "SendConsoleCommand"},
+	ServiceGameClips:        {"GetGameClips"},
+	ServiceGamerpics:        {"SetGamerpic"},
+	ServiceLeaderboards:     {"GetLeaderboard"},
+	ServiceMessaging:        {"GetConversationMessages", "GetConversations", "GetMessages"},
+	ServicePeopleHub:        {"ExportSocialGraph", "FindPeople", "GamertagToXUID", "GamertagsToXUIDs", "GetFriends", "GetFriendsOf", "GetFriendsWithPresence", "GetModerationReport", "GetProfile", "GetRecentPlayers", "GetRecommendations", "GetRelationship", "GetSharedSessions", "GetTitlePresence", "LookupGamertags", "LookupProfileByGamertag", "SearchPeople", "ValidateXUIDs"},
+	ServicePresence:         {"GetBroadcasts", "GetPresence", "GetTitlePresence", "SetPresenceVisibility"},
+	ServiceProfile:          {"GetProfileFields", "ResolveGamertags"},
+	ServiceScreenshots:      {"GetScreenshots"},
+	ServiceSessionDirectory: {"AcceptInvite", "CreateJoinHandle", "CreateParty", "GetParty", "GetPendingInvites", "GetSessionHandle", "GetSharedSessions", "InviteToParty", "KickFromParty", "ResolveJoinURL", "SendGameInvite"},
+	ServiceSocial:           {"AddFriend", "RemoveFriend"},
+	ServiceStats:            {"WriteTitleStats"},
+	ServiceTitleStorage:     {"GetTitleStorageQuota", "GetTitleStorageQuotas"},
+	ServiceTournaments:      {"GetTournamentMatches", "GetTournamentTeams", "ListTournaments"},
+	ServiceUserSearch:       {"SuggestGamertags"},
+}
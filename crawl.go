@@ -0,0 +1,225 @@
+package xblive
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCrawlConcurrency is the number of crawls CrawlAll runs at once when none is configured
+	DefaultCrawlConcurrency = 4
+
+	// DefaultCrawlPacing is the delay between pages of one crawl when none is configured
+	DefaultCrawlPacing = 500 * time.Millisecond
+
+	// DefaultCrawlRetries is the number of times a temporarily failing page is retried when none is configured
+	DefaultCrawlRetries = 3
+)
+
+// PageFunc fetches one page of a paged list API, returning the items and the continuation token for the next page
+// Client methods such as GetAchievements, GetActivity, and GetMessages fit it with a closure
+type PageFunc[T any] func(ctx context.Context, opts PageOptions) ([]T, string, error)
+
+// CrawlOptions configures Crawl and CrawlAll
+type CrawlOptions struct {
+	// Concurrency is the number of crawls CrawlAll runs at once (optional, defaults to DefaultCrawlConcurrency)
+	Concurrency int
+
+	// Pacing is the delay between pages of one crawl (optional, defaults to DefaultCrawlPacing)
+	Pacing time.Duration
+
+	// MaxRetries is the number of times a temporarily failing page is retried (optional, defaults to DefaultCrawlRetries)
+	MaxRetries int
+
+	// PageSize is the number of items requested per page (optional, defaults to DefaultPageSize)
+	PageSize int
+
+	// Cursors resumes crawls from saved continuation tokens, keyed by crawl key (optional)
+	// Crawl uses the empty key. Save the Cursor reported to OnProgress to resume after an interruption
+	Cursors map[string]string
+
+	// OnProgress is called after each page (optional)
+	// It may be called from multiple goroutines at once by CrawlAll
+	OnProgress func(CrawlProgress)
+}
+
+// CrawlProgress reports the progress of one crawl after a page
+type CrawlProgress struct {
+	// Key identifies the crawl; it is empty for Crawl
+	Key string
+
+	// Pages and Items count what this crawl has fetched so far
+	Pages int
+	Items int
+
+	// Cursor is the continuation token for the next page; pass it in CrawlOptions.Cursors to resume
+	Cursor string
+
+	// Done is true after the last page
+	Done bool
+}
+
+// Crawl fetches every page of a paged list API, pacing requests and retrying temporary failures
+// Rate-limited requests wait for the service's Retry-After delay before retrying
+func Crawl[T any](ctx context.Context, fetch PageFunc[T], opts CrawlOptions) ([]T, error) {
+	return crawlPages(ctx, newCrawler(opts), "", fetch)
+}
+
+// CrawlAll crawls a paged list API for each key (e.g. each XUID) with bounded concurrency
+// When any crawl is rate limited, all crawls pause until the Retry-After delay has passed
+// Returns: items per key, error (the first crawl to fail cancels the rest)
+func CrawlAll[T any](ctx context.Context, keys []string, fetch func(ctx context.Context, key string, opts PageOptions) ([]T, string, error), opts CrawlOptions) (map[string][]T, error) {
+	c := newCrawler(opts)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCrawlConcurrency
+	}
+
+	var mu sync.Mutex
+	results := make(map[string][]T, len(keys))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			items, err := crawlPages(ctx, c, key, func(ctx context.Context, opts PageOptions) ([]T, string, error) {
+				return fetch(ctx, key, opts)
+			})
+			if err != nil {
+				cancel(err)
+				return
+			}
+
+			mu.Lock()
+			results[key] = items
+			mu.Unlock()
+		}(key)
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// crawler holds the settings and shared rate-limit state of a crawl
+type crawler struct {
+	opts CrawlOptions
+
+	mu         sync.Mutex
+	pauseUntil time.Time
+}
+
+// newCrawler creates a crawler, applying option defaults
+func newCrawler(opts CrawlOptions) *crawler {
+	if opts.Pacing <= 0 {
+		opts.Pacing = DefaultCrawlPacing
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = DefaultCrawlRetries
+	}
+	return &crawler{opts: opts}
+}
+
+// crawlPages fetches every page for one key
+func crawlPages[T any](ctx context.Context, c *crawler, key string, fetch PageFunc[T]) ([]T, error) {
+	var all []T
+	progress := CrawlProgress{Key: key}
+	opts := PageOptions{MaxItems: c.opts.PageSize, ContinuationToken: c.opts.Cursors[key]}
+
+	for {
+		var items []T
+		var next string
+		var err error
+		for attempt := 0; ; attempt++ {
+			if err := c.waitForPause(ctx); err != nil {
+				return nil, err
+			}
+
+			items, next, err = fetch(ctx, opts)
+			if err == nil || !IsTemporary(err) || attempt == c.opts.MaxRetries {
+				break
+			}
+
+			delay := RetryAfter(err)
+			if delay == 0 {
+				delay = time.Duration(attempt+1) * 5 * time.Second
+			}
+			c.pause(delay)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+		progress.Pages++
+		progress.Items += len(items)
+		progress.Cursor = next
+		progress.Done = next == ""
+		if c.opts.OnProgress != nil {
+			c.opts.OnProgress(progress)
+		}
+
+		if next == "" {
+			return all, nil
+		}
+		opts.ContinuationToken = next
+
+		if err := sleepContext(ctx, c.opts.Pacing); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pause delays every crawl sharing this crawler until d has passed
+func (c *crawler) pause(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(c.pauseUntil) {
+		c.pauseUntil = until
+	}
+}
+
+// waitForPause waits until any rate-limit pause has passed
+func (c *crawler) waitForPause(ctx context.Context) error {
+	c.mu.Lock()
+	d := time.Until(c.pauseUntil)
+	c.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, d)
+}
+
+// sleepContext waits for d or until the context is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
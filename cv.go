@@ -0,0 +1,84 @@
+package xblive
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CorrelationVectorHeader is the HTTP header carrying the correlation vector (cV) to Microsoft services
+const CorrelationVectorHeader = "MS-CV"
+
+// CorrelationVector is a Microsoft correlation vector: a random base plus an extension incremented for each request in a chain
+// A single API call that refreshes tokens sends base.1 to the token endpoint, base.2 to XSTS, and base.3 to the API,
+// so Microsoft support can follow the whole chain from any one of them
+type CorrelationVector struct {
+	mu        sync.Mutex
+	base      string
+	extension int
+}
+
+// correlationVectorKey is the context key for the correlation vector
+type correlationVectorKey struct{}
+
+// NewCorrelationVector returns a correlation vector with a random 128-bit base
+func NewCorrelationVector() *CorrelationVector {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate correlation vector: %v", err))
+	}
+	return &CorrelationVector{base: base64.RawStdEncoding.EncodeToString(b[:])}
+}
+
+// ParseCorrelationVector continues a correlation vector received from elsewhere, e.g. "tul4NUsfs9Cl7mOf.1"
+// Requests extend it with a new element, so they read as its children: "tul4NUsfs9Cl7mOf.1.1", ".1.2", and so on
+func ParseCorrelationVector(s string) (*CorrelationVector, error) {
+	base, ext, ok := strings.Cut(s, ".")
+	if !ok || base == "" {
+		return nil, fmt.Errorf("invalid correlation vector %q", s)
+	}
+	for _, part := range strings.Split(ext, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return nil, fmt.Errorf("invalid correlation vector %q", s)
+		}
+	}
+	return &CorrelationVector{base: s}, nil
+}
+
+// Value returns the current correlation vector
+func (cv *CorrelationVector) Value() string {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return cv.base + "." + strconv.Itoa(cv.extension)
+}
+
+// Increment advances the vector's extension and returns the new value
+func (cv *CorrelationVector) Increment() string {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.extension++
+	return cv.base + "." + strconv.Itoa(cv.extension)
+}
+
+// WithCorrelationVector returns a context whose requests carry cv, incrementing it for each request
+func WithCorrelationVector(ctx context.Context, cv *CorrelationVector) context.Context {
+	return context.WithValue(ctx, correlationVectorKey{}, cv)
+}
+
+// CorrelationVectorFromContext returns the correlation vector carried by the context, or nil
+func CorrelationVectorFromContext(ctx context.Context) *CorrelationVector {
+	cv, _ := ctx.Value(correlationVectorKey{}).(*CorrelationVector)
+	return cv
+}
+
+// ensureCorrelationVector returns ctx carrying a correlation vector, starting a new one if it has none
+func ensureCorrelationVector(ctx context.Context) context.Context {
+	if CorrelationVectorFromContext(ctx) != nil {
+		return ctx
+	}
+	return WithCorrelationVector(ctx, NewCorrelationVector())
+}
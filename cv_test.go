@@ -0,0 +1,23 @@
+package xblive
+
+import "testing"
+
+func TestParseCorrelationVectorChildren(t *testing.T) {
+	cv, err := ParseCorrelationVector("tul4NUsfs9Cl7mOf.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"tul4NUsfs9Cl7mOf.1.1", "tul4NUsfs9Cl7mOf.1.2", "tul4NUsfs9Cl7mOf.1.3"} {
+		if got := cv.Increment(); got != want {
+			t.Errorf("Increment() = %q; want %q", got, want)
+		}
+	}
+}
+
+func TestParseCorrelationVectorInvalid(t *testing.T) {
+	for _, s := range []string{"", "tul4NUsfs9Cl7mOf", ".1", "tul4NUsfs9Cl7mOf.x", "tul4NUsfs9Cl7mOf.1."} {
+		if _, err := ParseCorrelationVector(s); err == nil {
+			t.Errorf("ParseCorrelationVector(%q) succeeded; want an error", s)
+		}
+	}
+}
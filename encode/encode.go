@@ -0,0 +1,285 @@
+// Package encode streams xblive types as NDJSON or CSV with stable column ordering
+package encode
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// LookupResult is a single gamertag to XUID lookup result
+type LookupResult struct {
+	Gamertag string `json:"gamertag"`
+	XUID     string `json:"xuid"`
+}
+
+// LookupResults converts the map returned by GamertagsToXUIDs into results ordered by gamertag
+func LookupResults(results map[string]string) []LookupResult {
+	out := make([]LookupResult, 0, len(results))
+	for gamertag, xuid := range results {
+		out = append(out, LookupResult{Gamertag: gamertag, XUID: xuid})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Gamertag < out[j].Gamertag
+	})
+	return out
+}
+
+// NDJSONWriter writes values as newline-delimited JSON
+type NDJSONWriter[T any] struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter creates a new NDJSON writer
+func NewNDJSONWriter[T any](w io.Writer) *NDJSONWriter[T] {
+	return &NDJSONWriter[T]{enc: json.NewEncoder(w)}
+}
+
+// Write writes a single value as one line of JSON
+func (n *NDJSONWriter[T]) Write(v T) error {
+	return n.enc.Encode(v)
+}
+
+// CSVWriter writes values as CSV rows, emitting the header before the first row
+type CSVWriter[T any] struct {
+	w           *csv.Writer
+	columns     []string
+	row         func(T) []string
+	wroteHeader bool
+}
+
+// Write writes a single value as a CSV row
+func (c *CSVWriter[T]) Write(v T) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.Write(c.row(v))
+}
+
+// Flush writes any buffered data, including the header if no rows were written
+func (c *CSVWriter[T]) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// writeHeader writes the header row once
+func (c *CSVWriter[T]) writeHeader() error {
+	if c.wroteHeader {
+		return nil
+	}
+	c.wroteHeader = true
+	return c.w.Write(c.columns)
+}
+
+// ProfileColumns is the CSV column order for profiles
+var ProfileColumns = []string{
+	"xuid",
+	"gamertag",
+	"display_name",
+	"real_name",
+	"gamer_score",
+	"modern_gamertag",
+	"modern_gamertag_suffix",
+	"unique_modern_gamertag",
+	"xbox_one_rep",
+	"presence_state",
+	"presence_text",
+	"display_pic_raw",
+	"account_tier",
+	"bio",
+	"location",
+	"tenure",
+	"is_verified",
+	"follower_count",
+	"following_count",
+	"has_game_pass",
+}
+
+// NewProfileCSVWriter creates a CSV writer for profiles
+func NewProfileCSVWriter(w io.Writer) *CSVWriter[*xblive.Profile] {
+	return &CSVWriter[*xblive.Profile]{
+		w:       csv.NewWriter(w),
+		columns: ProfileColumns,
+		row:     profileRow,
+	}
+}
+
+// profileRow converts a profile into a CSV row in ProfileColumns order
+func profileRow(p *xblive.Profile) []string {
+	detail := p.Detail
+	if detail == nil {
+		detail = &xblive.ProfileDetail{}
+	}
+	return []string{
+		p.XUID,
+		p.Gamertag,
+		p.DisplayName,
+		p.RealName,
+		p.GamerScore,
+		p.ModernGamertag,
+		p.ModernGamertagSuffix,
+		p.UniqueModernGamertag,
+		string(p.XboxOneRep),
+		string(p.PresenceState),
+		p.PresenceText,
+		p.DisplayPicRaw,
+		string(detail.AccountTier),
+		detail.Bio,
+		detail.Location,
+		detail.Tenure,
+		strconv.FormatBool(detail.IsVerified),
+		strconv.Itoa(detail.FollowerCount),
+		strconv.Itoa(detail.FollowingCount),
+		strconv.FormatBool(detail.HasGamePass),
+	}
+}
+
+// LookupColumns is the CSV column order for lookup results
+var LookupColumns = []string{
+	"gamertag",
+	"xuid",
+}
+
+// NewLookupCSVWriter creates a CSV writer for lookup results
+func NewLookupCSVWriter(w io.Writer) *CSVWriter[LookupResult] {
+	return &CSVWriter[LookupResult]{
+		w:       csv.NewWriter(w),
+		columns: LookupColumns,
+		row: func(r LookupResult) []string {
+			return []string{r.Gamertag, r.XUID}
+		},
+	}
+}
+
+// PresenceColumns is the CSV column order for presence
+var PresenceColumns = []string{
+	"xuid",
+	"state",
+	"title_ids",
+	"title_names",
+	"rich_presence",
+	"last_seen_title_id",
+	"last_seen_title_name",
+	"last_seen_timestamp",
+}
+
+// NewPresenceCSVWriter creates a CSV writer for presence
+// Multiple active titles are joined with ';' in a single row
+func NewPresenceCSVWriter(w io.Writer) *CSVWriter[*xblive.Presence] {
+	return &CSVWriter[*xblive.Presence]{
+		w:       csv.NewWriter(w),
+		columns: PresenceColumns,
+		row:     presenceRow,
+	}
+}
+
+// presenceRow converts presence into a CSV row in PresenceColumns order
+func presenceRow(p *xblive.Presence) []string {
+	var ids, names, rich []string
+	for _, title := range p.ActiveTitles() {
+		ids = append(ids, title.ID)
+		names = append(names, title.Name)
+		if title.Activity != nil && title.Activity.RichPresence != "" {
+			rich = append(rich, title.Activity.RichPresence)
+		}
+	}
+
+	var lastSeenID, lastSeenName, lastSeenTime string
+	if p.LastSeen != nil {
+		lastSeenID = p.LastSeen.TitleID
+		lastSeenName = p.LastSeen.TitleName
+		if !p.LastSeen.Timestamp.IsZero() {
+			lastSeenTime = p.LastSeen.Timestamp.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return []string{
+		p.XUID,
+		string(p.State),
+		strings.Join(ids, ";"),
+		strings.Join(names, ";"),
+		strings.Join(rich, ";"),
+		lastSeenID,
+		lastSeenName,
+		lastSeenTime,
+	}
+}
+
+// ModerationColumns is the CSV column order for moderation report entries
+var ModerationColumns = []string{
+	"xuid",
+	"gamertag",
+	"reputation",
+	"is_quarantined",
+	"presence_state",
+	"presence_text",
+	"last_played_with",
+	"shared_title_ids",
+	"shared_title_names",
+}
+
+// NewModerationCSVWriter creates a CSV writer for moderation report entries
+// Multiple shared titles are joined with ';' in a single row
+func NewModerationCSVWriter(w io.Writer) *CSVWriter[*xblive.ModerationEntry] {
+	return &CSVWriter[*xblive.ModerationEntry]{
+		w:       csv.NewWriter(w),
+		columns: ModerationColumns,
+		row:     moderationRow,
+	}
+}
+
+// moderationRow converts a moderation entry into a CSV row in ModerationColumns order
+func moderationRow(e *xblive.ModerationEntry) []string {
+	var ids, names []string
+	for _, title := range e.SharedTitles {
+		ids = append(ids, title.TitleID)
+		names = append(names, title.TitleName)
+	}
+
+	var lastPlayed string
+	if !e.LastPlayedWith.IsZero() {
+		lastPlayed = e.LastPlayedWith.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		e.XUID,
+		e.Gamertag,
+		string(e.Reputation),
+		strconv.FormatBool(e.IsQuarantined),
+		string(e.PresenceState),
+		e.PresenceText,
+		lastPlayed,
+		strings.Join(ids, ";"),
+		strings.Join(names, ";"),
+	}
+}
+
+// WriteNDJSON writes all values as newline-delimited JSON
+func WriteNDJSON[T any](w io.Writer, values []T) error {
+	n := NewNDJSONWriter[T](w)
+	for _, v := range values {
+		if err := n.Write(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes all values using the given CSV writer and flushes it
+func WriteCSV[T any](c *CSVWriter[T], values []T) error {
+	for _, v := range values {
+		if err := c.Write(v); err != nil {
+			return err
+		}
+	}
+	return c.Flush()
+}
@@ -0,0 +1,96 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+)
+
+// EnforcementState summarizes whether an account is subject to Xbox Live enforcement
+type EnforcementState string
+
+// Enforcement states
+const (
+	// EnforcementNone means the account authorized normally with communication and multiplayer privileges
+	EnforcementNone EnforcementState = "none"
+
+	// EnforcementBanned means Xbox Live refused authorization because the account is banned
+	EnforcementBanned EnforcementState = "banned"
+
+	// EnforcementRestricted means the account authorized but lacks communication or multiplayer privileges,
+	// which enforcement actions (or parental controls) remove
+	EnforcementRestricted EnforcementState = "restricted"
+
+	// EnforcementAccountIssue means Xbox Live refused authorization for another account-level reason (see XErr)
+	EnforcementAccountIssue EnforcementState = "account_issue"
+)
+
+// EnforcementStatus describes the enforcement state of the authenticated account
+type EnforcementStatus struct {
+	State EnforcementState `json:"state"`
+
+	// XErr is the code Xbox Live refused authorization with, if it did
+	XErr XErr `json:"xerr,omitempty"`
+
+	// Reason is a human-readable explanation
+	Reason string `json:"reason"`
+
+	// MissingPrivileges lists the communication and multiplayer privileges the account lacks
+	MissingPrivileges []Privilege `json:"missingPrivileges,omitempty"`
+}
+
+// enforcementPrivileges are the privileges whose absence indicates a restricted account
+var enforcementPrivileges = []Privilege{
+	PrivilegeCommunications,
+	PrivilegeCommunicationVoiceInGame,
+	PrivilegeMultiplayerSessions,
+	PrivilegeMultiplayerParties,
+	PrivilegeUserCreatedContent,
+}
+
+// EnforcementStatus checks whether the authenticated account is banned or restricted
+// A fresh XSTS token is requested so the result reflects the account's current state.
+// An error is returned only when the state can't be determined (e.g. not authenticated or a network failure),
+// so callers can distinguish a banned account from an authentication bug
+func (c *Client) EnforcementStatus(ctx context.Context) (*EnforcementStatus, error) {
+	_, _, err := c.fetchXSTSToken(ctx, DefaultAudience)
+
+	var xe *XboxError
+	if errors.As(err, &xe) {
+		status := &EnforcementStatus{
+			State:  EnforcementAccountIssue,
+			XErr:   xe.XErr,
+			Reason: xe.Error(),
+		}
+		switch xe.XErr {
+		case XErrAccountBanned:
+			status.State = EnforcementBanned
+		case XErrParentalRestriction:
+			status.State = EnforcementRestricted
+		}
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &EnforcementStatus{
+		State:  EnforcementNone,
+		Reason: "the account is in good standing",
+	}
+	for _, p := range enforcementPrivileges {
+		if !claims.Has(p) {
+			status.MissingPrivileges = append(status.MissingPrivileges, p)
+		}
+	}
+	if len(status.MissingPrivileges) > 0 {
+		status.State = EnforcementRestricted
+		status.Reason = "the account is missing communication or multiplayer privileges"
+	}
+
+	return status, nil
+}
@@ -0,0 +1,98 @@
+package xblive
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by ConfigFromEnv
+const (
+	EnvClientID = "XBLIVE_CLIENT_ID"
+	EnvTenant   = "XBLIVE_TENANT"
+	EnvCacheDir = "XBLIVE_CACHE_DIR"
+	EnvAccount  = "XBLIVE_ACCOUNT"
+	EnvLogLevel = "XBLIVE_LOG_LEVEL"
+	EnvTokens   = "XBLIVE_TOKENS_JSON"
+	EnvProfile  = "XBLIVE_PROFILE"
+)
+
+// NewFromEnv creates a new Xbox Live client configured from environment variables
+func NewFromEnv() (*Client, error) {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return New(config)
+}
+
+// ConfigFromEnv assembles a Config from environment variables
+//
+//	XBLIVE_CLIENT_ID    Microsoft Entra ID application client ID (required)
+//	XBLIVE_TENANT       Entra ID tenant (optional, defaults to DefaultTenant)
+//	XBLIVE_CACHE_DIR    token cache directory (optional, defaults to ~/.xblive)
+//	XBLIVE_ACCOUNT      name of the account whose tokens to use, stored in a subdirectory of the cache directory (optional)
+//	XBLIVE_LOG_LEVEL    debug, info, warn, or error; logs to stderr (optional, defaults to no logging)
+//	XBLIVE_TOKENS_JSON  token set as JSON, or the path of a file holding it, in the format of tokens.json (optional)
+//	                    It is served by a ReadOnlyTokenCache, so no interactive sign-in or writable cache directory is
+//	                    needed; it can't be combined with XBLIVE_CACHE_DIR or XBLIVE_ACCOUNT
+//	XBLIVE_PROFILE      interactive, bulk, or server; see TuningProfile (optional)
+//
+// All problems are reported together in the returned error
+func ConfigFromEnv() (Config, error) {
+	var errs []error
+	config := Config{
+		ClientID: os.Getenv(EnvClientID),
+		Tenant:   os.Getenv(EnvTenant),
+		Profile:  TuningProfile(os.Getenv(EnvProfile)),
+	}
+
+	if config.ClientID == "" {
+		errs = append(errs, fmt.Errorf("%s is required", EnvClientID))
+	}
+
+	cacheDir := os.Getenv(EnvCacheDir)
+	account := os.Getenv(EnvAccount)
+	if account != "" && (strings.ContainsAny(account, `/\`) || account == "." || account == "..") {
+		errs = append(errs, fmt.Errorf("%s must be a plain name, got %q", EnvAccount, account))
+		account = ""
+	}
+	if account != "" && cacheDir == "" {
+		dir, err := DefaultCacheDir()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		cacheDir = dir
+	}
+	if tokensJSON := os.Getenv(EnvTokens); tokensJSON != "" {
+		if cacheDir != "" {
+			errs = append(errs, fmt.Errorf("%s can't be combined with %s or %s", EnvTokens, EnvCacheDir, EnvAccount))
+		}
+		tokens, err := loadTokensJSON(tokensJSON)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvTokens, err))
+		} else {
+			config.Cache = NewReadOnlyTokenCache(tokens)
+		}
+	} else if cacheDir != "" {
+		config.CachePath = filepath.Join(cacheDir, account, "tokens.json")
+	}
+
+	if level := os.Getenv(EnvLogLevel); level != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid log level %q", EnvLogLevel, level))
+		} else {
+			config.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: l}))
+		}
+	}
+
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("invalid environment configuration: %w", errors.Join(errs...))
+	}
+
+	return config, nil
+}
@@ -0,0 +1,250 @@
+package xblive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// maxErrorBodySize is the maximum number of response body bytes captured in an XboxAPIError
+const maxErrorBodySize = 4 << 10
+
+// XboxAPIError is returned when an Xbox Live or Microsoft identity endpoint responds with an error status
+// Body holds a size-capped copy of the response body with anything resembling a credential redacted
+type XboxAPIError struct {
+	Op         string
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Body       string
+	Truncated  bool
+
+	// RetryAfterDelay is the delay requested by the server's Retry-After header, if any
+	RetryAfterDelay time.Duration
+
+	// CorrelationVector is the MS-CV sent with the failed request; quote it in support requests to Microsoft
+	CorrelationVector string
+}
+
+// Error implements the error interface
+func (e *XboxAPIError) Error() string {
+	msg := fmt.Sprintf("%s failed: %s", e.Op, e.Status)
+	if e.Body != "" {
+		msg += " - " + e.Body
+		if e.Truncated {
+			msg += "... (truncated)"
+		}
+	}
+	if e.CorrelationVector != "" {
+		msg += " (cV: " + e.CorrelationVector + ")"
+	}
+	return msg
+}
+
+// Is reports whether the error matches ErrNotFound (404), ErrForbidden (403), or ErrConflict (409)
+func (e *XboxAPIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// Temporary reports whether the request may succeed if retried (rate limiting or a server-side failure)
+func (e *XboxAPIError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// RetryAfter returns the delay requested by the server before retrying, or zero if none was given
+func (e *XboxAPIError) RetryAfter() time.Duration {
+	return e.RetryAfterDelay
+}
+
+// newXboxAPIError builds an XboxAPIError from a response and its captured error body
+func newXboxAPIError(op string, resp *http.Response, body []byte, truncated bool) *XboxAPIError {
+	apiErr := &XboxAPIError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       redactErrorBody(body),
+		Truncated:  truncated,
+
+		RetryAfterDelay: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.CorrelationVector = resp.Request.Header.Get(CorrelationVectorHeader)
+		if resp.Request.URL != nil {
+			apiErr.URL = resp.Request.URL.Redacted()
+		}
+	}
+	return apiErr
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// captureErrorBody reads at most maxErrorBodySize bytes of an error response body
+func captureErrorBody(r io.Reader) ([]byte, bool) {
+	body, _ := io.ReadAll(io.LimitReader(r, maxErrorBodySize+1))
+	if len(body) > maxErrorBodySize {
+		return body[:maxErrorBodySize], true
+	}
+	return body, false
+}
+
+var (
+	// JSON properties whose names suggest a credential, e.g. "access_token" or "RpsTicket"
+	redactJSONField = regexp.MustCompile(`(?i)("[^"]*(?:token|ticket|authorization|secret|password|assertion|code)[^"]*"\s*:\s*)"[^"]*"`)
+
+	// Form-encoded credentials, e.g. echoed request bodies
+	redactFormField = regexp.MustCompile(`(?i)\b((?:access_token|refresh_token|device_code|code|client_secret)=)[^&\s"]+`)
+
+	// Xbox Live authorization headers
+	redactXBLHeader = regexp.MustCompile(`XBL3\.0 x=[^\s"]+`)
+
+	// JWTs and long opaque token-like strings
+	redactJWT         = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	redactOpaqueToken = regexp.MustCompile(`[A-Za-z0-9+/_=.!*-]{100,}`)
+)
+
+// redactErrorBody replaces anything resembling a credential in an error body with [REDACTED]
+func redactErrorBody(body []byte) string {
+	s := string(body)
+	s = redactJSONField.ReplaceAllString(s, `$1"[REDACTED]"`)
+	s = redactFormField.ReplaceAllString(s, `$1[REDACTED]`)
+	s = redactXBLHeader.ReplaceAllString(s, `XBL3.0 x=[REDACTED]`)
+	s = redactJWT.ReplaceAllString(s, `[REDACTED]`)
+	s = redactOpaqueToken.ReplaceAllString(s, `[REDACTED]`)
+	return s
+}
+
+// XErr is an Xbox Live authorization error code, returned by the XSTS service
+type XErr int64
+
+// Known XErr codes
+const (
+	XErrAccountBanned           XErr = 0x8015DC03 // the account is banned for violating the Community Standards
+	XErrParentalRestriction     XErr = 0x8015DC05 // a guardian has not allowed the account to play online
+	XErrNoXboxAccount           XErr = 0x8015DC09 // the Microsoft account doesn't have an Xbox Live profile
+	XErrTermsNotAccepted        XErr = 0x8015DC0A // the account hasn't accepted the Xbox Terms of Use
+	XErrRegionBlocked           XErr = 0x8015DC0B // Xbox Live isn't available in the account's country/region
+	XErrAdultVerificationNeeded XErr = 0x8015DC0C // the account needs adult verification
+	XErrAgeVerificationNeeded   XErr = 0x8015DC0D // the account needs age verification
+	XErrChildAccount            XErr = 0x8015DC0E // a child account needs parental consent to proceed
+)
+
+// XErrCodes lists all known XErr codes
+var XErrCodes = []XErr{
+	XErrAccountBanned,
+	XErrParentalRestriction,
+	XErrNoXboxAccount,
+	XErrTermsNotAccepted,
+	XErrRegionBlocked,
+	XErrAdultVerificationNeeded,
+	XErrAgeVerificationNeeded,
+	XErrChildAccount,
+}
+
+// String returns the code in hex, as it appears in Microsoft documentation
+func (x XErr) String() string {
+	return fmt.Sprintf("0x%X", int64(x))
+}
+
+// Description returns a user-friendly English description of a known code, or an empty string
+func (x XErr) Description() string {
+	return localizer{}.xerrDescription(x)
+}
+
+// XboxError is an authorization failure reported by Xbox Live with an XErr code
+// None of the known codes are temporary: they describe the state of the account and won't change on retry
+type XboxError struct {
+	XErr     XErr
+	Message  string
+	Identity string
+	Redirect string
+
+	// description is the explanation of XErr in the client's language, empty to use the English Description
+	description string
+}
+
+// newXboxError converts an Xbox error response into an XboxError
+func newXboxError(resp XboxErrorResponse) *XboxError {
+	return &XboxError{
+		XErr:     XErr(resp.XErr),
+		Message:  resp.Message,
+		Identity: resp.Identity,
+		Redirect: resp.Redirect,
+	}
+}
+
+// Error implements the error interface
+func (e *XboxError) Error() string {
+	if e.description != "" {
+		return e.description
+	}
+	if desc := e.XErr.Description(); desc != "" {
+		return desc
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("Xbox error %d: %s", int64(e.XErr), e.Message)
+	}
+	return fmt.Sprintf("Xbox error code: %d (%s)", int64(e.XErr), e.XErr)
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *XboxError) Temporary() bool {
+	return false
+}
+
+// RetryAfter returns the delay before retrying, which is always zero for XErr failures
+func (e *XboxError) RetryAfter() time.Duration {
+	return 0
+}
+
+// IsXErr reports whether err is an XboxError with the given code
+func IsXErr(err error, code XErr) bool {
+	var xe *XboxError
+	return errors.As(err, &xe) && xe.XErr == code
+}
+
+// IsTemporary reports whether err, or any error it wraps, is temporary and may succeed if retried
+func IsTemporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// RetryAfter returns the retry delay requested by the server for err, or zero if none was given
+func RetryAfter(err error) time.Duration {
+	var r interface{ RetryAfter() time.Duration }
+	if errors.As(err, &r) {
+		return r.RetryAfter()
+	}
+	return 0
+}
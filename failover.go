@@ -0,0 +1,142 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverCooldown is how long a failed address is skipped when no cooldown is configured
+const DefaultFailoverCooldown = time.Minute
+
+// EndpointFailover configures alternative addresses for hosts whose DNS resolution or routing is unreliable,
+// such as login.microsoftonline.com on some corporate networks
+// Only the TCP connection is redirected; TLS still verifies the original host name
+type EndpointFailover struct {
+	// Hosts maps a host name to fallback addresses (IPs or host names, with an optional :port) tried in order
+	// after the host itself fails
+	Hosts map[string][]string
+
+	// Pin skips normal resolution of the listed hosts and dials only their fallback addresses (optional)
+	Pin bool
+
+	// Cooldown is how long an address that failed to connect is skipped (optional, defaults to DefaultFailoverCooldown)
+	Cooldown time.Duration
+}
+
+// failoverDialer dials through a host's fallback addresses, skipping addresses that recently failed
+type failoverDialer struct {
+	dialer   *net.Dialer
+	hosts    map[string][]string
+	pin      bool
+	cooldown time.Duration
+
+	mu       sync.Mutex
+	failedAt map[string]time.Time
+}
+
+// newFailoverDialer creates a dialer for a failover configuration
+func newFailoverDialer(config *EndpointFailover) *failoverDialer {
+	cooldown := config.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+
+	return &failoverDialer{
+		dialer:   &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second},
+		hosts:    config.Hosts,
+		pin:      config.Pin,
+		cooldown: cooldown,
+		failedAt: make(map[string]time.Time),
+	}
+}
+
+// DialContext connects to addr, failing over to the host's fallback addresses
+func (d *failoverDialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbacks, ok := d.hosts[host]
+	if !ok {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+
+	var candidates []string
+	if !d.pin {
+		candidates = append(candidates, addr)
+	}
+	for _, fallback := range fallbacks {
+		if _, _, err := net.SplitHostPort(fallback); err != nil {
+			fallback = net.JoinHostPort(fallback, port)
+		}
+		candidates = append(candidates, fallback)
+	}
+
+	// Try healthy addresses first; addresses in cooldown are a last resort
+	healthy, unhealthy := d.partition(candidates)
+
+	var errs []error
+	for _, candidate := range append(healthy, unhealthy...) {
+		conn, err := d.dialer.DialContext(ctx, network, candidate)
+		if err == nil {
+			d.markHealthy(candidate)
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		d.markFailed(candidate)
+		errs = append(errs, err)
+	}
+
+	return nil, fmt.Errorf("all addresses for %s failed: %w", host, errors.Join(errs...))
+}
+
+// partition splits addresses into those that are healthy and those still in cooldown, preserving order
+func (d *failoverDialer) partition(addrs []string) (healthy []string, unhealthy []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, addr := range addrs {
+		if failedAt, ok := d.failedAt[addr]; ok && time.Since(failedAt) < d.cooldown {
+			unhealthy = append(unhealthy, addr)
+		} else {
+			healthy = append(healthy, addr)
+		}
+	}
+	return healthy, unhealthy
+}
+
+// markFailed records a failed connection attempt
+func (d *failoverDialer) markFailed(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failedAt[addr] = time.Now()
+}
+
+// markHealthy clears any recorded failure
+func (d *failoverDialer) markHealthy(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.failedAt, addr)
+}
+
+// newHTTPClient creates the HTTP client for a config
+func newHTTPClient(config Config) *http.Client {
+	client := &http.Client{Timeout: config.tuning().Timeout, Transport: config.Transport}
+
+	if config.Failover != nil && len(config.Failover.Hosts) > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = newFailoverDialer(config.Failover).DialContext
+		client.Transport = transport
+	}
+
+	client.Transport = &headerTransport{userAgent: userAgent(config), base: client.Transport}
+	return client
+}
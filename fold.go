@@ -0,0 +1,135 @@
+package xblive
+
+import (
+	"strings"
+	"unicode"
+)
+
+// partialFoldGamertag returns the form of a gamertag used for case- and width-insensitive comparison
+// It composes Latin letters typed as a base letter and combining marks (as macOS and some IMEs produce) into their
+// precomposed forms, then applies full Unicode case folding, treating the Turkish dotted and dotless i as plain i so
+// that lookups behave the same in every locale. Of NFKC it covers the mappings gamertags actually hit: Latin canonical
+// composition and the full-width ASCII and ideographic space typed with East Asian IMEs. Other compatibility
+// characters (half-width katakana, ligatures, circled or superscript letters) and combining sequences outside Latin are
+// compared as they are, since full normalization tables would need golang.org/x/text and the core library is stdlib-only
+func partialFoldGamertag(s string) string {
+	composed := make([]rune, 0, len(s))
+	for _, r := range s {
+		if n := len(composed); n > 0 {
+			if c, ok := latinCompositions[[2]rune{composed[n-1], r}]; ok {
+				composed[n-1] = c
+				continue
+			}
+		}
+		composed = append(composed, r)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range composed {
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// latinCompositionTable lists, for each combining mark, pairs of a base letter and the letter it composes into
+// It holds the canonical compositions of Unicode's Latin-1 Supplement, Latin Extended-A and -B, and Latin Extended
+// Additional blocks; letters with several marks (e.g. Vietnamese ệ) compose one mark at a time
+var latinCompositionTable = []struct {
+	mark  rune
+	pairs string
+}{
+	// grave accent
+	{0x0300, "AÀEÈIÌOÒUÙaàeèiìoòuùÜǛüǜNǸnǹĒḔēḕŌṐōṑWẀwẁÂẦâầĂẰăằÊỀêềÔỒôồƠỜơờƯỪưừYỲyỳ"},
+	// acute accent
+	{0x0301, "AÁEÉIÍOÓUÚYÝaáeéiíoóuúyýCĆcćLĹlĺNŃnńRŔrŕSŚsśZŹzźÜǗüǘGǴgǵÅǺåǻÆǼæǽØǾøǿÇḈçḉĒḖēḗÏḮïḯKḰkḱMḾmḿÕṌõṍŌṒōṓPṔpṕŨṸũṹWẂwẃÂẤâấĂẮăắÊẾêếÔỐôốƠỚơớƯỨưứ"},
+	// circumflex accent
+	{0x0302, "AÂEÊIÎOÔUÛaâeêiîoôuûCĈcĉGĜgĝHĤhĥJĴjĵSŜsŝWŴwŵYŶyŷZẐzẑẠẬạậẸỆẹệỌỘọộ"},
+	// tilde
+	{0x0303, "AÃNÑOÕaãnñoõIĨiĩUŨuũVṼvṽÂẪâẫĂẴăẵEẼeẽÊỄêễÔỖôỗƠỠơỡƯỮưữYỸyỹ"},
+	// macron
+	{0x0304, "AĀaāEĒeēIĪiīOŌoōUŪuūÜǕüǖÄǞäǟȦǠȧǡÆǢæǣǪǬǫǭÖȪöȫÕȬõȭȮȰȯȱYȲyȳGḠgḡḶḸḷḹṚṜṛṝ"},
+	// breve
+	{0x0306, "AĂaăEĔeĕGĞgğIĬiĭOŎoŏUŬuŭȨḜȩḝẠẶạặ"},
+	// dot above
+	{0x0307, "CĊcċEĖeėGĠgġIİZŻzżAȦaȧOȮoȯBḂbḃDḊdḋFḞfḟHḢhḣMṀmṁNṄnṅPṖpṗRṘrṙSṠsṡŚṤśṥŠṦšṧṢṨṣṩTṪtṫWẆwẇXẊxẋYẎyẏſẛ"},
+	// diaeresis
+	{0x0308, "AÄEËIÏOÖUÜaäeëiïoöuüyÿYŸHḦhḧÕṎõṏŪṺūṻWẄwẅXẌxẍtẗ"},
+	// hook above
+	{0x0309, "AẢaảÂẨâẩĂẲăẳEẺeẻÊỂêểIỈiỉOỎoỏÔỔôổƠỞơởUỦuủƯỬưửYỶyỷ"},
+	// ring above
+	{0x030A, "AÅaåUŮuůwẘyẙ"},
+	// double acute accent
+	{0x030B, "OŐoőUŰuű"},
+	// caron
+	{0x030C, "CČcčDĎdďEĚeěLĽlľNŇnňRŘrřSŠsšTŤtťZŽzžAǍaǎIǏiǐOǑoǒUǓuǔÜǙüǚGǦgǧKǨkǩƷǮʒǯjǰHȞhȟ"},
+	// double grave accent
+	{0x030F, "AȀaȁEȄeȅIȈiȉOȌoȍRȐrȑUȔuȕ"},
+	// inverted breve
+	{0x0311, "AȂaȃEȆeȇIȊiȋOȎoȏRȒrȓUȖuȗ"},
+	// horn
+	{0x031B, "OƠoơUƯuư"},
+	// dot below
+	{0x0323, "BḄbḅDḌdḍHḤhḥKḲkḳLḶlḷMṂmṃNṆnṇRṚrṛSṢsṣTṬtṭVṾvṿWẈwẉZẒzẓAẠaạEẸeẹIỊiịOỌoọƠỢơợUỤuụƯỰưựYỴyỵ"},
+	// diaeresis below
+	{0x0324, "UṲuṳ"},
+	// ring below
+	{0x0325, "AḀaḁ"},
+	// comma below
+	{0x0326, "SȘsșTȚtț"},
+	// cedilla
+	{0x0327, "CÇcçGĢgģKĶkķLĻlļNŅnņRŖrŗSŞsşTŢtţEȨeȩDḐdḑHḨhḩ"},
+	// ogonek
+	{0x0328, "AĄaąEĘeęIĮiįUŲuųOǪoǫ"},
+	// circumflex accent below
+	{0x032D, "DḒdḓEḘeḙLḼlḽNṊnṋTṰtṱUṶuṷ"},
+	// breve below
+	{0x032E, "HḪhḫ"},
+	// tilde below
+	{0x0330, "EḚeḛIḬiḭUṴuṵ"},
+	// macron below
+	{0x0331, "BḆbḇDḎdḏKḴkḵLḺlḻNṈnṉRṞrṟTṮtṯZẔzẕhẖ"},
+}
+
+// latinCompositions maps a base letter and a combining mark to the precomposed letter
+var latinCompositions = func() map[[2]rune]rune {
+	m := make(map[[2]rune]rune)
+	for _, entry := range latinCompositionTable {
+		pairs := []rune(entry.pairs)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			m[[2]rune{pairs[i], entry.mark}] = pairs[i+1]
+		}
+	}
+	return m
+}()
+
+// foldRune maps a rune to its canonical comparison form
+func foldRune(r rune) rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E:
+		// Full-width ASCII variants
+		r -= 0xFF01 - 0x21
+	case r == 0x3000:
+		// Ideographic space
+		return ' '
+	case r == 'ı' || r == 'İ':
+		// Turkish dotless i and dotted capital I
+		return 'i'
+	}
+
+	// The smallest rune in the case folding orbit is the canonical form (e.g. K, k, and the Kelvin sign all fold to K)
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+	return unicode.ToLower(folded)
+}
+
+// GamertagsEqual reports whether two gamertags are the same, ignoring case, full-width forms, decomposed Latin
+// accents, and whitespace
+// This is the comparison the lookup APIs use to decide on an exact match; see partialFoldGamertag for its limits
+func GamertagsEqual(a string, b string) bool {
+	return normalizeGamertag(a) == normalizeGamertag(b)
+}
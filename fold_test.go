@@ -0,0 +1,76 @@
+package xblive
+
+import "testing"
+
+func TestGamertagsEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "identical", a: "MajorNelson", b: "MajorNelson", want: true},
+		{name: "ascii case", a: "MajorNelson", b: "majornelson", want: true},
+		{name: "different", a: "MajorNelson", b: "MinorNelson", want: false},
+		{name: "spaces ignored", a: "Major Nelson", b: "MajorNelson", want: true},
+		{name: "surrounding space", a: "  MajorNelson ", b: "MajorNelson", want: true},
+
+		// Turkish dotted and dotless i compare equal to plain i in every locale
+		{name: "turkish dotless i", a: "Kılıç", b: "KILIÇ", want: true},
+		{name: "turkish dotless i to ascii", a: "Kılıç", b: "kilic", want: false}, // ç isn't folded to c
+		{name: "turkish dotted capital I", a: "İstanbul", b: "istanbul", want: true},
+		{name: "turkish dotless vs dotted", a: "ı", b: "İ", want: true},
+		{name: "plain I matches dotless", a: "I", b: "ı", want: true},
+
+		// Full-width forms typed with East Asian IMEs
+		{name: "full-width letters", a: "ＭａｊｏｒＮｅｌｓｏｎ", b: "MajorNelson", want: true},
+		{name: "full-width digits", a: "Player１２３", b: "player123", want: true},
+		{name: "full-width case", a: "ｍａｊｏｒ", b: "MAJOR", want: true},
+		{name: "ideographic space ignored", a: "Major　Nelson", b: "MajorNelson", want: true},
+		{name: "full-width different", a: "ＭａｊｏｒＮｅｌｓｏｎ", b: "MinorNelson", want: false},
+
+		// Unicode case folding beyond ASCII
+		{name: "kelvin sign", a: "Ken", b: "ken", want: true},
+		{name: "greek final sigma", a: "ΟΔΥΣΣΕΥΣ", b: "οδυσσευς", want: true},
+		{name: "cyrillic", a: "Иван", b: "иВАН", want: true},
+
+		// Decomposed (NFD) input matches precomposed (NFC) input
+		{name: "nfd acute", a: "Jose\u0301", b: "José", want: true},
+		{name: "nfd case", a: "E\u0301lan", b: "élan", want: true},
+		{name: "nfd cedilla", a: "Franc\u0327ois", b: "François", want: true},
+		{name: "nfd two marks", a: "Nguye\u0302\u0303n", b: "Nguyễn", want: true},
+		{name: "nfd dotted capital I", a: "I\u0307stanbul", b: "istanbul", want: true},
+		{name: "mark not dropped", a: "Jose\u0301", b: "Jose", want: false},
+		{name: "nfd different accent", a: "Jose\u0300", b: "José", want: false},
+
+		// Outside the partial fold: other compatibility characters stay distinct
+		{name: "ligature not folded", a: "ﬁre", b: "fire", want: false},
+		{name: "circled letter not folded", a: "Ⓐce", b: "Ace", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GamertagsEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("GamertagsEqual(%q, %q) = %v; want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := GamertagsEqual(tt.b, tt.a); got != tt.want {
+				t.Errorf("GamertagsEqual(%q, %q) = %v; want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatinCompositionTable(t *testing.T) {
+	for _, entry := range latinCompositionTable {
+		pairs := []rune(entry.pairs)
+		if len(pairs)%2 != 0 {
+			t.Errorf("mark %U: odd number of runes in %q", entry.mark, entry.pairs)
+			continue
+		}
+		for i := 0; i < len(pairs); i += 2 {
+			decomposed := string([]rune{pairs[i], entry.mark})
+			if !GamertagsEqual(decomposed, string(pairs[i+1])) {
+				t.Errorf("%q (%U %U) doesn't match %q", decomposed, pairs[i], entry.mark, pairs[i+1])
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package format renders xblive profiles and presence as Discord embeds and Markdown summaries
+package format
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+const (
+	// ColorOnline is the embed color used for online users without a preferred color
+	ColorOnline = 0x107C10
+
+	// ColorOffline is the embed color used for offline users without a preferred color
+	ColorOffline = 0x737373
+
+	// profileURL is the public Xbox profile page for a gamertag
+	profileURL = "https://www.xbox.com/play/user/%s"
+)
+
+// Embed is a Discord message embed
+// It marshals to the JSON Discord's API and webhooks expect
+type Embed struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Color       int           `json:"color,omitempty"`
+	Timestamp   string        `json:"timestamp,omitempty"`
+	Thumbnail   *EmbedImage   `json:"thumbnail,omitempty"`
+	Fields      []*EmbedField `json:"fields,omitempty"`
+	Footer      *EmbedFooter  `json:"footer,omitempty"`
+}
+
+// EmbedImage is an image in an embed
+type EmbedImage struct {
+	URL string `json:"url"`
+}
+
+// EmbedField is a name/value field in an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// EmbedFooter is the footer of an embed
+type EmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// ProfileEmbed renders a profile as a Discord embed
+// presence is optional; without it the profile's own presence fields are used
+func ProfileEmbed(p *xblive.Profile, presence *xblive.Presence) *Embed {
+	online, status := presenceStatus(p, presence)
+
+	embed := &Embed{
+		Title:       p.Gamertag,
+		Description: EscapeMarkdown(status),
+		URL:         fmt.Sprintf(profileURL, url.PathEscape(p.Gamertag)),
+		Color:       profileColor(p, online),
+		Footer:      &EmbedFooter{Text: "XUID " + p.XUID},
+	}
+
+	if p.DisplayPicRaw != "" {
+		embed.Thumbnail = &EmbedImage{URL: p.DisplayPicRaw}
+	}
+
+	if p.GamerScore != "" {
+		embed.Fields = append(embed.Fields, &EmbedField{Name: "Gamerscore", Value: p.GamerScore, Inline: true})
+	}
+	if p.XboxOneRep != "" {
+		embed.Fields = append(embed.Fields, &EmbedField{Name: "Reputation", Value: EscapeMarkdown(string(p.XboxOneRep)), Inline: true})
+	}
+	if p.Detail != nil && p.Detail.AccountTier != "" {
+		embed.Fields = append(embed.Fields, &EmbedField{Name: "Tier", Value: EscapeMarkdown(string(p.Detail.AccountTier)), Inline: true})
+	}
+
+	if presence != nil && presence.LastSeen != nil && !presence.IsOnline() && !presence.LastSeen.Timestamp.IsZero() {
+		embed.Timestamp = presence.LastSeen.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	return embed
+}
+
+// ProfileMarkdown renders a profile as a short Markdown summary suitable for chat messages
+// presence is optional; without it the profile's own presence fields are used
+func ProfileMarkdown(p *xblive.Profile, presence *xblive.Presence) string {
+	_, status := presenceStatus(p, presence)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**", EscapeMarkdown(p.Gamertag))
+	if p.GamerScore != "" {
+		fmt.Fprintf(&b, " (%sG)", p.GamerScore)
+	}
+	fmt.Fprintf(&b, "\n%s", EscapeMarkdown(status))
+	return b.String()
+}
+
+// presenceStatus returns whether the user is online and a one-line status
+func presenceStatus(p *xblive.Profile, presence *xblive.Presence) (bool, string) {
+	if presence == nil {
+		online := p.PresenceState.IsOnline()
+		if p.PresenceText != "" {
+			return online, p.PresenceText
+		}
+		if online {
+			return true, "Online"
+		}
+		return false, "Offline"
+	}
+
+	if !presence.IsOnline() {
+		if presence.LastSeen != nil && presence.LastSeen.TitleName != "" {
+			return false, "Last seen in " + presence.LastSeen.TitleName
+		}
+		return false, "Offline"
+	}
+
+	var playing []string
+	for _, title := range presence.ActiveTitles() {
+		if title.Placement == "Background" || title.Name == "" {
+			continue
+		}
+		entry := title.Name
+		if title.Activity != nil && title.Activity.RichPresence != "" {
+			entry += " - " + title.Activity.RichPresence
+		}
+		playing = append(playing, entry)
+	}
+	if len(playing) == 0 {
+		return true, "Online"
+	}
+	return true, "Playing " + strings.Join(playing, ", ")
+}
+
+// profileColor returns the user's preferred color, or a color reflecting their online state
+func profileColor(p *xblive.Profile, online bool) int {
+	if p.PreferredColor != nil {
+		hex := strings.TrimPrefix(p.PreferredColor.PrimaryColor, "#")
+		if color, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return int(color)
+		}
+	}
+	if online {
+		return ColorOnline
+	}
+	return ColorOffline
+}
+
+// markdownEscaper escapes the characters Discord treats as Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	"`", "\\`",
+	`~`, `\~`,
+	`|`, `\|`,
+	`>`, `\>`,
+)
+
+// EscapeMarkdown escapes text so Discord renders it literally
+// Gamertags and rich presence often contain underscores and asterisks
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,163 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MatchDecision records how a batch lookup resolved an input gamertag
+type MatchDecision string
+
+const (
+	// MatchExact means a profile matched the input ignoring case and whitespace
+	MatchExact MatchDecision = "exact"
+
+	// MatchFuzzyAccepted means the best fuzzy candidate scored at least BatchOptions.FuzzyAcceptScore and was accepted
+	MatchFuzzyAccepted MatchDecision = "fuzzy_accepted"
+
+	// MatchFuzzyRejected means fuzzy candidates were found but none scored high enough to accept
+	MatchFuzzyRejected MatchDecision = "fuzzy_rejected"
+
+	// MatchNotFound means the search returned no candidates
+	MatchNotFound MatchDecision = "not_found"
+)
+
+// BatchOptions configures LookupGamertags
+type BatchOptions struct {
+	// FuzzyAcceptScore auto-accepts the best fuzzy candidate when its similarity is at least this score, in (0, 1] (optional)
+	// Zero never accepts fuzzy candidates
+	FuzzyAcceptScore float64
+}
+
+// FuzzyCandidate is a profile returned by search for an input gamertag, scored by similarity
+type FuzzyCandidate struct {
+	Profile *Profile `json:"profile"`
+
+	// Score is the similarity of the candidate's gamertag to the input, from 0 (nothing in common) to 1 (equal)
+	Score float64 `json:"score"`
+}
+
+// BatchResult is the outcome of looking up one input gamertag, recorded so imports can be audited
+type BatchResult struct {
+	Input    string        `json:"input"`
+	Decision MatchDecision `json:"decision"`
+
+	// XUID and Gamertag are set for exact and accepted fuzzy matches
+	XUID     string `json:"xuid,omitempty"`
+	Gamertag string `json:"gamertag,omitempty"`
+
+	// Score is the similarity of the accepted match (1 for exact matches)
+	Score float64 `json:"score"`
+
+	// Candidates are the fuzzy candidates, best first; empty for exact matches
+	Candidates []*FuzzyCandidate `json:"candidates,omitempty"`
+}
+
+// LookupGamertags looks up multiple gamertags, returning one result per input in input order
+// Inputs that differ only in case or whitespace are searched once. When there is no exact match, the
+// candidates are scored by similarity and the best is accepted if it reaches opts.FuzzyAcceptScore
+func (c *Client) LookupGamertags(ctx context.Context, gamertags []string, opts BatchOptions) ([]*BatchResult, error) {
+	if opts.FuzzyAcceptScore < 0 || opts.FuzzyAcceptScore > 1 {
+		return nil, fmt.Errorf("fuzzy accept score must be between 0 and 1")
+	}
+
+	resolved := make(map[string]*BatchResult)
+	results := make([]*BatchResult, 0, len(gamertags))
+	for _, input := range gamertags {
+		key := normalizeGamertag(input)
+
+		base, ok := resolved[key]
+		if !ok {
+			var err error
+			base, err = c.lookupGamertag(ctx, input, opts)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = base
+		}
+
+		result := *base
+		result.Input = input
+		results = append(results, &result)
+	}
+
+	return results, nil
+}
+
+// lookupGamertag searches for one gamertag and decides on a match
+func (c *Client) lookupGamertag(ctx context.Context, gamertag string, opts BatchOptions) (*BatchResult, error) {
+	profiles, exact, err := c.matchGamertag(ctx, gamertag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BatchResult{Input: gamertag}
+	if exact {
+		result.Decision = MatchExact
+		result.XUID = profiles[0].XUID
+		result.Gamertag = profiles[0].Gamertag
+		result.Score = 1
+		return result, nil
+	}
+
+	if len(profiles) == 0 {
+		result.Decision = MatchNotFound
+		return result, nil
+	}
+
+	for _, p := range profiles {
+		result.Candidates = append(result.Candidates, &FuzzyCandidate{Profile: p, Score: GamertagSimilarity(gamertag, p.Gamertag)})
+	}
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Score > result.Candidates[j].Score
+	})
+
+	best := result.Candidates[0]
+	if opts.FuzzyAcceptScore > 0 && best.Score >= opts.FuzzyAcceptScore {
+		result.Decision = MatchFuzzyAccepted
+		result.XUID = best.Profile.XUID
+		result.Gamertag = best.Profile.Gamertag
+		result.Score = best.Score
+		return result, nil
+	}
+
+	result.Decision = MatchFuzzyRejected
+	return result, nil
+}
+
+// GamertagSimilarity scores how similar two gamertags are, from 0 (nothing in common) to 1 (equal ignoring case and whitespace)
+// It is one minus the edit distance between the normalized gamertags divided by the length of the longer one
+func GamertagSimilarity(a string, b string) float64 {
+	ra := []rune(normalizeGamertag(a))
+	rb := []rune(normalizeGamertag(b))
+
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance returns the Levenshtein distance between two rune slices
+func editDistance(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,44 @@
+package xblive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// Gamerpic upload endpoint
+	gamerpicEndpoint = "https://gamerpics.xboxlive.com/users/me/gamerpic"
+
+	// maxGamerpicSize is the largest image accepted for upload
+	maxGamerpicSize = 5 << 20
+)
+
+// SetGamerpic uploads a new profile picture for the authenticated account
+// The image must be a PNG or JPEG no larger than 5 MB; a square image of at least 1080x1080 is recommended
+func (c *Client) SetGamerpic(ctx context.Context, image io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(image, maxGamerpicSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("image is empty")
+	}
+	if len(data) > maxGamerpicSize {
+		return fmt.Errorf("image is larger than %d bytes", maxGamerpicSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return fmt.Errorf("unsupported image type %s: must be PNG or JPEG", contentType)
+	}
+
+	return c.mutate(ctx, "set_gamerpic", "", func(ctx context.Context) error {
+		if err := c.xblRequestRaw(ctx, "POST", gamerpicEndpoint, ServiceGamerpics, contentType, bytes.NewReader(data), nil); err != nil {
+			return fmt.Errorf("failed to upload gamerpic: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,220 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// graphRequestInterval is the minimum time between friends list requests while walking the social graph
+const graphRequestInterval = time.Second
+
+// SocialGraph is a directed graph of users and their friend relationships
+type SocialGraph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is a user in the social graph
+// The authenticated user is the root node, with depth 0
+type GraphNode struct {
+	XUID     string `json:"xuid"`
+	Gamertag string `json:"gamertag"`
+	Depth    int    `json:"depth"`
+
+	// Private is set when the user's friends list could not be read due to their privacy settings
+	Private bool `json:"private,omitempty"`
+}
+
+// GraphEdge is a friend relationship from one user to another
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ExportSocialGraph walks the authenticated user's friends (and friends-of-friends up to depth) and returns the social graph
+// A depth of 1 returns only the caller's friends. Users whose friends lists are private are included as leaf nodes
+func (c *Client) ExportSocialGraph(ctx context.Context, depth int) (*SocialGraph, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1")
+	}
+
+	graph := &SocialGraph{}
+	nodes := make(map[string]*GraphNode)
+	edges := make(map[GraphEdge]bool)
+
+	addNode := func(xuid string, gamertag string, d int) *GraphNode {
+		if node, ok := nodes[xuid]; ok {
+			return node
+		}
+		node := &GraphNode{XUID: xuid, Gamertag: gamertag, Depth: d}
+		nodes[xuid] = node
+		graph.Nodes = append(graph.Nodes, node)
+		return node
+	}
+	addEdge := func(from string, to string) {
+		edge := GraphEdge{From: from, To: to}
+		if !edges[edge] {
+			edges[edge] = true
+			graph.Edges = append(graph.Edges, &edge)
+		}
+	}
+
+	// The root is the authenticated user, keyed by their real XUID so friends-of-friends link back to it
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	friends, err := c.GetFriends(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	root := addNode(claims.XUID, claims.Gamertag, 0)
+	var frontier []*GraphNode
+	for _, friend := range friends {
+		node := addNode(friend.XUID, friend.Gamertag, 1)
+		addEdge(root.XUID, node.XUID)
+		frontier = append(frontier, node)
+	}
+
+	for d := 2; d <= depth; d++ {
+		var next []*GraphNode
+		for _, node := range frontier {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(graphRequestInterval):
+			}
+
+			friends, err := c.GetFriendsOf(ctx, node.XUID)
+			if err != nil {
+				if errors.Is(err, ErrForbidden) {
+					node.Private = true
+					continue
+				}
+				return nil, err
+			}
+
+			for _, friend := range friends {
+				_, seen := nodes[friend.XUID]
+				child := addNode(friend.XUID, friend.Gamertag, d)
+				addEdge(node.XUID, child.XUID)
+				if !seen {
+					next = append(next, child)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return graph, nil
+}
+
+// WriteJSON writes the graph as JSON
+func (g *SocialGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+func (g *SocialGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph social {\n")
+	for _, node := range g.sortedNodes() {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", node.XUID, node.Gamertag)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteGraphML writes the graph in GraphML format
+func (g *SocialGraph) WriteGraphML(w io.Writer) error {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type node struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+	}
+	type key struct {
+		ID       string `xml:"id,attr"`
+		For      string `xml:"for,attr"`
+		AttrName string `xml:"attr.name,attr"`
+		AttrType string `xml:"attr.type,attr"`
+	}
+	type graph struct {
+		ID          string `xml:"id,attr"`
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []node `xml:"node"`
+		Edges       []edge `xml:"edge"`
+	}
+	type graphml struct {
+		XMLName xml.Name `xml:"graphml"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   graph    `xml:"graph"`
+	}
+
+	doc := graphml{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []key{
+			{ID: "gamertag", For: "node", AttrName: "gamertag", AttrType: "string"},
+			{ID: "depth", For: "node", AttrName: "depth", AttrType: "int"},
+		},
+		Graph: graph{ID: "social", EdgeDefault: "directed"},
+	}
+	for _, n := range g.sortedNodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{
+			ID: n.XUID,
+			Data: []data{
+				{Key: "gamertag", Value: n.Gamertag},
+				{Key: "depth", Value: fmt.Sprintf("%d", n.Depth)},
+			},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{Source: e.From, Target: e.To})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sortedNodes returns the nodes ordered by depth then XUID for stable output
+func (g *SocialGraph) sortedNodes() []*GraphNode {
+	nodes := make([]*GraphNode, len(g.Nodes))
+	copy(nodes, g.Nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		return nodes[i].XUID < nodes[j].XUID
+	})
+	return nodes
+}
@@ -0,0 +1,58 @@
+package xblive
+
+import (
+	"context"
+	"sync"
+)
+
+// HTTPCache stores response bodies for conditional requests (ETag / If-None-Match)
+// Keys identify the caller, contract version, and URL, so responses are never shared between accounts
+type HTTPCache interface {
+	Get(ctx context.Context, key string) (etag string, body []byte, ok bool)
+	Set(ctx context.Context, key string, etag string, body []byte) error
+}
+
+// MemoryHTTPCache is an in-memory HTTPCache
+// It is safe for concurrent use by multiple goroutines
+type MemoryHTTPCache struct {
+	mu      sync.Mutex
+	entries map[string]httpCacheEntry
+}
+
+type httpCacheEntry struct {
+	etag string
+	body []byte
+}
+
+// NewMemoryHTTPCache creates a new in-memory HTTP cache
+func NewMemoryHTTPCache() *MemoryHTTPCache {
+	return &MemoryHTTPCache{
+		entries: make(map[string]httpCacheEntry),
+	}
+}
+
+// Get returns the cached ETag and body for a key
+func (m *MemoryHTTPCache) Get(ctx context.Context, key string) (string, []byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	return entry.etag, entry.body, ok
+}
+
+// Set stores the ETag and body for a key
+func (m *MemoryHTTPCache) Set(ctx context.Context, key string, etag string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = httpCacheEntry{etag: etag, body: body}
+	return nil
+}
+
+// httpCacheKey returns the HTTP cache key for a GET request made by a user
+// Calls overriding the sandbox or language are cached apart, since their responses differ
+func httpCacheKey(userHash string, contractVersion string, endpoint string, opts CallOptions) string {
+	key := userHash + "|" + contractVersion + "|" + endpoint
+	if opts.Sandbox != "" || opts.Language != "" {
+		key += "|" + opts.Sandbox + "|" + opts.Language
+	}
+	return key
+}
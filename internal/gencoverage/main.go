@@ -0,0 +1,155 @@
+// Command gencoverage generates coverage.go, the map from each Xbox Live service to the exported
+// Client methods that call it. Run it with go generate from the repository root
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// requestFuncs are the Client methods that send a request to a service, with the index of their Service argument
+var requestFuncs = map[string]int{
+	"xblRequest":    3,
+	"xblRequestRaw": 3,
+}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != "coverage.go"
+	}, 0)
+	if err != nil {
+		log.Fatalf("failed to parse package: %v", err)
+	}
+	pkg, ok := pkgs["xblive"]
+	if !ok {
+		log.Fatalf("package xblive not found; run from the repository root")
+	}
+
+	// Services each Client method calls directly, and the Client methods it calls
+	services := map[string]map[string]bool{}
+	calls := map[string]map[string]bool{}
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || !isClientMethod(fn) {
+				continue
+			}
+			recv := fn.Recv.List[0].Names
+			if len(recv) == 0 {
+				continue
+			}
+			name, recvName := fn.Name.Name, recv[0].Name
+			services[name] = map[string]bool{}
+			calls[name] = map[string]bool{}
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if x, ok := sel.X.(*ast.Ident); !ok || x.Name != recvName {
+					return true
+				}
+				calls[name][sel.Sel.Name] = true
+				if i, ok := requestFuncs[sel.Sel.Name]; ok && i < len(call.Args) {
+					if id, ok := call.Args[i].(*ast.Ident); ok && strings.HasPrefix(id.Name, "Service") {
+						services[name][id.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	// Attribute each service to every exported method that reaches it
+	methods := map[string][]string{}
+	for name := range services {
+		if !ast.IsExported(name) {
+			continue
+		}
+		for service := range reachable(name, services, calls) {
+			methods[service] = append(methods[service], name)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by go run ./internal/gencoverage; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package xblive\n\n")
+	fmt.Fprintf(&buf, "// serviceMethods lists the exported Client methods that call each service\n")
+	fmt.Fprintf(&buf, "var serviceMethods = map[Service][]string{\n")
+	for _, service := range sortedKeys(methods) {
+		names := methods[service]
+		sort.Strings(names)
+		fmt.Fprintf(&buf, "\t%s: {%q", service, names[0])
+		for _, n := range names[1:] {
+			fmt.Fprintf(&buf, ", %q", n)
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format coverage.go: %v", err)
+	}
+	if err := os.WriteFile("coverage.go", src, 0644); err != nil {
+		log.Fatalf("failed to write coverage.go: %v", err)
+	}
+}
+
+// isClientMethod reports whether fn is a method on *Client
+func isClientMethod(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return false
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	id, ok := star.X.(*ast.Ident)
+	return ok && id.Name == "Client"
+}
+
+// reachable returns the services a method calls directly or through other Client methods
+func reachable(name string, services map[string]map[string]bool, calls map[string]map[string]bool) map[string]bool {
+	found := map[string]bool{}
+	seen := map[string]bool{}
+	var walk func(string)
+	walk = func(m string) {
+		if seen[m] {
+			return
+		}
+		seen[m] = true
+		for s := range services[m] {
+			found[s] = true
+		}
+		for callee := range calls[m] {
+			walk(callee)
+		}
+	}
+	walk(name)
+	return found
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
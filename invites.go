@@ -0,0 +1,83 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetPendingInvites returns the unexpired game invites sent to the authenticated user
+// Each is an MPSD invite handle; pass its ID to AcceptInvite to join the session it points to
+func (c *Client) GetPendingInvites(ctx context.Context) ([]*SessionHandle, error) {
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := InviteHandleQuery{
+		Type:        "invite",
+		InvitedXUID: claims.XUID,
+	}
+
+	var resp InviteHandleQueryResponse
+	if err := c.xblRequest(ctx, "POST", sessionDirectoryEndpoint+"/handles/query?include=relatedInfo", ServiceSessionDirectory, reqBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query invites: %w", err)
+	}
+
+	now := c.clock.Now()
+	var invites []*SessionHandle
+	for _, handle := range resp.Results {
+		if !handle.Expiration.IsZero() && !handle.Expiration.After(now) {
+			continue
+		}
+		invites = append(invites, handle)
+	}
+	return invites, nil
+}
+
+// AcceptInvite accepts a game invite by joining the session its invite handle points to as an active member
+// It fails if the handle isn't an invite addressed to the authenticated user. Returns the accepted invite
+func (c *Client) AcceptInvite(ctx context.Context, inviteID string) (*SessionHandle, error) {
+	if inviteID == "" {
+		return nil, fmt.Errorf("invite ID is required")
+	}
+
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := c.GetSessionHandle(ctx, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	if invite.Type != "invite" {
+		return nil, fmt.Errorf("handle %s is a %s handle, not an invite", inviteID, invite.Type)
+	}
+	if invite.InvitedXUID != claims.XUID {
+		return nil, fmt.Errorf("%w: invite %s is addressed to another user", ErrForbidden, inviteID)
+	}
+
+	update := MultiplayerSession{
+		Members: map[string]*SessionMember{
+			"me": {
+				Constants: &SessionMemberConstants{System: &SessionMemberConstantsSystem{
+					Initialize:   true,
+					InviteHandle: inviteID,
+				}},
+				Properties: &SessionMemberProperties{System: &SessionMemberPropertiesSystem{Active: true}},
+			},
+		},
+	}
+
+	err = c.mutate(ctx, "accept_invite", "", func(ctx context.Context) error {
+		if _, err := c.putSession(ctx, invite.SessionRef, update); err != nil {
+			return fmt.Errorf("failed to accept invite: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
@@ -0,0 +1,93 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// JoinURLScheme is the URI scheme of join links
+const JoinURLScheme = "xbox"
+
+// joinURLHost and joinURLPath locate the join action in a join link: xbox://multiplayer/join?handle=<id>
+const (
+	joinURLHost = "multiplayer"
+	joinURLPath = "/join"
+)
+
+// CreateJoinHandle creates an MPSD activity handle for a session, through which anyone with the handle ID can join it
+// The authenticated user must be a member of the session. Share it as a link with JoinURL
+func (c *Client) CreateJoinHandle(ctx context.Context, ref SessionRef) (*SessionHandle, error) {
+	if err := validateSessionRef(ref); err != nil {
+		return nil, err
+	}
+
+	reqBody := SessionHandleRequest{
+		Type:       "activity",
+		Version:    1,
+		SessionRef: ref,
+	}
+
+	var handle SessionHandle
+	err := c.mutate(ctx, "create_join_handle", "", func(ctx context.Context) error {
+		if err := c.xblRequest(ctx, "POST", sessionDirectoryEndpoint+"/handles", ServiceSessionDirectory, reqBody, &handle); err != nil {
+			return fmt.Errorf("failed to create join handle: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &handle, nil
+}
+
+// GetSessionHandle resolves an MPSD handle to the session it points to
+func (c *Client) GetSessionHandle(ctx context.Context, handleID string) (*SessionHandle, error) {
+	if handleID == "" {
+		return nil, fmt.Errorf("handle ID is required")
+	}
+
+	var handle SessionHandle
+	endpoint := fmt.Sprintf("%s/handles/%s", sessionDirectoryEndpoint, url.PathEscape(handleID))
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceSessionDirectory, nil, &handle); err != nil {
+		return nil, fmt.Errorf("failed to get session handle: %w", err)
+	}
+	return &handle, nil
+}
+
+// ResolveJoinURL resolves a join link made by JoinURL to its handle and session
+func (c *Client) ResolveJoinURL(ctx context.Context, link string) (*SessionHandle, error) {
+	handleID, err := ParseJoinURL(link)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetSessionHandle(ctx, handleID)
+}
+
+// JoinURL returns a join deep link for a session handle, such as one created by CreateJoinHandle
+func JoinURL(handleID string) string {
+	u := url.URL{
+		Scheme:   JoinURLScheme,
+		Host:     joinURLHost,
+		Path:     joinURLPath,
+		RawQuery: url.Values{"handle": {handleID}}.Encode(),
+	}
+	return u.String()
+}
+
+// ParseJoinURL returns the handle ID in a join link made by JoinURL
+func ParseJoinURL(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid join URL: %w", err)
+	}
+	if u.Scheme != JoinURLScheme || u.Host != joinURLHost || u.Path != joinURLPath {
+		return "", fmt.Errorf("invalid join URL %q: expected %s://%s%s?handle=<id>", link, JoinURLScheme, joinURLHost, joinURLPath)
+	}
+	handleID := u.Query().Get("handle")
+	if handleID == "" {
+		return "", fmt.Errorf("invalid join URL %q: no handle", link)
+	}
+	return handleID, nil
+}
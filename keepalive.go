@@ -0,0 +1,73 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DefaultKeepAliveInterval is the keep-alive refresh interval used when none is configured
+const DefaultKeepAliveInterval = 7 * 24 * time.Hour
+
+// KeepAliveOptions configures KeepAlive
+type KeepAliveOptions struct {
+	// Interval is the time between refreshes (optional, defaults to DefaultKeepAliveInterval)
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added to each interval (optional)
+	Jitter time.Duration
+
+	// OnError is called when a refresh fails (optional)
+	OnError func(err error)
+
+	// OnRefresh is called after each successful refresh (optional)
+	OnRefresh func()
+}
+
+// KeepAlive redeems the refresh token on a schedule so long-lived credentials don't expire due to inactivity
+// It blocks until the context is cancelled or the client is shut down. Refresh failures are reported to OnError and retried on the next interval
+func (c *Client) KeepAlive(ctx context.Context, opts KeepAliveOptions) error {
+	ctx, done, err := c.track(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+	if opts.Jitter < 0 {
+		return fmt.Errorf("jitter must not be negative")
+	}
+
+	for {
+		delay := interval
+		if opts.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(opts.Jitter)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return c.stopped(ctx)
+		case <-c.clock.After(delay):
+		}
+
+		c.tokenMu.Lock()
+		err := c.refreshAccessToken(ctx)
+		c.tokenMu.Unlock()
+
+		if err != nil {
+			c.logger.Warn("keep-alive refresh failed", "error", err)
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("keep-alive refresh failed: %w", err))
+			}
+			continue
+		}
+
+		if opts.OnRefresh != nil {
+			opts.OnRefresh()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Leaderboards endpoint
+const leaderboardsEndpoint = "https://leaderboards.xboxlive.com"
+
+// LeaderboardOptions controls which page of a leaderboard is returned
+// SkipToUser and SkipToRank start the page at a position instead of the top; set at most one of them,
+// and neither together with a continuation token
+type LeaderboardOptions struct {
+	PageOptions
+
+	// SkipToUser starts the page so that it includes this user's entry, e.g. to show "my rank and neighbors" (optional)
+	SkipToUser string
+
+	// SkipToRank starts the page at this rank, 1-based (optional)
+	SkipToRank int
+}
+
+// Leaderboard is a page of a title's leaderboard
+type Leaderboard struct {
+	Columns    []*LeaderboardColumn
+	TotalCount int
+	Entries    []*LeaderboardEntry
+
+	// ContinuationToken fetches the next page when passed in LeaderboardOptions; empty when there are no more entries
+	ContinuationToken string
+}
+
+// StatLeaderboard returns the name of the leaderboard that ranks a 2017 stats model stat, for use with GetLeaderboard
+func StatLeaderboard(statName string) string {
+	return "stat(" + url.PathEscape(statName) + ")"
+}
+
+// GetLeaderboard returns a page of a title's global leaderboard
+// name is a leaderboard configured for the title, or StatLeaderboard(stat) for a stat written with WriteTitleStats;
+// it is used as a path segment as given
+func (c *Client) GetLeaderboard(ctx context.Context, scid SCID, name string, opts LeaderboardOptions) (*Leaderboard, error) {
+	if scid == "" {
+		return nil, fmt.Errorf("SCID is required")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("leaderboard name is required")
+	}
+	if opts.SkipToRank < 0 {
+		return nil, fmt.Errorf("skip to rank must not be negative")
+	}
+	if opts.SkipToUser != "" && opts.SkipToRank > 0 {
+		return nil, fmt.Errorf("skip to user and skip to rank are mutually exclusive")
+	}
+	if opts.ContinuationToken != "" && (opts.SkipToUser != "" || opts.SkipToRank > 0) {
+		return nil, fmt.Errorf("a continuation token cannot be combined with skip to user or rank")
+	}
+
+	params, err := opts.query("maxItems", "continuationToken")
+	if err != nil {
+		return nil, err
+	}
+	if opts.SkipToUser != "" {
+		params.Set("skipToUser", opts.SkipToUser)
+	}
+	if opts.SkipToRank > 0 {
+		params.Set("skipToRank", strconv.Itoa(opts.SkipToRank))
+	}
+
+	endpoint := fmt.Sprintf("%s/scids/%s/leaderboards/%s?%s", leaderboardsEndpoint, url.PathEscape(string(scid)), name, params.Encode())
+
+	var resp LeaderboardResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceLeaderboards, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	leaderboard := &Leaderboard{Entries: resp.UserList}
+	if resp.LeaderboardInfo != nil {
+		leaderboard.Columns = resp.LeaderboardInfo.Columns
+		leaderboard.TotalCount = resp.LeaderboardInfo.TotalCount
+	}
+	if resp.PagingInfo != nil {
+		leaderboard.ContinuationToken = resp.PagingInfo.ContinuationToken
+	}
+	if leaderboard.Entries == nil {
+		leaderboard.Entries = []*LeaderboardEntry{}
+	}
+	return leaderboard, nil
+}
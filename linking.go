@@ -0,0 +1,118 @@
+package xblive
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// OAuth authorization endpoint, formatted with the tenant
+	authorizeEndpoint = "https://login.microsoftonline.com/%s/oauth2/v2.0/authorize"
+)
+
+// PKCE holds a Proof Key for Code Exchange verifier and its S256 challenge
+type PKCE struct {
+	Verifier  string
+	Challenge string
+}
+
+// NewPKCE generates a new random PKCE verifier and challenge
+func NewPKCE() (*PKCE, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate PKCE verifier: %w", err)
+	}
+
+	verifier := base64.RawURLEncoding.EncodeToString(b[:])
+	sum := sha256.Sum256([]byte(verifier))
+
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+	}, nil
+}
+
+// GenerateAuthURL returns the URL to send a user to for the authorization code flow (account linking)
+// state is echoed back to the redirect URI and must be verified by the caller. pkce is optional but recommended
+func (c *Client) GenerateAuthURL(state string, pkce *PKCE) (string, error) {
+	if c.redirectURI == "" {
+		return "", fmt.Errorf("redirect URI is required for the authorization code flow")
+	}
+	if state == "" {
+		return "", fmt.Errorf("state is required")
+	}
+
+	params := url.Values{}
+	params.Set("client_id", c.clientID)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", c.redirectURI)
+	params.Set("response_mode", "query")
+	params.Set("scope", scopes)
+	params.Set("state", state)
+	if pkce != nil {
+		params.Set("code_challenge", pkce.Challenge)
+		params.Set("code_challenge_method", "S256")
+	}
+
+	return fmt.Sprintf(authorizeEndpoint, c.tenant) + "?" + params.Encode(), nil
+}
+
+// ExchangeCode redeems an authorization code received at the redirect URI for tokens
+// The returned tokens are not stored in the client's cache; persist them per user and bootstrap
+// a client with AuthenticateWithRefreshToken when acting on that user's behalf
+func (c *Client) ExchangeCode(ctx context.Context, code string, verifier string) (*CachedTokens, error) {
+	if c.redirectURI == "" {
+		return nil, fmt.Errorf("redirect URI is required for the authorization code flow")
+	}
+	if code == "" {
+		return nil, fmt.Errorf("authorization code is required")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("client_id", c.clientID)
+	data.Set("code", code)
+	data.Set("redirect_uri", c.redirectURI)
+	data.Set("scope", scopes)
+	if verifier != "" {
+		data.Set("code_verifier", verifier)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf(tokenEndpoint, c.tenant), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, truncated := captureErrorBody(resp.Body)
+		return nil, newXboxAPIError("authorization code exchange", resp, body, truncated)
+	}
+
+	var token TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	return &CachedTokens{
+		AccessToken:        token.AccessToken,
+		AccessTokenExpiry:  now.Add(time.Duration(token.ExpiresIn) * time.Second),
+		RefreshToken:       token.RefreshToken,
+		RefreshTokenIssued: now,
+	}, nil
+}
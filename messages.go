@@ -0,0 +1,75 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Messaging endpoint
+	messagingEndpoint = "https://xblmessaging.xboxlive.com/network/Xbox/users/me"
+)
+
+// GetConversations returns one page of the conversations in the authenticated user's primary inbox, most recent first
+// Returns the conversations and the continuation token for the next page (empty when there are no more)
+func (c *Client) GetConversations(ctx context.Context, opts PageOptions) ([]*Conversation, string, error) {
+	params, err := opts.query("maxItems", "continuationToken")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/inbox/primary?%s", messagingEndpoint, params.Encode())
+
+	var resp ConversationsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceMessaging, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	return resp.Primary.Conversations, resp.Primary.ContinuationToken, nil
+}
+
+// GetMessages returns one page of the authenticated user's messages with another user, newest first
+// Returns the messages and the continuation token for the next (older) page (empty when there are no more)
+func (c *Client) GetMessages(ctx context.Context, xuid string, opts PageOptions) ([]*Message, string, error) {
+	if xuid == "" {
+		return nil, "", fmt.Errorf("XUID is required")
+	}
+	return c.getMessages(ctx, fmt.Sprintf("%s/conversations/users/xuid(%s)", messagingEndpoint, url.PathEscape(xuid)), opts)
+}
+
+// GetConversationMessages returns one page of the messages in a conversation, one-to-one or group, newest first
+// Returns the messages and the continuation token for the next (older) page (empty when there are no more)
+func (c *Client) GetConversationMessages(ctx context.Context, conversationID string, opts PageOptions) ([]*Message, string, error) {
+	if conversationID == "" {
+		return nil, "", fmt.Errorf("conversation ID is required")
+	}
+	return c.getMessages(ctx, fmt.Sprintf("%s/conversations/%s", messagingEndpoint, url.PathEscape(conversationID)), opts)
+}
+
+// getMessages fetches one page of a conversation's messages from its endpoint
+func (c *Client) getMessages(ctx context.Context, conversationEndpoint string, opts PageOptions) ([]*Message, string, error) {
+	params, err := opts.query("maxItems", "startAfter")
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := conversationEndpoint + "?" + params.Encode()
+
+	var resp MessagesResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceMessaging, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	// Messages are paged by the ID of the last message returned
+	var next string
+	maxItems := opts.MaxItems
+	if maxItems == 0 {
+		maxItems = DefaultPageSize
+	}
+	if len(resp.Messages) == maxItems {
+		next = resp.Messages[len(resp.Messages)-1].MessageID
+	}
+
+	return resp.Messages, next, nil
+}
@@ -0,0 +1,55 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConversationPaging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/network/Xbox/users/me/inbox/primary":
+			var resp ConversationsResponse
+			if r.URL.Query().Get("continuationToken") == "" {
+				resp.Primary.Conversations = []*Conversation{{ConversationID: "one"}}
+				resp.Primary.ContinuationToken = "page2"
+			} else {
+				resp.Primary.Conversations = []*Conversation{{ConversationID: "group:two"}}
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "/network/Xbox/users/me/conversations/group:two":
+			_ = json.NewEncoder(w).Encode(MessagesResponse{Messages: []*Message{{MessageID: "m1"}}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.Error(w, "unexpected", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	if err := cache.SetXSTSToken(ctx, "xsts-token", "user-hash", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	conversations, err := Crawl(ctx, client.GetConversations, CrawlOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conversations) != 2 || conversations[1].ConversationID != "group:two" {
+		t.Fatalf("GetConversations returned %d conversations; want both pages", len(conversations))
+	}
+
+	messages, _, err := client.GetConversationMessages(ctx, "group:two", PageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || messages[0].MessageID != "m1" {
+		t.Errorf("GetConversationMessages returned %d messages; want m1", len(messages))
+	}
+}
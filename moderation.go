@@ -0,0 +1,71 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ModerationReport summarizes the users the authenticated user recently played with, for handling abuse reports
+type ModerationReport struct {
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Entries     []*ModerationEntry `json:"entries"`
+}
+
+// ModerationEntry describes one recent player: who they are, their reputation, presence, and the titles played together
+type ModerationEntry struct {
+	XUID           string               `json:"xuid"`
+	Gamertag       string               `json:"gamertag"`
+	Reputation     Reputation           `json:"reputation"`
+	IsQuarantined  bool                 `json:"isQuarantined"`
+	PresenceState  PresenceState        `json:"presenceState"`
+	PresenceText   string               `json:"presenceText"`
+	LastPlayedWith time.Time            `json:"lastPlayedWith"`
+	SharedTitles   []*RecentPlayerTitle `json:"sharedTitles"`
+}
+
+// GetModerationReport builds a moderation report from the authenticated user's recent players
+// Entries are ordered as returned by the service, most recent first
+func (c *Client) GetModerationReport(ctx context.Context) (*ModerationReport, error) {
+	players, err := c.GetRecentPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ModerationReport{
+		GeneratedAt: c.clock.Now(),
+		Entries:     make([]*ModerationEntry, 0, len(players)),
+	}
+
+	for _, player := range players {
+		entry := &ModerationEntry{
+			XUID:          player.XUID,
+			Gamertag:      player.Gamertag,
+			Reputation:    player.XboxOneRep,
+			IsQuarantined: player.IsQuarantined,
+			PresenceState: player.PresenceState,
+			PresenceText:  player.PresenceText,
+		}
+
+		if player.RecentPlayer != nil {
+			entry.SharedTitles = player.RecentPlayer.Titles
+			for _, title := range player.RecentPlayer.Titles {
+				if title.LastPlayedWithDateTime.After(entry.LastPlayedWith) {
+					entry.LastPlayedWith = title.LastPlayedWithDateTime
+				}
+			}
+		}
+
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report, nil
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *ModerationReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,37 @@
+package xblive
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// DefaultPageSize is the number of items requested per page when PageOptions.MaxItems isn't set
+const DefaultPageSize = 100
+
+// PageOptions controls a single page of a paged list API
+type PageOptions struct {
+	// MaxItems is the maximum number of items to return (optional, defaults to DefaultPageSize)
+	MaxItems int
+
+	// ContinuationToken resumes from the previous page; pass the token returned with that page (optional)
+	ContinuationToken string
+}
+
+// query returns the page options as query parameters using the given parameter names
+func (o PageOptions) query(maxItemsParam string, continuationParam string) (url.Values, error) {
+	if o.MaxItems < 0 {
+		return nil, fmt.Errorf("max items must not be negative")
+	}
+
+	maxItems := o.MaxItems
+	if maxItems == 0 {
+		maxItems = DefaultPageSize
+	}
+
+	params := url.Values{}
+	params.Set(maxItemsParam, fmt.Sprintf("%d", maxItems))
+	if o.ContinuationToken != "" {
+		params.Set(continuationParam, o.ContinuationToken)
+	}
+	return params, nil
+}
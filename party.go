@@ -0,0 +1,151 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Xbox party sessions live under a well-known service config and template
+const (
+	partySCID         = "7492baca-c1b4-440d-a391-b7ef364a8d40"
+	partyTemplateName = "chat"
+)
+
+// Party is the state of an Xbox party (voice chat session)
+type Party struct {
+	Ref     SessionRef     `json:"ref"`
+	Members []*PartyMember `json:"members"`
+}
+
+// PartyMember is a member of a party
+type PartyMember struct {
+	Index    string `json:"index"`
+	XUID     string `json:"xuid"`
+	Gamertag string `json:"gamertag"`
+	Active   bool   `json:"active"`
+}
+
+// CreateParty creates a new party with the authenticated user as its only member
+func (c *Client) CreateParty(ctx context.Context) (*Party, error) {
+	name, err := newSessionName()
+	if err != nil {
+		return nil, err
+	}
+
+	ref := SessionRef{SCID: partySCID, TemplateName: partyTemplateName, Name: name}
+	update := MultiplayerSession{
+		Members: map[string]*SessionMember{
+			"me": {
+				Constants:  &SessionMemberConstants{System: &SessionMemberConstantsSystem{Initialize: true}},
+				Properties: &SessionMemberProperties{System: &SessionMemberPropertiesSystem{Active: true}},
+			},
+		},
+	}
+
+	var session *MultiplayerSession
+	err = c.mutate(ctx, "create_party", "", func(ctx context.Context) error {
+		var err error
+		session, err = c.putSession(ctx, ref, update)
+		if err != nil {
+			return fmt.Errorf("failed to create party: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newParty(ref, session), nil
+}
+
+// GetParty returns the current state of a party
+func (c *Client) GetParty(ctx context.Context, ref SessionRef) (*Party, error) {
+	if err := validateSessionRef(ref); err != nil {
+		return nil, err
+	}
+
+	session, err := c.getSession(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return newParty(ref, session), nil
+}
+
+// InviteToParty invites a user to a party
+// Parties aren't tied to a title, so the invite carries no title ID
+func (c *Client) InviteToParty(ctx context.Context, ref SessionRef, xuid string) error {
+	_, err := c.sendInvite(ctx, xuid, ref, nil)
+	return err
+}
+
+// KickFromParty removes a user from a party
+func (c *Client) KickFromParty(ctx context.Context, ref SessionRef, xuid string) error {
+	if xuid == "" {
+		return fmt.Errorf("XUID is required")
+	}
+
+	party, err := c.GetParty(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	var index string
+	for _, member := range party.Members {
+		if member.XUID == xuid {
+			index = member.Index
+			break
+		}
+	}
+	if index == "" {
+		return fmt.Errorf("%w: XUID '%s' is not a member of the party", ErrNotFound, xuid)
+	}
+
+	// Setting a member to null removes it from the session
+	update := map[string]map[string]*SessionMember{
+		"members": {index: nil},
+	}
+
+	return c.mutate(ctx, "kick_from_party", xuid, func(ctx context.Context) error {
+		if _, err := c.putSession(ctx, ref, update); err != nil {
+			return fmt.Errorf("failed to kick party member: %w", err)
+		}
+		return nil
+	})
+}
+
+// newParty converts an MPSD session into a Party
+func newParty(ref SessionRef, session *MultiplayerSession) *Party {
+	party := &Party{Ref: ref}
+	for index, member := range session.Members {
+		if member == nil {
+			continue
+		}
+		pm := &PartyMember{Index: index, Gamertag: member.Gamertag}
+		if member.Constants != nil && member.Constants.System != nil {
+			pm.XUID = member.Constants.System.XUID
+		}
+		if member.Properties != nil && member.Properties.System != nil {
+			pm.Active = member.Properties.System.Active
+		}
+		party.Members = append(party.Members, pm)
+	}
+
+	// Member indexes are decimal strings; order them numerically so "10" follows "9"
+	sort.Slice(party.Members, func(i, j int) bool {
+		x, xErr := strconv.Atoi(party.Members[i].Index)
+		y, yErr := strconv.Atoi(party.Members[j].Index)
+		if xErr != nil || yErr != nil {
+			// Unparseable indexes sort after numeric ones, in string order
+			if (xErr == nil) != (yErr == nil) {
+				return xErr == nil
+			}
+			return party.Members[i].Index < party.Members[j].Index
+		}
+		return x < y
+	})
+
+	return party
+}
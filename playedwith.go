@@ -0,0 +1,109 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SharedSession is a title in which the authenticated user played with another user
+type SharedSession struct {
+	TitleID        string    `json:"title_id"`
+	TitleName      string    `json:"title_name"`
+	LastPlayedWith time.Time `json:"last_played_with"`
+
+	// Session is the multiplayer session both users are in right now, if any
+	Session *SessionRef `json:"session,omitempty"`
+}
+
+// GetSharedSessions returns the titles in which the authenticated user played with a user within the given window, most recent first
+// It combines the recent players list with the users' current MPSD activity, so a session still in progress is
+// included (with its SessionRef) even before it shows up in recent players
+func (c *Client) GetSharedSessions(ctx context.Context, xuid string, window time.Duration) ([]*SharedSession, error) {
+	if xuid == "" {
+		return nil, fmt.Errorf("XUID is required")
+	}
+
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := c.GetRecentPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	cutoff := now.Add(-window)
+	byTitle := map[string]*SharedSession{}
+	for _, p := range players {
+		if p.XUID != xuid || p.RecentPlayer == nil {
+			continue
+		}
+		for _, t := range p.RecentPlayer.Titles {
+			if t.LastPlayedWithDateTime.Before(cutoff) {
+				continue
+			}
+			byTitle[t.TitleID] = &SharedSession{TitleID: t.TitleID, TitleName: t.TitleName, LastPlayedWith: t.LastPlayedWithDateTime}
+		}
+	}
+
+	handles, err := c.getActivityHandles(ctx, []string{claims.XUID, xuid})
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range sharedActivity(handles, claims.XUID, xuid) {
+		shared, ok := byTitle[h.TitleID]
+		if !ok {
+			shared = &SharedSession{TitleID: h.TitleID}
+			byTitle[h.TitleID] = shared
+		}
+		ref := h.SessionRef
+		shared.Session = &ref
+		shared.LastPlayedWith = now
+	}
+
+	sessions := make([]*SharedSession, 0, len(byTitle))
+	for _, s := range byTitle {
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastPlayedWith.After(sessions[j].LastPlayedWith)
+	})
+	return sessions, nil
+}
+
+// getActivityHandles returns the MPSD activity handles (current sessions) of a set of users
+func (c *Client) getActivityHandles(ctx context.Context, xuids []string) ([]*ActivityHandle, error) {
+	reqBody := SessionHandleQuery{
+		Type:   "activity",
+		Owners: SessionHandleQueryOwners{XUIDs: xuids},
+	}
+
+	var resp SessionHandleQueryResponse
+	if err := c.xblRequest(ctx, "POST", sessionDirectoryEndpoint+"/handles/query?include=relatedInfo", ServiceSessionDirectory, reqBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+
+	return resp.Results, nil
+}
+
+// sharedActivity returns the handles of a's sessions that b is also in
+func sharedActivity(handles []*ActivityHandle, a string, b string) []*ActivityHandle {
+	inB := map[SessionRef]bool{}
+	for _, h := range handles {
+		if h.OwnerXUID == b {
+			inB[h.SessionRef] = true
+		}
+	}
+
+	var shared []*ActivityHandle
+	for _, h := range handles {
+		if h.OwnerXUID == a && inB[h.SessionRef] {
+			shared = append(shared, h)
+		}
+	}
+	return shared
+}
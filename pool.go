@@ -0,0 +1,194 @@
+package xblive
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// DefaultPoolSize is the maximum number of clients a ClientPool keeps when none is configured
+const DefaultPoolSize = 1000
+
+// PoolConfig contains configuration for a ClientPool
+type PoolConfig struct {
+	// Config is the configuration shared by every client in the pool (ClientID is required)
+	// Config.Cache must be a shared cache that keeps a token chain per scope (see ScopeAwareTokenCache); if nil, an
+	// in-memory cache is used
+	// Config.CachePath and Config.CacheScope are ignored
+	Config Config
+
+	// MaxClients is the number of clients kept before the least recently used is evicted (optional, defaults to DefaultPoolSize)
+	MaxClients int
+}
+
+// ClientPool manages clients acting on behalf of many Xbox accounts
+// Each user gets a client with its own token chain, scoped within the shared cache by user ID.
+// All clients share one HTTP client (and its connection pool). Evicted clients are shut down, stopping their KeepAlive
+// loops and watchers, but lose nothing else: their tokens remain in the cache
+type ClientPool struct {
+	mu         sync.Mutex
+	config     Config
+	cache      TokenCache
+	httpClient *http.Client
+	maxClients int
+	lru        *list.List
+	clients    map[string]*list.Element
+	closed     bool
+
+	// retiring tracks evicted and removed clients that are still shutting down
+	retiring sync.WaitGroup
+}
+
+// poolEntry is an element of the pool's LRU list
+type poolEntry struct {
+	userID string
+	client *Client
+}
+
+// NewClientPool creates a new client pool
+func NewClientPool(config PoolConfig) (*ClientPool, error) {
+	if err := config.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if config.MaxClients < 0 {
+		return nil, fmt.Errorf("max clients must not be negative")
+	}
+
+	maxClients := config.MaxClients
+	if maxClients == 0 {
+		maxClients = DefaultPoolSize
+	}
+
+	// Clients share the profile's quota, as they would a configured one
+	config.Config.Quota = config.Config.quota()
+
+	cache := config.Config.Cache
+	if cache == nil {
+		cache = NewMemoryTokenCache()
+	} else if sa, ok := cache.(ScopeAwareTokenCache); !ok || !sa.ScopeAware() {
+		// A cache that ignores scopes would give every pooled user the same token chain
+		return nil, fmt.Errorf("pool token cache %T does not keep a token chain per scope", cache)
+	}
+
+	return &ClientPool{
+		config:     config.Config,
+		cache:      cache,
+		httpClient: newHTTPClient(config.Config),
+		maxClients: maxClients,
+		lru:        list.New(),
+		clients:    make(map[string]*list.Element),
+	}, nil
+}
+
+// For returns the client acting on behalf of a user, creating it if necessary
+// The user must have been authenticated (e.g. with AuthenticateWithRefreshToken) before API calls succeed.
+// After the pool is closed, the client returned is already shut down and its calls fail with ErrClientClosed
+func (p *ClientPool) For(userID string) *Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.clients[userID]; ok {
+		p.lru.MoveToFront(elem)
+		return elem.Value.(*poolEntry).client
+	}
+
+	config := p.config
+	config.CacheScope = TokenCacheScope(userID)
+	client := newClient(config, p.cache, p.httpClient)
+	client.sharedHTTPClient = true
+	if p.closed {
+		client.closeCancel()
+		return client
+	}
+
+	p.clients[userID] = p.lru.PushFront(&poolEntry{userID: userID, client: client})
+
+	for p.lru.Len() > p.maxClients {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		entry := oldest.Value.(*poolEntry)
+		delete(p.clients, entry.userID)
+		p.retire(entry.client)
+	}
+
+	return client
+}
+
+// Remove drops a user's client from the pool and shuts it down; cached tokens are left in place
+func (p *ClientPool) Remove(userID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.clients[userID]; ok {
+		p.lru.Remove(elem)
+		delete(p.clients, userID)
+		p.retire(elem.Value.(*poolEntry).client)
+	}
+}
+
+// retire shuts down a client dropped from the pool in the background, so For and Remove don't wait for it
+// The caller must hold p.mu
+func (p *ClientPool) retire(client *Client) {
+	p.retiring.Add(1)
+	go func() {
+		defer p.retiring.Done()
+		if err := client.Close(); err != nil {
+			client.logger.Warn("failed to shut down client dropped from pool", "error", err)
+		}
+	}()
+}
+
+// Shutdown shuts down every client in the pool (see Client.Shutdown), waits for evicted clients to finish shutting
+// down, and closes the shared HTTP client's idle connections. Calling it again has no further effect
+func (p *ClientPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	var clients []*Client
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		clients = append(clients, elem.Value.(*poolEntry).client)
+	}
+	p.lru.Init()
+	p.clients = make(map[string]*list.Element)
+	p.mu.Unlock()
+
+	var errs []error
+	for _, client := range clients {
+		if err := client.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	retired := make(chan struct{})
+	go func() {
+		p.retiring.Wait()
+		close(retired)
+	}()
+	select {
+	case <-retired:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("evicted clients did not stop: %w", ctx.Err()))
+	}
+
+	p.httpClient.CloseIdleConnections()
+
+	return errors.Join(errs...)
+}
+
+// Close shuts the pool down, waiting for every client to stop; see Shutdown
+func (p *ClientPool) Close() error {
+	return p.Shutdown(context.Background())
+}
+
+// Len returns the number of clients currently in the pool
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lru.Len()
+}
@@ -0,0 +1,113 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientPoolShutsDownDroppedClients(t *testing.T) {
+	pool, err := NewClientPool(PoolConfig{
+		Config:     Config{ClientID: "00000000-0000-0000-0000-000000000000"},
+		MaxClients: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := pool.For("a")
+	b := pool.For("b")
+	c := pool.For("c") // evicts a
+	pool.Remove("b")
+	if pool.Len() != 1 {
+		t.Fatalf("Len() = %d; want 1", pool.Len())
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, client := range map[string]*Client{"evicted": a, "removed": b, "pooled": c} {
+		if client.closeCtx.Err() == nil {
+			t.Errorf("%s client not shut down", name)
+		}
+	}
+
+	if late := pool.For("d"); late.closeCtx.Err() == nil {
+		t.Error("client handed out after Close is not shut down")
+	}
+	if pool.Len() != 0 {
+		t.Errorf("Len() after Close = %d; want 0", pool.Len())
+	}
+}
+
+func TestClientPoolRejectsUnscopedCaches(t *testing.T) {
+	fileCache, err := NewFileTokenCacheWithPath(filepath.Join(t.TempDir(), "tokens.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, cache := range map[string]TokenCache{
+		"file":      fileCache,
+		"read-only": NewReadOnlyTokenCache(CachedTokens{RefreshToken: "refresh"}),
+	} {
+		_, err := NewClientPool(PoolConfig{Config: Config{ClientID: "00000000-0000-0000-0000-000000000000", Cache: cache}})
+		if err == nil {
+			t.Errorf("NewClientPool accepted a %s cache", name)
+		}
+	}
+}
+
+func TestClientPoolKeepsUsersApart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body XSTSTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("bad XSTS request: %v", err)
+		}
+		// Each user's XSTS token is derived from the user token it was exchanged for
+		user := strings.TrimSuffix(body.Properties.UserTokens[0], "-user")
+		_ = json.NewEncoder(w).Encode(XSTSTokenResponse{
+			NotAfter:      time.Now().Add(time.Hour),
+			Token:         user + "-xsts",
+			DisplayClaims: XSTSTokenDisplayClaims{Xui: []map[string]interface{}{{"uhs": user + "-hash"}}},
+		})
+	}))
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	for _, user := range []string{"alice", "bob"} {
+		if err := cache.SetUserToken(WithTokenCacheScope(ctx, TokenCacheScope(user)), user+"-user", time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool, err := NewClientPool(PoolConfig{Config: Config{
+		ClientID:  "00000000-0000-0000-0000-000000000000",
+		Cache:     cache,
+		Transport: &rewriteTransport{target: target},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Interleave the users so a shared chain would hand one user's token to the other
+	for _, user := range []string{"alice", "bob", "alice", "bob"} {
+		token, userHash, err := pool.For(user).ensureXSTSToken(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", user, err)
+		}
+		if token != user+"-xsts" || userHash != user+"-hash" {
+			t.Errorf("%s got token %q, hash %q", user, token, userHash)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Presence endpoints
+	presenceBatchEndpoint = "https://userpresence.xboxlive.com/users/batch"
+	presenceStateEndpoint = "https://userpresence.xboxlive.com/users/xuid(%s)/state"
+)
+
+// maxPresenceBatch is the most users the batch presence endpoint accepts in one request
+const maxPresenceBatch = 1100
+
+// PresenceState is a user's online state, as reported in Presence.State and Profile.PresenceState
+// Values the service adds later are kept as-is, so they survive a JSON round trip
+type PresenceState string
+
+// Known presence states
+const (
+	PresenceOnline  PresenceState = "Online"
+	PresenceAway    PresenceState = "Away"
+	PresenceOffline PresenceState = "Offline"
+)
+
+// IsOnline reports whether the state is Online
+func (s PresenceState) IsOnline() bool {
+	return s == PresenceOnline
+}
+
+// IsKnown reports whether the state is one of the known presence states
+func (s PresenceState) IsKnown() bool {
+	switch s {
+	case PresenceOnline, PresenceAway, PresenceOffline:
+		return true
+	}
+	return false
+}
+
+// PresenceVisibility is how the authenticated user appears to other users
+type PresenceVisibility string
+
+const (
+	// PresenceVisible shows the user's real online state and activity
+	PresenceVisible PresenceVisibility = "Online"
+
+	// PresenceAppearOffline shows the user as offline while signed in
+	PresenceAppearOffline PresenceVisibility = "Offline"
+)
+
+// ParsePresenceVisibility parses "online" or "offline" (case-insensitive)
+func ParsePresenceVisibility(s string) (PresenceVisibility, error) {
+	switch {
+	case strings.EqualFold(s, string(PresenceVisible)):
+		return PresenceVisible, nil
+	case strings.EqualFold(s, string(PresenceAppearOffline)):
+		return PresenceAppearOffline, nil
+	}
+	return "", fmt.Errorf("invalid presence visibility %q: expected online or offline", s)
+}
+
+// GetPresence returns the current presence for a set of users by XUID
+func (c *Client) GetPresence(ctx context.Context, xuids []string) ([]*Presence, error) {
+	if len(xuids) == 0 {
+		return []*Presence{}, nil
+	}
+
+	reqBody := PresenceBatchRequest{
+		Users: xuids,
+		Level: "all",
+	}
+
+	var presence []*Presence
+	if err := c.xblRequest(ctx, "POST", presenceBatchEndpoint, ServicePresence, reqBody, &presence); err != nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	return presence, nil
+}
+
+// GetTitlePresence returns the presence of the authenticated user's friends who are currently in a title
+// The presence service filters by title and online state, so offline friends and other titles aren't transferred
+func (c *Client) GetTitlePresence(ctx context.Context, titleID string) ([]*Presence, error) {
+	if titleID == "" {
+		return nil, fmt.Errorf("title ID is required")
+	}
+
+	friends, err := c.GetFriends(ctx)
+	if err != nil {
+		return nil, err
+	}
+	xuids := make([]string, 0, len(friends))
+	for _, friend := range friends {
+		xuids = append(xuids, friend.XUID)
+	}
+
+	result := []*Presence{}
+	for start := 0; start < len(xuids); start += maxPresenceBatch {
+		reqBody := PresenceBatchRequest{
+			Users:      xuids[start:min(start+maxPresenceBatch, len(xuids))],
+			Level:      "all",
+			Titles:     []string{titleID},
+			OnlineOnly: true,
+		}
+
+		var presence []*Presence
+		if err := c.xblRequest(ctx, "POST", presenceBatchEndpoint, ServicePresence, reqBody, &presence); err != nil {
+			return nil, fmt.Errorf("failed to get title presence: %w", err)
+		}
+
+		// Online friends without a record for the title may still be listed
+		for _, p := range presence {
+			if p.InTitle(titleID) {
+				result = append(result, p)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// SetPresenceVisibility makes the authenticated user appear online or offline to other users
+// Not every account type supports appearing offline; the service refusing the change is reported as an error wrapping ErrForbidden or ErrNotFound
+func (c *Client) SetPresenceVisibility(ctx context.Context, mode PresenceVisibility) error {
+	if mode != PresenceVisible && mode != PresenceAppearOffline {
+		return fmt.Errorf("invalid presence visibility %q", mode)
+	}
+
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqBody := PresenceStateRequest{State: string(mode)}
+	return c.mutate(ctx, "set_presence_visibility", claims.XUID, func(ctx context.Context) error {
+		err := c.xblRequest(ctx, "PUT", fmt.Sprintf(presenceStateEndpoint, claims.XUID), ServicePresence, reqBody, nil)
+		if errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("presence visibility is not supported for this account: %w", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set presence visibility: %w", err)
+		}
+		return nil
+	})
+}
+
+// InTitle reports whether the user is currently running a title
+func (p *Presence) InTitle(titleID string) bool {
+	for _, title := range p.ActiveTitles() {
+		if title.ID == titleID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOnline reports whether the user is currently online
+func (p *Presence) IsOnline() bool {
+	return p.State.IsOnline()
+}
+
+// ActiveTitles returns the titles the user is currently running across all devices
+func (p *Presence) ActiveTitles() []*PresenceTitle {
+	var titles []*PresenceTitle
+	for _, device := range p.Devices {
+		titles = append(titles, device.Titles...)
+	}
+	return titles
+}
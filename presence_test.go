@@ -0,0 +1,52 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTitlePresenceFiltersOnServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/users/me/people/social"):
+			_ = json.NewEncoder(w).Encode(SearchResponse{People: []*Profile{{XUID: "1"}, {XUID: "2"}, {XUID: "3"}}})
+		case r.URL.Path == "/users/batch":
+			var body PresenceBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("bad presence request: %v", err)
+			}
+			if !slices.Equal(body.Users, []string{"1", "2", "3"}) || !slices.Equal(body.Titles, []string{"1144039928"}) || !body.OnlineOnly {
+				t.Errorf("presence request = %+v; want the friends, filtered to the title and online users", body)
+			}
+			_ = json.NewEncoder(w).Encode([]*Presence{
+				{XUID: "1", State: PresenceOnline, Devices: []*PresenceDevice{{Titles: []*PresenceTitle{{ID: "1144039928"}}}}},
+				{XUID: "2", State: PresenceOnline, Devices: []*PresenceDevice{{Titles: []*PresenceTitle{{ID: "750323071"}}}}},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.Error(w, "unexpected", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	if err := cache.SetXSTSToken(ctx, "xsts-token", "user-hash", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	playing, err := client.GetTitlePresence(ctx, "1144039928")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playing) != 1 || playing[0].XUID != "1" {
+		t.Errorf("GetTitlePresence returned %d users; want only XUID 1", len(playing))
+	}
+}
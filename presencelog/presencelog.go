@@ -0,0 +1,311 @@
+// Package presencelog records Xbox Live presence snapshots over time and answers
+// playtime questions (time online per day, titles played) from the recorded history
+package presencelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// DefaultInterval is the snapshot interval used when none is configured
+const DefaultInterval = 5 * time.Minute
+
+// PresenceSource is the subset of the xblive client used to fetch presence
+type PresenceSource interface {
+	GetPresence(ctx context.Context, xuids []string) ([]*xblive.Presence, error)
+}
+
+// Store is an interface for persisting presence snapshots
+type Store interface {
+	// Append stores a batch of snapshots
+	Append(ctx context.Context, snapshots []Snapshot) error
+
+	// Query returns the snapshots for a user taken in [from, to), ordered by time
+	Query(ctx context.Context, xuid string, from time.Time, to time.Time) ([]Snapshot, error)
+}
+
+// Snapshot is a single presence observation for a user
+type Snapshot struct {
+	XUID   string    `json:"xuid"`
+	Time   time.Time `json:"time"`
+	Online bool      `json:"online"`
+	Titles []Title   `json:"titles,omitempty"`
+}
+
+// Title identifies a title a user was running when a snapshot was taken
+type Title struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Config contains configuration for a Recorder
+type Config struct {
+	// Source is used to fetch presence, normally an *xblive.Client (required)
+	Source PresenceSource
+
+	// Store persists snapshots (required)
+	Store Store
+
+	// XUIDs is the set of users to record (required)
+	XUIDs []string
+
+	// Interval is the time between snapshots (optional, defaults to DefaultInterval)
+	Interval time.Duration
+
+	// OnError is called when a snapshot fails; the recorder tries again on the next tick (optional)
+	OnError func(err error)
+}
+
+// Recorder periodically snapshots presence for a set of users
+type Recorder struct {
+	source   PresenceSource
+	store    Store
+	xuids    []string
+	interval time.Duration
+	onError  func(error)
+}
+
+// New creates a new presence recorder
+func New(config Config) (*Recorder, error) {
+	if config.Source == nil {
+		return nil, fmt.Errorf("presence source is required")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if len(config.XUIDs) == 0 {
+		return nil, fmt.Errorf("at least one XUID is required")
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &Recorder{
+		source:   config.Source,
+		store:    config.Store,
+		xuids:    config.XUIDs,
+		interval: interval,
+		onError:  config.OnError,
+	}, nil
+}
+
+// Run takes a snapshot every interval until the context is cancelled, and returns the context's error
+// A failed snapshot (e.g. a 429 or a network error) is reported to OnError and skipped, so a long-running recorder
+// only loses the samples it couldn't take
+func (r *Recorder) Run(ctx context.Context) error {
+	r.snapshot(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			r.snapshot(ctx)
+		}
+	}
+}
+
+// snapshot takes a snapshot for Run, reporting a failure to OnError
+func (r *Recorder) snapshot(ctx context.Context) {
+	if err := r.Snapshot(ctx); err != nil && ctx.Err() == nil && r.onError != nil {
+		r.onError(err)
+	}
+}
+
+// Snapshot fetches presence for all recorded users and stores it
+func (r *Recorder) Snapshot(ctx context.Context) error {
+	presence, err := r.source.GetPresence(ctx, r.xuids)
+	if err != nil {
+		return fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	now := time.Now()
+	snapshots := make([]Snapshot, 0, len(presence))
+	for _, p := range presence {
+		snapshot := Snapshot{
+			XUID:   p.XUID,
+			Time:   now,
+			Online: p.IsOnline(),
+		}
+		for _, title := range p.ActiveTitles() {
+			snapshot.Titles = append(snapshot.Titles, Title{ID: title.ID, Name: title.Name})
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := r.store.Append(ctx, snapshots); err != nil {
+		return fmt.Errorf("failed to store snapshots: %w", err)
+	}
+
+	return nil
+}
+
+// TimeOnlinePerDay returns how long a user was online on each day in [from, to)
+// Keys are dates formatted as YYYY-MM-DD in the location of from
+func (r *Recorder) TimeOnlinePerDay(ctx context.Context, xuid string, from time.Time, to time.Time) (map[string]time.Duration, error) {
+	snapshots, err := r.store.Query(ctx, xuid, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]time.Duration)
+	r.walk(snapshots, func(s Snapshot, d time.Duration) {
+		if s.Online {
+			result[s.Time.In(from.Location()).Format("2006-01-02")] += d
+		}
+	})
+
+	return result, nil
+}
+
+// TitlesPlayed returns how long a user spent in each title in [from, to), keyed by title ID
+func (r *Recorder) TitlesPlayed(ctx context.Context, xuid string, from time.Time, to time.Time) (map[string]*TitleTime, error) {
+	snapshots, err := r.store.Query(ctx, xuid, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*TitleTime)
+	r.walk(snapshots, func(s Snapshot, d time.Duration) {
+		for _, title := range s.Titles {
+			tt, ok := result[title.ID]
+			if !ok {
+				tt = &TitleTime{Title: title}
+				result[title.ID] = tt
+			}
+			tt.Duration += d
+		}
+	})
+
+	return result, nil
+}
+
+// TitleTime is the accumulated time a user spent in a title
+type TitleTime struct {
+	Title    Title
+	Duration time.Duration
+}
+
+// walk calls fn with each snapshot and the time it is considered to cover
+// A snapshot covers the time until the next one, capped at twice the interval so gaps in recording aren't counted
+func (r *Recorder) walk(snapshots []Snapshot, fn func(Snapshot, time.Duration)) {
+	maxGap := 2 * r.interval
+	for i, s := range snapshots {
+		d := r.interval
+		if i+1 < len(snapshots) {
+			d = snapshots[i+1].Time.Sub(s.Time)
+		}
+		if d > maxGap {
+			d = maxGap
+		}
+		fn(s, d)
+	}
+}
+
+// MemoryStore is an in-memory implementation of Store
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[string][]Snapshot
+}
+
+// NewMemoryStore creates a new in-memory snapshot store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		snapshots: make(map[string][]Snapshot),
+	}
+}
+
+// Append stores a batch of snapshots
+func (m *MemoryStore) Append(ctx context.Context, snapshots []Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range snapshots {
+		m.snapshots[s.XUID] = append(m.snapshots[s.XUID], s)
+	}
+	return nil
+}
+
+// Query returns the snapshots for a user taken in [from, to), ordered by time
+func (m *MemoryStore) Query(ctx context.Context, xuid string, from time.Time, to time.Time) ([]Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []Snapshot
+	for _, s := range m.snapshots[xuid] {
+		if !s.Time.Before(from) && s.Time.Before(to) {
+			result = append(result, s)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+
+	return result, nil
+}
+
+// snapshotKeyLayout formats snapshot times in keys so that key order is time order
+const snapshotKeyLayout = "20060102T150405.000000000Z"
+
+// SharedStore is a Store kept in an xblive.StateStore, so presence history can share one persistence backend with the watchers
+// Each snapshot is a key under "presencelog/<xuid>/"
+type SharedStore struct {
+	state xblive.StateStore
+}
+
+// NewSharedStore creates a snapshot store backed by a state store
+func NewSharedStore(state xblive.StateStore) *SharedStore {
+	return &SharedStore{state: state}
+}
+
+// snapshotPrefix returns the key prefix of a user's snapshots
+func snapshotPrefix(xuid string) string {
+	return "presencelog/" + xuid + "/"
+}
+
+// Append stores a batch of snapshots
+func (s *SharedStore) Append(ctx context.Context, snapshots []Snapshot) error {
+	for _, snapshot := range snapshots {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		key := snapshotPrefix(snapshot.XUID) + snapshot.Time.UTC().Format(snapshotKeyLayout)
+		if err := s.state.Put(ctx, key, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns the snapshots for a user taken in [from, to), ordered by time
+func (s *SharedStore) Query(ctx context.Context, xuid string, from time.Time, to time.Time) ([]Snapshot, error) {
+	entries, err := s.state.List(ctx, snapshotPrefix(xuid))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Snapshot
+	for _, e := range entries {
+		var snapshot Snapshot
+		if err := json.Unmarshal(e.Value, &snapshot); err != nil {
+			return nil, fmt.Errorf("invalid snapshot %s: %w", e.Key, err)
+		}
+		if !snapshot.Time.Before(from) && snapshot.Time.Before(to) {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package presencelog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+// flakySource fails its first calls, then cancels the recorder once it has served enough snapshots
+type flakySource struct {
+	mu       sync.Mutex
+	failures int
+	serves   int
+	cancel   context.CancelFunc
+}
+
+func (s *flakySource) GetPresence(ctx context.Context, xuids []string) ([]*xblive.Presence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return nil, errors.New("429 too many requests")
+	}
+	s.serves--
+	if s.serves == 0 {
+		s.cancel()
+	}
+	return []*xblive.Presence{{XUID: xuids[0], State: xblive.PresenceOnline}}, nil
+}
+
+func TestRunSurvivesFailedSnapshots(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var failures int
+	store := NewMemoryStore()
+	recorder, err := New(Config{
+		Source:   &flakySource{failures: 3, serves: 2, cancel: cancel},
+		Store:    store,
+		XUIDs:    []string{"2533274792093503"},
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures++
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v; want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failures != 3 {
+		t.Errorf("OnError called %d times; want 3", failures)
+	}
+	snapshots, err := store.Query(context.Background(), "2533274792093503", time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("%d snapshots stored; want 2", len(snapshots))
+	}
+}
@@ -0,0 +1,217 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PresenceStatus is the part of a user's presence a PresenceWatcher reports changes to
+type PresenceStatus struct {
+	State     PresenceState `json:"state"`
+	TitleID   string        `json:"titleId,omitempty"`
+	TitleName string        `json:"titleName,omitempty"`
+	Since     time.Time     `json:"since"`
+}
+
+// same reports whether two statuses describe the same state and title, ignoring when they began
+func (s PresenceStatus) same(other PresenceStatus) bool {
+	return s.State == other.State && s.TitleID == other.TitleID
+}
+
+// PresenceChange is emitted when a watched user's presence status changes and stays changed for the debounce period
+type PresenceChange struct {
+	XUID string         `json:"xuid"`
+	From PresenceStatus `json:"from"`
+	To   PresenceStatus `json:"to"`
+}
+
+// presenceStatePrefix namespaces the PresenceWatcher's reported statuses in a StateStore
+const presenceStatePrefix = "presence/"
+
+// PresenceWatcherConfig configures a PresenceWatcher
+type PresenceWatcherConfig struct {
+	// XUIDs is the set of users to watch (required)
+	XUIDs []string
+
+	// OnChange is called for each reported change (required)
+	OnChange func(PresenceChange)
+
+	// Interval is the time between polls (optional, defaults to DefaultWatchInterval)
+	Interval time.Duration
+
+	// Debounce is how long a new status must hold before it is reported (optional, defaults to reporting immediately)
+	// Flaps that revert within it, such as a console briefly dropping offline, are ignored
+	// Statuses are only observed on polls, so it is effectively rounded up to a multiple of Interval
+	Debounce time.Duration
+
+	// Store persists the last reported status of each user across restarts (optional, defaults to memory only)
+	// A restarted watcher then neither re-fires changes it already reported nor misses ones that happened while it was down
+	Store StateStore
+
+	// OnError is called when polling fails (optional)
+	// Failures don't stop the watcher; users are polled again on the next interval
+	OnError func(err error)
+}
+
+// PresenceWatcher polls watched users' presence and emits an event when their online state or foreground title changes
+// Without a stored status, a user's first observed status is taken as the baseline and not reported
+type PresenceWatcher struct {
+	client   *Client
+	xuids    []string
+	onChange func(PresenceChange)
+	onError  func(error)
+	interval time.Duration
+	debounce time.Duration
+	store    StateStore
+
+	// reported is the last reported status per user; pending is a different status seen since, awaiting the debounce period
+	reported map[string]PresenceStatus
+	pending  map[string]PresenceStatus
+}
+
+// NewPresenceWatcher creates a watcher for presence changes
+func (c *Client) NewPresenceWatcher(config PresenceWatcherConfig) (*PresenceWatcher, error) {
+	if len(config.XUIDs) == 0 {
+		return nil, fmt.Errorf("at least one XUID is required")
+	}
+	if config.OnChange == nil {
+		return nil, fmt.Errorf("change callback is required")
+	}
+	if config.Debounce < 0 {
+		return nil, fmt.Errorf("debounce must not be negative")
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	if config.Store != nil {
+		c.addFlusher(config.Store)
+	}
+
+	return &PresenceWatcher{
+		client:   c,
+		xuids:    config.XUIDs,
+		onChange: config.OnChange,
+		onError:  config.OnError,
+		interval: interval,
+		debounce: config.Debounce,
+		store:    config.Store,
+		reported: make(map[string]PresenceStatus),
+		pending:  make(map[string]PresenceStatus),
+	}, nil
+}
+
+// Run polls until the context is cancelled or the client is shut down
+func (w *PresenceWatcher) Run(ctx context.Context) error {
+	ctx, done, err := w.client.track(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	for {
+		w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return w.client.stopped(ctx)
+		case <-w.client.clock.After(w.interval):
+		}
+	}
+}
+
+// Poll checks every watched user once and emits events for changes that have outlasted the debounce period
+// Run calls it on each interval; call it directly to drive the watcher from your own scheduler
+func (w *PresenceWatcher) Poll(ctx context.Context) {
+	if err := w.poll(ctx); err != nil {
+		w.client.logger.Warn("presence poll failed", "error", err)
+		if w.onError != nil {
+			w.onError(err)
+		}
+	}
+}
+
+// poll fetches presence for all watched users and applies it
+func (w *PresenceWatcher) poll(ctx context.Context) error {
+	presence, err := w.client.GetPresence(ctx, w.xuids)
+	if err != nil {
+		return err
+	}
+
+	now := w.client.clock.Now()
+	for _, p := range presence {
+		if err := w.observe(ctx, p.XUID, presenceStatus(p, now), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observe applies one user's current status, reporting it once it has held for the debounce period
+func (w *PresenceWatcher) observe(ctx context.Context, xuid string, current PresenceStatus, now time.Time) error {
+	reported, ok := w.reported[xuid]
+	if !ok && w.store != nil {
+		found, err := getState(ctx, w.store, presenceStatePrefix+xuid, &reported)
+		if err != nil {
+			return fmt.Errorf("failed to load presence state for %s: %w", xuid, err)
+		}
+		if found {
+			w.reported[xuid] = reported
+			ok = true
+		}
+	}
+	if !ok {
+		// First sighting: take it as the baseline
+		return w.report(ctx, xuid, current)
+	}
+
+	if current.same(reported) {
+		delete(w.pending, xuid)
+		return nil
+	}
+
+	pending, waiting := w.pending[xuid]
+	if !waiting || !current.same(pending) {
+		pending = current
+		w.pending[xuid] = pending
+	}
+	if now.Sub(pending.Since) < w.debounce {
+		return nil
+	}
+
+	delete(w.pending, xuid)
+	if err := w.report(ctx, xuid, pending); err != nil {
+		return err
+	}
+	w.onChange(PresenceChange{XUID: xuid, From: reported, To: pending})
+	return nil
+}
+
+// report records a user's status as reported
+func (w *PresenceWatcher) report(ctx context.Context, xuid string, status PresenceStatus) error {
+	w.reported[xuid] = status
+	if w.store == nil {
+		return nil
+	}
+	if err := putState(ctx, w.store, presenceStatePrefix+xuid, status); err != nil {
+		return fmt.Errorf("failed to save presence state for %s: %w", xuid, err)
+	}
+	return nil
+}
+
+// presenceStatus reduces a presence to its state and foreground title, first seen at now
+func presenceStatus(p *Presence, now time.Time) PresenceStatus {
+	status := PresenceStatus{State: p.State, Since: now}
+	for _, title := range p.ActiveTitles() {
+		if title.Placement == "Background" {
+			continue
+		}
+		status.TitleID = title.ID
+		status.TitleName = title.Name
+		break
+	}
+	return status
+}
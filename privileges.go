@@ -0,0 +1,84 @@
+package xblive
+
+import (
+	"context"
+)
+
+// Privilege is an Xbox Live user privilege, as listed in the XSTS claims
+type Privilege int
+
+// Known privileges
+const (
+	PrivilegeBroadcast                Privilege = 190
+	PrivilegeViewFriendsList          Privilege = 197
+	PrivilegeGameDVR                  Privilege = 198
+	PrivilegeShareKinectContent       Privilege = 199
+	PrivilegeMultiplayerParties       Privilege = 203
+	PrivilegeCommunicationVoiceInGame Privilege = 205
+	PrivilegeCommunicationVoiceSkype  Privilege = 206
+	PrivilegeCloudGamingManageSession Privilege = 207
+	PrivilegeCloudGamingJoinSession   Privilege = 208
+	PrivilegeCloudSavedGames          Privilege = 209
+	PrivilegeShareContent             Privilege = 211
+	PrivilegePremiumContent           Privilege = 214
+	PrivilegeSubscriptionContent      Privilege = 219
+	PrivilegeSocialNetworkSharing     Privilege = 220
+	PrivilegePremiumVideo             Privilege = 224
+	PrivilegeVideoCommunications      Privilege = 235
+	PrivilegePurchaseContent          Privilege = 245
+	PrivilegeUserCreatedContent       Privilege = 247
+	PrivilegeProfileViewing           Privilege = 249
+	PrivilegeCommunications           Privilege = 252
+	PrivilegeMultiplayerSessions      Privilege = 254
+	PrivilegeAddFriend                Privilege = 255
+)
+
+// PrivacySetting is who a user's Xbox privacy setting (e.g. who can see their friends list or presence) allows
+// Values the service adds later are kept as-is, so they survive a JSON round trip
+type PrivacySetting string
+
+// Known privacy settings
+const (
+	PrivacyEveryone                    PrivacySetting = "Everyone"
+	PrivacyFriends                     PrivacySetting = "PeopleOnMyList"
+	PrivacyFriendCategoryShareIdentity PrivacySetting = "FriendCategoryShareIdentity"
+	PrivacyBlocked                     PrivacySetting = "Blocked"
+)
+
+// AllowsEveryone reports whether the setting allows anyone
+func (p PrivacySetting) AllowsEveryone() bool {
+	return p == PrivacyEveryone
+}
+
+// AllowsFriends reports whether the setting allows at least the user's friends
+func (p PrivacySetting) AllowsFriends() bool {
+	return p == PrivacyEveryone || p == PrivacyFriends || p == PrivacyFriendCategoryShareIdentity
+}
+
+// IsKnown reports whether the setting is one of the known privacy settings
+func (p PrivacySetting) IsKnown() bool {
+	switch p {
+	case PrivacyEveryone, PrivacyFriends, PrivacyFriendCategoryShareIdentity, PrivacyBlocked:
+		return true
+	}
+	return false
+}
+
+// Has reports whether the claims grant a privilege
+func (c *XSTSClaims) Has(privilege Privilege) bool {
+	for _, p := range c.Privileges {
+		if p == privilege {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrivilege reports whether the authenticated user has an Xbox Live privilege (e.g. multiplayer, communications)
+func (c *Client) HasPrivilege(ctx context.Context, privilege Privilege) (bool, error) {
+	claims, err := c.Identity(ctx)
+	if err != nil {
+		return false, err
+	}
+	return claims.Has(privilege), nil
+}
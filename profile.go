@@ -0,0 +1,167 @@
+package xblive
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// TuningProfile names a preset of timeouts, retries, concurrency, and rate limits suited to one kind of workload
+type TuningProfile string
+
+const (
+	// ProfileInteractive suits a user waiting on the result (CLIs, chat bots): short timeouts, and at most one quick
+	// retry, so failures surface fast instead of hanging
+	ProfileInteractive TuningProfile = "interactive"
+
+	// ProfileBulk suits unattended jobs such as imports and crawls: long timeouts, patient retries that honor
+	// long Retry-After delays, and gentle crawl pacing, so a job finishes rather than failing on a throttled page
+	ProfileBulk TuningProfile = "bulk"
+
+	// ProfileServer suits long-running services making calls on behalf of many requests: moderate timeouts and
+	// retries, higher crawl concurrency, and a per-service quota so a runaway caller can't exhaust the account's rate limits
+	ProfileServer TuningProfile = "server"
+)
+
+// Tuning is the set of knobs a TuningProfile sets
+type Tuning struct {
+	// Timeout bounds each HTTP request, including reading the response (zero means no timeout)
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a read that fails temporarily (rate limiting or a server-side failure) is
+	// retried. Writes are never retried, since they may have taken effect
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry when the service gives no Retry-After; it doubles on each retry
+	RetryBackoff time.Duration
+
+	// MaxRetryDelay caps the wait before a retry; a call asked to wait longer fails instead
+	MaxRetryDelay time.Duration
+
+	// Crawl is the concurrency, pacing, and retry settings returned by Client.CrawlOptions
+	Crawl CrawlOptions
+
+	// QuotaLimit caps the calls to each service per DefaultQuotaWindow when Config.Quota is nil (zero means unlimited)
+	QuotaLimit int
+}
+
+// DefaultTuning is the tuning used when no profile is configured: a 30 second timeout and no retries
+var DefaultTuning = Tuning{
+	Timeout: 30 * time.Second,
+	Crawl: CrawlOptions{
+		Concurrency: DefaultCrawlConcurrency,
+		Pacing:      DefaultCrawlPacing,
+		MaxRetries:  DefaultCrawlRetries,
+	},
+}
+
+// tuningProfiles holds the settings of each named profile
+var tuningProfiles = map[TuningProfile]Tuning{
+	ProfileInteractive: {
+		Timeout:       10 * time.Second,
+		MaxRetries:    1,
+		RetryBackoff:  500 * time.Millisecond,
+		MaxRetryDelay: 2 * time.Second,
+		Crawl: CrawlOptions{
+			Concurrency: 2,
+			Pacing:      250 * time.Millisecond,
+			MaxRetries:  1,
+		},
+	},
+	ProfileBulk: {
+		Timeout:       60 * time.Second,
+		MaxRetries:    5,
+		RetryBackoff:  2 * time.Second,
+		MaxRetryDelay: 2 * time.Minute,
+		Crawl: CrawlOptions{
+			Concurrency: 4,
+			Pacing:      time.Second,
+			MaxRetries:  5,
+		},
+	},
+	ProfileServer: {
+		Timeout:       15 * time.Second,
+		MaxRetries:    2,
+		RetryBackoff:  time.Second,
+		MaxRetryDelay: 10 * time.Second,
+		Crawl: CrawlOptions{
+			Concurrency: 8,
+			Pacing:      500 * time.Millisecond,
+			MaxRetries:  2,
+		},
+		QuotaLimit: 10000,
+	},
+}
+
+// Tuning returns the settings of a profile; ok is false if the profile is unknown
+func (p TuningProfile) Tuning() (t Tuning, ok bool) {
+	t, ok = tuningProfiles[p]
+	return t, ok
+}
+
+// tuning returns the tuning a config selects: Config.Tuning, else Config.Profile, else DefaultTuning
+func (c Config) tuning() Tuning {
+	if c.Tuning != nil {
+		return *c.Tuning
+	}
+	if t, ok := c.Profile.Tuning(); ok {
+		return t
+	}
+	return DefaultTuning
+}
+
+// quota returns the config's quota, or a new one from its tuning's QuotaLimit
+// A ClientPool calls it once so the pool's clients share the quota
+func (c Config) quota() *Quota {
+	if c.Quota != nil {
+		return c.Quota
+	}
+	if limit := c.tuning().QuotaLimit; limit > 0 {
+		return &Quota{DefaultLimit: limit}
+	}
+	return nil
+}
+
+// validate checks the tuning durations and counts
+func (t *Tuning) validate() []error {
+	var errs []error
+	if t.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("tuning timeout must not be negative"))
+	}
+	if t.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("tuning max retries must not be negative"))
+	}
+	if t.RetryBackoff < 0 || t.MaxRetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("tuning retry delays must not be negative"))
+	}
+	if t.QuotaLimit < 0 {
+		errs = append(errs, fmt.Errorf("tuning quota limit must not be negative"))
+	}
+	return errs
+}
+
+// CrawlOptions returns crawl settings matching the client's tuning, for Crawl and CrawlAll
+// Set PageSize, Cursors, and OnProgress on the result as needed
+func (c *Client) CrawlOptions() CrawlOptions {
+	return c.tuning.Crawl
+}
+
+// retryDelay reports whether a failed request should be retried under the tuning, and after how long
+// Only reads whose body can be replayed are retried, and only after temporary failures
+func (t Tuning) retryDelay(err error, mutation bool, attempt int, body io.Reader) (time.Duration, bool) {
+	if err == nil || mutation || attempt >= t.MaxRetries || !IsTemporary(err) {
+		return 0, false
+	}
+	if _, ok := body.(io.Seeker); body != nil && !ok {
+		return 0, false
+	}
+
+	delay := RetryAfter(err)
+	if delay == 0 {
+		delay = t.RetryBackoff << attempt
+	}
+	if t.MaxRetryDelay > 0 && delay > t.MaxRetryDelay {
+		return 0, false
+	}
+	return delay, true
+}
@@ -0,0 +1,107 @@
+package xblive
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AccountTier is a user's Xbox subscription tier, as reported in ProfileDetail.AccountTier
+// Values the service adds later are kept as-is, so they survive a JSON round trip
+type AccountTier string
+
+// Known account tiers
+const (
+	AccountTierSilver           AccountTier = "Silver"
+	AccountTierGold             AccountTier = "Gold"
+	AccountTierGamePassUltimate AccountTier = "GamePassUltimate"
+)
+
+// IsPaid reports whether the tier is a paid subscription (Gold or Game Pass Ultimate)
+func (t AccountTier) IsPaid() bool {
+	return t == AccountTierGold || t == AccountTierGamePassUltimate
+}
+
+// IsKnown reports whether the tier is one of the known account tiers
+func (t AccountTier) IsKnown() bool {
+	switch t {
+	case AccountTierSilver, AccountTierGold, AccountTierGamePassUltimate:
+		return true
+	}
+	return false
+}
+
+// Reputation is a user's Xbox One reputation, as reported in Profile.XboxOneRep
+// Values the service adds later are kept as-is, so they survive a JSON round trip
+type Reputation string
+
+// Known reputations
+const (
+	ReputationGoodPlayer Reputation = "GoodPlayer"
+	ReputationNeedsWork  Reputation = "NeedsWork"
+	ReputationAvoidMe    Reputation = "AvoidMe"
+)
+
+// IsGood reports whether the user has a good reputation
+func (r Reputation) IsGood() bool {
+	return r == ReputationGoodPlayer
+}
+
+// IsFlagged reports whether other players' feedback has lowered the user's reputation (NeedsWork or AvoidMe)
+func (r Reputation) IsFlagged() bool {
+	return r == ReputationNeedsWork || r == ReputationAvoidMe
+}
+
+// IsKnown reports whether the reputation is one of the known reputations
+func (r Reputation) IsKnown() bool {
+	switch r {
+	case ReputationGoodPlayer, ReputationNeedsWork, ReputationAvoidMe:
+		return true
+	}
+	return false
+}
+
+// Watermark is a profile badge recognizing a user's participation in an Xbox program, such as a launch team
+type Watermark string
+
+// Known watermarks
+const (
+	WatermarkXboxOriginalTeam  Watermark = "XboxOriginalTeam"
+	WatermarkLaunchTeam        Watermark = "LaunchTeam"
+	WatermarkNxeLaunchTeam     Watermark = "NxeLaunchTeam"
+	WatermarkKinectLaunchTeam  Watermark = "KinectLaunchTeam"
+	WatermarkXboxOneLaunchTeam Watermark = "XboxOneLaunchTeam"
+	WatermarkXboxOneTeam       Watermark = "XboxOneTeam"
+)
+
+// TenureYears returns the number of years the user has had an Xbox Live account, or 0 if unknown
+func (d *ProfileDetail) TenureYears() int {
+	years, err := strconv.Atoi(strings.TrimSpace(d.Tenure))
+	if err != nil || years < 0 {
+		return 0
+	}
+	return years
+}
+
+// Badges returns the user's watermarks as typed values
+// Unrecognized watermarks are returned as-is rather than dropped
+func (d *ProfileDetail) Badges() []Watermark {
+	var badges []Watermark
+	for _, raw := range d.Watermarks {
+		for _, w := range strings.Split(raw, ",") {
+			if w = strings.TrimSpace(w); w != "" {
+				badges = append(badges, Watermark(w))
+			}
+		}
+	}
+	return badges
+}
+
+// HasBadge reports whether the user has a watermark
+func (d *ProfileDetail) HasBadge(watermark Watermark) bool {
+	for _, w := range d.Badges() {
+		if strings.EqualFold(string(w), string(watermark)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProfileField is a profile setting that can be requested by XUID
+type ProfileField string
+
+// Profile fields
+const (
+	ProfileFieldGamertag             ProfileField = "Gamertag"
+	ProfileFieldModernGamertag       ProfileField = "ModernGamertag"
+	ProfileFieldModernGamertagSuffix ProfileField = "ModernGamertagSuffix"
+	ProfileFieldUniqueModernGamertag ProfileField = "UniqueModernGamertag"
+	ProfileFieldGamerscore           ProfileField = "Gamerscore"
+	ProfileFieldGameDisplayPicRaw    ProfileField = "GameDisplayPicRaw"
+	ProfileFieldGameDisplayName      ProfileField = "GameDisplayName"
+	ProfileFieldRealName             ProfileField = "RealName"
+	ProfileFieldXboxOneRep           ProfileField = "XboxOneRep"
+	ProfileFieldAccountTier          ProfileField = "AccountTier"
+	ProfileFieldBio                  ProfileField = "Bio"
+	ProfileFieldLocation             ProfileField = "Location"
+	ProfileFieldTenureLevel          ProfileField = "TenureLevel"
+	ProfileFieldWatermarks           ProfileField = "Watermarks"
+)
+
+// DefaultProfileFields are the fields requested when none are specified
+var DefaultProfileFields = []ProfileField{
+	ProfileFieldGamertag,
+	ProfileFieldGamerscore,
+	ProfileFieldGameDisplayPicRaw,
+}
+
+// GetProfileFields returns selected profile fields for users by XUID, in batches
+// Requesting only the fields you need keeps responses small for bulk enrichment jobs; fields that weren't requested
+// are left empty. nil fields selects DefaultProfileFields. XUIDs that don't resolve are omitted
+func (c *Client) GetProfileFields(ctx context.Context, xuids []string, fields []ProfileField) ([]*Profile, error) {
+	if fields == nil {
+		fields = DefaultProfileFields
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one profile field is required")
+	}
+
+	settings := make([]string, len(fields))
+	for i, f := range fields {
+		settings[i] = string(f)
+	}
+
+	users, err := c.getProfileSettings(ctx, xuids, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile fields: %w", err)
+	}
+
+	profiles := make([]*Profile, 0, len(users))
+	for _, user := range users {
+		profiles = append(profiles, profileFromSettings(user))
+	}
+	return profiles, nil
+}
+
+// getProfileSettings requests profile settings for users, in batches of maxProfileBatchSize
+func (c *Client) getProfileSettings(ctx context.Context, xuids []string, settings []string) ([]*ProfileUser, error) {
+	var users []*ProfileUser
+	for start := 0; start < len(xuids); start += maxProfileBatchSize {
+		end := min(start+maxProfileBatchSize, len(xuids))
+
+		reqBody := ProfileSettingsRequest{
+			UserIDs:  xuids[start:end],
+			Settings: settings,
+		}
+
+		var resp ProfileSettingsResponse
+		if err := c.xblRequest(ctx, "POST", profileSettingsBatchEndpoint, ServiceProfile, reqBody, &resp); err != nil {
+			return nil, err
+		}
+		users = append(users, resp.ProfileUsers...)
+	}
+	return users, nil
+}
+
+// profileFromSettings builds a profile from the settings returned for a user
+func profileFromSettings(user *ProfileUser) *Profile {
+	p := &Profile{XUID: user.ID}
+	var detail ProfileDetail
+	hasDetail := false
+
+	for _, s := range user.Settings {
+		switch ProfileField(s.ID) {
+		case ProfileFieldGamertag:
+			p.Gamertag = s.Value
+		case ProfileFieldModernGamertag:
+			p.ModernGamertag = s.Value
+		case ProfileFieldModernGamertagSuffix:
+			p.ModernGamertagSuffix = s.Value
+		case ProfileFieldUniqueModernGamertag:
+			p.UniqueModernGamertag = s.Value
+		case ProfileFieldGamerscore:
+			p.GamerScore = s.Value
+		case ProfileFieldGameDisplayPicRaw:
+			p.DisplayPicRaw = s.Value
+		case ProfileFieldGameDisplayName:
+			p.DisplayName = s.Value
+		case ProfileFieldRealName:
+			p.RealName = s.Value
+		case ProfileFieldXboxOneRep:
+			p.XboxOneRep = Reputation(s.Value)
+		case ProfileFieldAccountTier:
+			detail.AccountTier = AccountTier(s.Value)
+			hasDetail = true
+		case ProfileFieldBio:
+			detail.Bio = s.Value
+			hasDetail = true
+		case ProfileFieldLocation:
+			detail.Location = s.Value
+			hasDetail = true
+		case ProfileFieldTenureLevel:
+			detail.Tenure = s.Value
+			hasDetail = true
+		case ProfileFieldWatermarks:
+			if s.Value != "" {
+				detail.Watermarks = []string{s.Value}
+			}
+			hasDetail = true
+		}
+	}
+
+	if hasDetail {
+		p.Detail = &detail
+	}
+	return p
+}
@@ -0,0 +1,41 @@
+package xblive
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyTransport is an http.RoundTripper that sends every request through a CORS proxy
+// The proxy receives the original URL appended to its own (e.g. https://proxy.example/https://peoplehub.xboxlive.com/...),
+// the convention used by cors-anywhere style proxies. Browser (js/wasm) builds need one because Xbox Live doesn't send CORS headers
+type ProxyTransport struct {
+	// ProxyURL is the proxy's base URL (required)
+	ProxyURL string
+
+	// Base performs the proxied requests (optional, defaults to http.DefaultTransport, which uses fetch on js/wasm)
+	Base http.RoundTripper
+}
+
+// RoundTrip rewrites the request to go through the proxy and sends it
+func (t *ProxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.ProxyURL == "" {
+		return nil, fmt.Errorf("proxy URL is required")
+	}
+
+	proxied, err := url.Parse(strings.TrimSuffix(t.ProxyURL, "/") + "/" + req.URL.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	out := req.Clone(req.Context())
+	out.URL = proxied
+	out.Host = proxied.Host
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(out)
+}
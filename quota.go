@@ -0,0 +1,114 @@
+package xblive
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a call would exceed a caller-defined quota; the request is not sent
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// DefaultQuotaWindow is the quota window used when none is configured
+const DefaultQuotaWindow = time.Hour
+
+// Quota counts calls per service over a sliding window and enforces caller-defined caps
+// Calls are counted per service rather than per endpoint: Xbox Live applies its rate limits to each service, and
+// endpoints with XUIDs or gamertags in their paths would otherwise be counted separately for every user
+// A Quota may be shared by several clients (e.g. in a ClientPool) to cap their combined usage
+// It is safe for concurrent use by multiple goroutines
+type Quota struct {
+	// Window is the sliding window calls are counted over (optional, defaults to DefaultQuotaWindow)
+	Window time.Duration
+
+	// Limits caps the calls to each service per window (optional); a limit of zero blocks the service
+	Limits map[Service]int
+
+	// DefaultLimit caps the calls per window to services without an entry in Limits (optional, zero means unlimited)
+	DefaultLimit int
+
+	mu    sync.Mutex
+	clock Clock
+	calls map[Service][]time.Time
+}
+
+// SetClock sets the clock calls are timed with; a client with a custom Clock sets it on its quota
+func (q *Quota) SetClock(clock Clock) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.clock = clock
+}
+
+// now returns the current time of the quota's clock
+// The caller must hold q.mu
+func (q *Quota) now() time.Time {
+	if q.clock == nil {
+		return SystemClock.Now()
+	}
+	return q.clock.Now()
+}
+
+// window returns the configured window or the default
+func (q *Quota) window() time.Duration {
+	if q.Window > 0 {
+		return q.Window
+	}
+	return DefaultQuotaWindow
+}
+
+// limit returns the cap for a service, and false if it is unlimited
+func (q *Quota) limit(service Service) (int, bool) {
+	if limit, ok := q.Limits[service]; ok {
+		return limit, true
+	}
+	return q.DefaultLimit, q.DefaultLimit > 0
+}
+
+// prune drops calls older than the window
+// The caller must hold q.mu
+func (q *Quota) prune(service Service, now time.Time) []time.Time {
+	cutoff := now.Add(-q.window())
+	calls := q.calls[service]
+	i := 0
+	for i < len(calls) && !calls[i].After(cutoff) {
+		i++
+	}
+	calls = calls[i:]
+	q.calls[service] = calls
+	return calls
+}
+
+// take records a call to a service, or returns ErrQuotaExceeded if the service's cap is reached
+func (q *Quota) take(service Service) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if q.calls == nil {
+		q.calls = make(map[Service][]time.Time)
+	}
+
+	calls := q.prune(service, now)
+	if limit, ok := q.limit(service); ok && len(calls) >= limit {
+		return fmt.Errorf("%w: %d calls to %s in the last %s", ErrQuotaExceeded, len(calls), service, q.window())
+	}
+
+	q.calls[service] = append(calls, now)
+	return nil
+}
+
+// Usage returns the number of calls made to each service within the current window
+func (q *Quota) Usage() map[Service]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := make(map[Service]int, len(q.calls))
+	now := q.now()
+	for service := range q.calls {
+		if n := len(q.prune(service, now)); n > 0 {
+			usage[service] = n
+		}
+	}
+	return usage
+}
@@ -0,0 +1,71 @@
+package xblive
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuotaLimits(t *testing.T) {
+	q := &Quota{
+		Limits:       map[Service]int{ServiceProfile: 2, ServiceSocial: 0},
+		DefaultLimit: 0,
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := q.take(ServiceProfile); err != nil {
+			t.Fatalf("call %d to profile: %v", i+1, err)
+		}
+	}
+	if err := q.take(ServiceProfile); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("third call to profile = %v; want ErrQuotaExceeded", err)
+	}
+
+	// A zero limit blocks the service outright
+	if err := q.take(ServiceSocial); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("call to blocked social service = %v; want ErrQuotaExceeded", err)
+	}
+
+	// A zero default limit leaves services without an entry unlimited
+	for i := 0; i < 100; i++ {
+		if err := q.take(ServicePresence); err != nil {
+			t.Fatalf("call %d to presence: %v", i+1, err)
+		}
+	}
+
+	usage := q.Usage()
+	if usage[ServiceProfile] != 2 || usage[ServiceSocial] != 0 || usage[ServicePresence] != 100 {
+		t.Errorf("Usage() = %v; want profile 2, social 0, presence 100", usage)
+	}
+}
+
+func TestQuotaUsesClientClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	quota := &Quota{Window: time.Minute, DefaultLimit: 1}
+	client, err := New(Config{
+		ClientID: "00000000-0000-0000-0000-000000000000",
+		Cache:    NewMemoryTokenCache(),
+		Clock:    clock,
+		Quota:    quota,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.quota.take(ServiceProfile); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.quota.take(ServiceProfile); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("second call in the window = %v; want ErrQuotaExceeded", err)
+	}
+
+	// The window slides with the client's clock, not the system's
+	clock.now = clock.now.Add(2 * time.Minute)
+	if usage := quota.Usage(); len(usage) != 0 {
+		t.Errorf("Usage() after the window = %v; want none", usage)
+	}
+	if err := client.quota.take(ServiceProfile); err != nil {
+		t.Errorf("call after the window: %v", err)
+	}
+}
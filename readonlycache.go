@@ -0,0 +1,63 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadOnlyTokenCache serves a token set supplied up front, such as one mounted into a container, and never writes it back
+// Tokens refreshed while the process runs are kept in memory, so it keeps working after the supplied access and XSTS
+// tokens expire for as long as the refresh token stays valid; every restart begins again from the supplied set
+type ReadOnlyTokenCache struct {
+	*MemoryTokenCache
+}
+
+// NewReadOnlyTokenCache creates a cache seeded with tokens, in the format of the file-based cache's tokens.json
+// The tokens are used by every TokenCacheScope
+func NewReadOnlyTokenCache(tokens CachedTokens) *ReadOnlyTokenCache {
+	m := NewMemoryTokenCache()
+	m.seed = &tokens
+	return &ReadOnlyTokenCache{MemoryTokenCache: m}
+}
+
+// ScopeAware reports false: every scope starts from the same supplied tokens, so it can't back a ClientPool
+func (r *ReadOnlyTokenCache) ScopeAware() bool {
+	return false
+}
+
+// Clear discards the tokens for the scope in ctx until the process restarts; the supplied tokens are left untouched
+func (r *ReadOnlyTokenCache) Clear(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[TokenCacheScopeFromContext(ctx)] = &CachedTokens{}
+	return nil
+}
+
+// ParseTokensJSON decodes a token set in the format of the file-based cache's tokens.json
+// It fails if the set holds neither a refresh token nor an XSTS token, since a client could not authenticate with it
+func ParseTokensJSON(data []byte) (CachedTokens, error) {
+	var tokens CachedTokens
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return CachedTokens{}, fmt.Errorf("failed to parse tokens: %w", err)
+	}
+	if tokens.RefreshToken == "" && tokens.XSTSToken == "" {
+		return CachedTokens{}, fmt.Errorf("tokens contain neither a refresh token nor an XSTS token")
+	}
+	return tokens, nil
+}
+
+// loadTokensJSON reads a token set given inline as JSON or as the path of a file holding it, such as a mounted secret
+func loadTokensJSON(value string) (CachedTokens, error) {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var err error
+		data, err = os.ReadFile(value)
+		if err != nil {
+			return CachedTokens{}, fmt.Errorf("failed to read tokens: %w", err)
+		}
+	}
+	return ParseTokensJSON(data)
+}
@@ -0,0 +1,188 @@
+package xblive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// xblRequest performs an authenticated Xbox Live API request
+// If in is non-nil it is sent as the JSON request body; if out is non-nil the JSON response is decoded into it
+func (c *Client) xblRequest(ctx context.Context, method string, endpoint string, service Service, in interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if in != nil {
+		jsonData, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	return c.xblRequestRaw(ctx, method, endpoint, service, "application/json", reqBody, out)
+}
+
+// xblRequestRaw performs an authenticated Xbox Live API request with a raw request body of the given content type
+// The contract version header is chosen by service; see contractVersion. If out is non-nil the JSON response is decoded into it
+// Reads that fail temporarily are retried as the client's Tuning allows. CallOptions on the context override client settings
+func (c *Client) xblRequestRaw(ctx context.Context, method string, endpoint string, service Service, contentType string, reqBody io.Reader, out interface{}) error {
+	// Token refreshes below share the request's correlation vector, so the whole chain can be traced
+	if c.closeCtx.Err() != nil {
+		return ErrClientClosed
+	}
+
+	ctx = ensureCorrelationVector(ctx)
+	contractVersion := c.contractVersion(ctx, service)
+
+	if c.dryRun && method != http.MethodGet && isMutation(ctx) {
+		return c.dryRunRequest(ctx, method, endpoint, reqBody)
+	}
+
+	opts := CallOptionsFromContext(ctx)
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	tuning := c.callTuning(opts)
+
+	mutation := isMutation(ctx)
+	for attempt := 0; ; attempt++ {
+		err := c.xblAttempt(ctx, method, endpoint, service, contractVersion, contentType, reqBody, out)
+		delay, retry := tuning.retryDelay(err, mutation, attempt, reqBody)
+		if !retry {
+			return err
+		}
+
+		c.logger.Debug("retrying xbox live request", "method", method, "url", endpoint, "attempt", attempt+1, "delay", delay, "error", err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+		if seeker, ok := reqBody.(io.Seeker); ok {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to rewind request: %w", err)
+			}
+		}
+	}
+}
+
+// xblAttempt sends one attempt of an Xbox Live API request; see xblRequestRaw
+func (c *Client) xblAttempt(ctx context.Context, method string, endpoint string, service Service, contractVersion string, contentType string, reqBody io.Reader, out interface{}) error {
+	if c.quota != nil {
+		if err := c.quota.take(service); err != nil {
+			return err
+		}
+	}
+
+	opts := CallOptionsFromContext(ctx)
+
+	// Ensure we have a valid XSTS token for the call's sandbox
+	xstsToken, userHash, err := c.ensureXSTSTokenFor(ctx, callAudience(opts))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+
+	// Set required headers
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-xbl-contract-version", contractVersion)
+	req.Header.Set("Authorization", fmt.Sprintf("XBL3.0 x=%s;%s", userHash, xstsToken))
+	req.Header.Set("Accept-Language", c.localizerFor(ctx).acceptLanguage())
+
+	traceID := TraceIDFromContext(ctx)
+
+	// Revalidate cached responses instead of re-fetching them
+	var cacheKey string
+	var cachedBody []byte
+	if c.httpCache != nil && method == http.MethodGet {
+		cacheKey = httpCacheKey(userHash, contractVersion, endpoint, opts)
+		if etag, body, ok := c.httpCache.Get(ctx, cacheKey); ok {
+			req.Header.Set("If-None-Match", etag)
+			cachedBody = body
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.logger.Debug("xbox live request", "method", method, "url", endpoint, "status", resp.StatusCode, "trace_id", traceID)
+
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cachedBody != nil:
+		body = cachedBody
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		errBody, truncated := captureErrorBody(resp.Body)
+		return newXboxAPIError("request", resp, errBody, truncated)
+	case cacheKey != "" && resp.Header.Get("ETag") != "":
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if err := c.httpCache.Set(ctx, cacheKey, resp.Header.Get("ETag"), body); err != nil {
+			c.logger.Warn("failed to cache response", "url", endpoint, "error", err)
+		}
+	case out == nil:
+		return nil
+	default:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return c.checkSchema(endpoint, body, out)
+}
+
+// do sends a request the way every request of the client is sent: with the trace ID from its context, the
+// User-Agent and correlation vector headers added by the header transport, and the call's timeout (see CallOptions)
+// Token chain, status, and API requests all go through it
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		req.Header.Set(TraceIDHeader, traceID)
+	}
+	return c.callHTTPClient(c.callTuning(CallOptionsFromContext(ctx)).Timeout).Do(req)
+}
+
+// dryRunRequest logs a mutating request instead of sending it and reports success
+func (c *Client) dryRunRequest(ctx context.Context, method string, endpoint string, reqBody io.Reader) error {
+	var body []byte
+	if reqBody != nil {
+		var err error
+		body, err = io.ReadAll(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+	}
+
+	c.logger.Info("dry run: request not sent", "method", method, "url", endpoint, "body_size", len(body), "body", dryRunBody(body), "trace_id", TraceIDFromContext(ctx))
+	return nil
+}
+
+// dryRunBody returns a request body for logging: JSON as text, anything else (e.g. images) omitted
+func dryRunBody(body []byte) string {
+	if !json.Valid(body) {
+		return ""
+	}
+	return string(body)
+}
@@ -0,0 +1,134 @@
+package xblive
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownFieldsFunc is called with the response fields a request's result type has no place for
+// Fields are JSON paths such as "people[].detail.newField"
+type UnknownFieldsFunc func(endpoint string, fields []string)
+
+// SchemaDriftError is returned in strict decode mode when a response contains fields the result type doesn't know
+type SchemaDriftError struct {
+	URL    string
+	Fields []string
+}
+
+func (e *SchemaDriftError) Error() string {
+	return fmt.Sprintf("response from %s has unknown fields: %s", e.URL, strings.Join(e.Fields, ", "))
+}
+
+// checkSchema reports fields in body that out has no place for, per Config.StrictDecode and Config.OnUnknownFields
+func (c *Client) checkSchema(endpoint string, body []byte, out interface{}) error {
+	if !c.strictDecode && c.onUnknownFields == nil {
+		return nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	fields := unknownFields(raw, reflect.TypeOf(out), "")
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Strings(fields)
+
+	if c.onUnknownFields != nil {
+		c.onUnknownFields(endpoint, fields)
+	}
+	if c.strictDecode {
+		return &SchemaDriftError{URL: endpoint, Fields: fields}
+	}
+	return nil
+}
+
+// unknownFields returns the JSON paths in v that have no corresponding field in t
+func unknownFields(v interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			var unknown []string
+			for key, child := range value {
+				field, ok := structField(t, key)
+				if !ok {
+					unknown = append(unknown, joinPath(path, key))
+					continue
+				}
+				unknown = append(unknown, unknownFields(child, field.Type, joinPath(path, key))...)
+			}
+			return unknown
+		case reflect.Map:
+			var unknown []string
+			for key, child := range value {
+				unknown = append(unknown, unknownFields(child, t.Elem(), joinPath(path, key))...)
+			}
+			return unknown
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		// Report each unknown path once, not once per element
+		seen := make(map[string]bool)
+		var unknown []string
+		for _, child := range value {
+			for _, field := range unknownFields(child, t.Elem(), path+"[]") {
+				if !seen[field] {
+					seen[field] = true
+					unknown = append(unknown, field)
+				}
+			}
+		}
+		return unknown
+	}
+
+	// Scalars, and anything decoded into interface{} or json.RawMessage, accept any shape
+	return nil
+}
+
+// structField finds the struct field a JSON key decodes into, matching names case-insensitively like encoding/json
+func structField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if embedded, ok := structField(field.Type, key); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// joinPath appends a key to a JSON path
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,303 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// TokenCacheScope identifies whose token chain a cache operation applies to
+// Shared caches (Redis, SQL, ...) serving many Xbox accounts should namespace their keys by the scope
+type TokenCacheScope string
+
+// tokenCacheScopeKey is the context key for the token cache scope
+type tokenCacheScopeKey struct{}
+
+// WithTokenCacheScope returns a context carrying a token cache scope
+func WithTokenCacheScope(ctx context.Context, scope TokenCacheScope) context.Context {
+	return context.WithValue(ctx, tokenCacheScopeKey{}, scope)
+}
+
+// TokenCacheScopeFromContext returns the token cache scope carried by ctx, or an empty scope
+// TokenCache implementations call this to find out which user's tokens are being read or written
+func TokenCacheScopeFromContext(ctx context.Context) TokenCacheScope {
+	scope, _ := ctx.Value(tokenCacheScopeKey{}).(TokenCacheScope)
+	return scope
+}
+
+// errNoSnapshot is returned by scopedTokenCache.Snapshot when the wrapped cache doesn't implement TokenSnapshotter
+var errNoSnapshot = errors.New("token cache does not support snapshots")
+
+// scopedTokenCache wraps a TokenCache, attaching a fixed scope to the context of every call
+type scopedTokenCache struct {
+	cache TokenCache
+	scope TokenCacheScope
+}
+
+// ctx attaches the scope to a context
+func (s *scopedTokenCache) ctx(ctx context.Context) context.Context {
+	return WithTokenCacheScope(ctx, s.scope)
+}
+
+func (s *scopedTokenCache) GetAccessToken(ctx context.Context) (string, bool) {
+	return s.cache.GetAccessToken(s.ctx(ctx))
+}
+
+func (s *scopedTokenCache) GetRefreshToken(ctx context.Context) (string, bool) {
+	return s.cache.GetRefreshToken(s.ctx(ctx))
+}
+
+func (s *scopedTokenCache) GetUserToken(ctx context.Context) (string, bool) {
+	return s.cache.GetUserToken(s.ctx(ctx))
+}
+
+func (s *scopedTokenCache) GetXSTSToken(ctx context.Context) (string, string, bool) {
+	return s.cache.GetXSTSToken(s.ctx(ctx))
+}
+
+func (s *scopedTokenCache) SetAccessToken(ctx context.Context, token string, notAfter time.Time) error {
+	return s.cache.SetAccessToken(s.ctx(ctx), token, notAfter)
+}
+
+func (s *scopedTokenCache) SetRefreshToken(ctx context.Context, token string) error {
+	return s.cache.SetRefreshToken(s.ctx(ctx), token)
+}
+
+func (s *scopedTokenCache) SetUserToken(ctx context.Context, token string, notAfter time.Time) error {
+	return s.cache.SetUserToken(s.ctx(ctx), token, notAfter)
+}
+
+func (s *scopedTokenCache) SetXSTSToken(ctx context.Context, token string, userHash string, notAfter time.Time) error {
+	return s.cache.SetXSTSToken(s.ctx(ctx), token, userHash, notAfter)
+}
+
+func (s *scopedTokenCache) Clear(ctx context.Context) error {
+	return s.cache.Clear(s.ctx(ctx))
+}
+
+// GetXSTSTokenFor forwards to the wrapped cache if it implements AudienceTokenCache
+func (s *scopedTokenCache) GetXSTSTokenFor(ctx context.Context, audience XSTSAudience) (string, string, bool) {
+	if ac, ok := s.cache.(AudienceTokenCache); ok {
+		return ac.GetXSTSTokenFor(s.ctx(ctx), audience)
+	}
+	return "", "", false
+}
+
+// SetXSTSTokenFor forwards to the wrapped cache if it implements AudienceTokenCache, otherwise the token isn't cached
+func (s *scopedTokenCache) SetXSTSTokenFor(ctx context.Context, audience XSTSAudience, token string, userHash string, notAfter time.Time) error {
+	if ac, ok := s.cache.(AudienceTokenCache); ok {
+		return ac.SetXSTSTokenFor(s.ctx(ctx), audience, token, userHash, notAfter)
+	}
+	return nil
+}
+
+// SetClock forwards to the wrapped cache if it supports a custom clock
+func (s *scopedTokenCache) SetClock(clock Clock) {
+	if cs, ok := s.cache.(clockSetter); ok {
+		cs.SetClock(clock)
+	}
+}
+
+// SetExpiryMargin forwards to the wrapped cache if it supports a configurable expiry margin
+func (s *scopedTokenCache) SetExpiryMargin(margin time.Duration) {
+	if ms, ok := s.cache.(expiryMarginSetter); ok {
+		ms.SetExpiryMargin(margin)
+	}
+}
+
+// Snapshot forwards to the wrapped cache if it implements TokenSnapshotter
+func (s *scopedTokenCache) Snapshot(ctx context.Context) (CachedTokens, error) {
+	if ts, ok := s.cache.(TokenSnapshotter); ok {
+		return ts.Snapshot(s.ctx(ctx))
+	}
+	return CachedTokens{}, errNoSnapshot
+}
+
+// ScopeAwareTokenCache is an optional interface a TokenCache implements to report whether it keeps a separate token
+// chain per TokenCacheScope; a ClientPool only accepts caches that do, so one user's tokens are never used for another
+type ScopeAwareTokenCache interface {
+	ScopeAware() bool
+}
+
+// MemoryTokenCache is an in-memory TokenCache that keeps a separate token chain per TokenCacheScope
+// It is useful for tests and as a reference for scope-aware shared cache implementations
+type MemoryTokenCache struct {
+	mu     sync.Mutex
+	tokens map[TokenCacheScope]*CachedTokens
+	clock  Clock
+	margin time.Duration
+
+	// seed, if set, is copied into each scope's token chain when it is first used
+	seed *CachedTokens
+}
+
+// NewMemoryTokenCache creates a new in-memory token cache
+func NewMemoryTokenCache() *MemoryTokenCache {
+	return &MemoryTokenCache{
+		tokens: make(map[TokenCacheScope]*CachedTokens),
+		clock:  SystemClock,
+		margin: DefaultExpirySkew,
+	}
+}
+
+// ScopeAware reports that each TokenCacheScope has its own token chain
+func (m *MemoryTokenCache) ScopeAware() bool {
+	return true
+}
+
+// SetClock sets the clock used for expiry checks
+func (m *MemoryTokenCache) SetClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+}
+
+// SetExpiryMargin sets how long before their actual expiry cached tokens are treated as expired
+func (m *MemoryTokenCache) SetExpiryMargin(margin time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.margin = margin
+}
+
+// get returns the tokens for the scope in ctx, creating them if necessary
+// The caller must hold m.mu
+func (m *MemoryTokenCache) get(ctx context.Context) *CachedTokens {
+	scope := TokenCacheScopeFromContext(ctx)
+	tokens, ok := m.tokens[scope]
+	if !ok {
+		tokens = &CachedTokens{}
+		if m.seed != nil {
+			*tokens = m.seed.clone()
+		}
+		m.tokens[scope] = tokens
+	}
+	return tokens
+}
+
+// GetAccessToken returns the cached access token if valid
+func (m *MemoryTokenCache) GetAccessToken(ctx context.Context) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	if t.AccessToken == "" || tokenExpired(m.clock.Now(), t.AccessTokenExpiry, m.margin) {
+		return "", false
+	}
+	return t.AccessToken, true
+}
+
+// GetRefreshToken returns the cached refresh token
+func (m *MemoryTokenCache) GetRefreshToken(ctx context.Context) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	if t.RefreshToken == "" {
+		return "", false
+	}
+	return t.RefreshToken, true
+}
+
+// GetUserToken returns the cached user token if valid
+func (m *MemoryTokenCache) GetUserToken(ctx context.Context) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	if t.UserToken == "" || tokenExpired(m.clock.Now(), t.UserTokenExpiry, m.margin) {
+		return "", false
+	}
+	return t.UserToken, true
+}
+
+// GetXSTSToken returns the cached XSTS token and user hash if valid
+func (m *MemoryTokenCache) GetXSTSToken(ctx context.Context) (string, string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	if t.XSTSToken == "" || t.UserHash == "" || tokenExpired(m.clock.Now(), t.XSTSTokenExpiry, m.margin) {
+		return "", "", false
+	}
+	return t.XSTSToken, t.UserHash, true
+}
+
+// SetAccessToken stores the access token
+func (m *MemoryTokenCache) SetAccessToken(ctx context.Context, token string, notAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	t.AccessToken = token
+	t.AccessTokenExpiry = notAfter
+	return nil
+}
+
+// SetRefreshToken stores the refresh token
+func (m *MemoryTokenCache) SetRefreshToken(ctx context.Context, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	t.RefreshToken = token
+	t.RefreshTokenIssued = m.clock.Now()
+	return nil
+}
+
+// SetUserToken stores the user token
+func (m *MemoryTokenCache) SetUserToken(ctx context.Context, token string, notAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	t.UserToken = token
+	t.UserTokenExpiry = notAfter
+	return nil
+}
+
+// SetXSTSToken stores the XSTS token and user hash
+func (m *MemoryTokenCache) SetXSTSToken(ctx context.Context, token string, userHash string, notAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	t.XSTSToken = token
+	t.UserHash = userHash
+	t.XSTSTokenExpiry = notAfter
+	return nil
+}
+
+// GetXSTSTokenFor returns the cached XSTS token and user hash for an audience if valid
+func (m *MemoryTokenCache) GetXSTSTokenFor(ctx context.Context, audience XSTSAudience) (string, string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cached, ok := m.get(ctx).AudienceXSTSTokens[audience.Key()]
+	if !ok || cached.Token == "" || cached.UserHash == "" || tokenExpired(m.clock.Now(), cached.Expiry, m.margin) {
+		return "", "", false
+	}
+	return cached.Token, cached.UserHash, true
+}
+
+// SetXSTSTokenFor stores the XSTS token and user hash for an audience
+func (m *MemoryTokenCache) SetXSTSTokenFor(ctx context.Context, audience XSTSAudience, token string, userHash string, notAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.get(ctx)
+	if t.AudienceXSTSTokens == nil {
+		t.AudienceXSTSTokens = make(map[string]*CachedXSTSToken)
+	}
+	t.AudienceXSTSTokens[audience.Key()] = &CachedXSTSToken{
+		Token:    token,
+		Expiry:   notAfter,
+		UserHash: userHash,
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the cached tokens for the scope in ctx
+func (m *MemoryTokenCache) Snapshot(ctx context.Context) (CachedTokens, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(ctx).clone(), nil
+}
+
+// Clear removes the cached tokens for the scope in ctx
+func (m *MemoryTokenCache) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, TokenCacheScopeFromContext(ctx))
+	return nil
+}
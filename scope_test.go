@@ -0,0 +1,53 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryTokenCacheAudienceTokens(t *testing.T) {
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges.Add(1)
+		_ = json.NewEncoder(w).Encode(XSTSTokenResponse{
+			NotAfter:      time.Now().Add(time.Hour),
+			Token:         "sandbox-token",
+			DisplayClaims: XSTSTokenDisplayClaims{Xui: []map[string]interface{}{{"uhs": "user-hash"}}},
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	if err := cache.SetUserToken(ctx, "user-token", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	audience := XSTSAudience{RelyingParty: DefaultAudience.RelyingParty, SandboxID: "XDKS.1"}
+	for i := 0; i < 3; i++ {
+		token, userHash, err := client.XSTSToken(ctx, audience)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "sandbox-token" || userHash != "user-hash" {
+			t.Fatalf("XSTSToken = %q, %q; want sandbox-token, user-hash", token, userHash)
+		}
+	}
+	if n := exchanges.Load(); n != 1 {
+		t.Errorf("%d XSTS exchanges for one audience; want 1", n)
+	}
+
+	// Other scopes and the default audience don't see the sandbox token
+	if _, _, ok := cache.GetXSTSTokenFor(WithTokenCacheScope(ctx, "other"), audience); ok {
+		t.Error("audience token leaked into another scope")
+	}
+	if _, _, ok := cache.GetXSTSToken(ctx); ok {
+		t.Error("audience token stored as the default XSTS token")
+	}
+}
@@ -0,0 +1,262 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// User search suggest endpoint
+	suggestEndpoint = "https://usersearch.xboxlive.com/suggest"
+
+	// DefaultSearchMaxItems is the number of results returned per search page when MaxItems isn't set
+	DefaultSearchMaxItems = 25
+)
+
+// Decoration selects additional data peoplehub attaches to each person
+// Each decoration adds latency and payload size, so request only what you use
+type Decoration string
+
+// Available peoplehub decorations
+const (
+	DecorationDetail             Decoration = "detail"
+	DecorationPresenceDetail     Decoration = "presenceDetail"
+	DecorationMultiplayerSummary Decoration = "multiplayerSummary"
+	DecorationPreferredColor     Decoration = "preferredColor"
+	DecorationFollower           Decoration = "follower"
+)
+
+// DefaultDecorations are the decorations requested when none are specified
+var DefaultDecorations = []Decoration{DecorationDetail}
+
+// decorationPath returns the URL path segment selecting decorations
+// nil selects DefaultDecorations; an empty non-nil slice selects none
+func decorationPath(decorations []Decoration) string {
+	if decorations == nil {
+		decorations = DefaultDecorations
+	}
+	if len(decorations) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(decorations))
+	for i, d := range decorations {
+		names[i] = string(d)
+	}
+	return "/decoration/" + strings.Join(names, ",")
+}
+
+// SearchScope selects whom a people search covers
+type SearchScope string
+
+const (
+	// SearchScopeGlobal searches all Xbox users through the peoplehub search service
+	SearchScopeGlobal SearchScope = "global"
+
+	// SearchScopeFriends searches only the caller's friends, matching the query against their gamertags, display
+	// names, and real names where visible. It is served from the friends list, so it is cheaper than a global
+	// search and doesn't send the query to the search service
+	SearchScopeFriends SearchScope = "friends"
+)
+
+// SearchOptions controls a people search
+type SearchOptions struct {
+	// MaxItems is the maximum number of results to return (optional, defaults to DefaultSearchMaxItems)
+	MaxItems int
+
+	// ContinuationToken resumes a previous search from where it left off (optional)
+	ContinuationToken string
+
+	// Decorations selects additional data to return for each person (optional, defaults to DefaultDecorations)
+	// Pass an empty non-nil slice to request no decorations
+	Decorations []Decoration
+
+	// Scope restricts the search to the caller's friends or runs it globally (optional, defaults to SearchScopeGlobal)
+	Scope SearchScope
+}
+
+// SearchResult is a page of people search results
+type SearchResult struct {
+	People []*Profile
+
+	// ContinuationToken fetches the next page when passed in SearchOptions; empty when there are no more results
+	ContinuationToken string
+}
+
+// SearchPeople searches for people matching a query, returning one page of results
+func (c *Client) SearchPeople(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if opts.MaxItems < 0 {
+		return nil, fmt.Errorf("max items must not be negative")
+	}
+
+	maxItems := opts.MaxItems
+	if maxItems == 0 {
+		maxItems = DefaultSearchMaxItems
+	}
+
+	switch opts.Scope {
+	case "", SearchScopeGlobal:
+	case SearchScopeFriends:
+		return c.searchFriends(ctx, query, maxItems, opts)
+	default:
+		return nil, fmt.Errorf("unknown search scope %q", opts.Scope)
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("maxItems", fmt.Sprintf("%d", maxItems))
+	if opts.ContinuationToken != "" {
+		params.Set("continuationToken", opts.ContinuationToken)
+	}
+
+	endpoint := fmt.Sprintf("%s/me/people/search%s?%s", peopleHubEndpoint, decorationPath(opts.Decorations), params.Encode())
+
+	var resp SearchResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServicePeopleHub, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to search people: %w", err)
+	}
+
+	return &SearchResult{
+		People:            resp.People,
+		ContinuationToken: resp.ContinuationToken,
+	}, nil
+}
+
+// searchFriends searches the caller's friends list, paging through the matches by offset
+func (c *Client) searchFriends(ctx context.Context, query string, maxItems int, opts SearchOptions) (*SearchResult, error) {
+	offset := 0
+	if opts.ContinuationToken != "" {
+		n, err := strconv.Atoi(opts.ContinuationToken)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid continuation token for a friends search")
+		}
+		offset = n
+	}
+
+	friends, err := c.getSocialList(ctx, "me", opts.Decorations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search friends: %w", err)
+	}
+
+	var people []*Profile
+	for _, p := range friends {
+		if rankPerson(p, query).Score > 0 {
+			people = append(people, p)
+		}
+	}
+
+	result := &SearchResult{}
+	if offset < len(people) {
+		end := min(offset+maxItems, len(people))
+		result.People = people[offset:end]
+		if end < len(people) {
+			result.ContinuationToken = strconv.Itoa(end)
+		}
+	}
+	return result, nil
+}
+
+// SuggestGamertags returns typeahead suggestions for a gamertag prefix
+// Suggestions are lightweight and ranked by the service; use LookupProfileByGamertag for full profiles
+func (c *Client) SuggestGamertags(ctx context.Context, prefix string) ([]*GamertagSuggestion, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix is required")
+	}
+
+	endpoint := fmt.Sprintf("%s?q=%s", suggestEndpoint, url.QueryEscape(prefix))
+
+	var resp SuggestResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceUserSearch, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	suggestions := make([]*GamertagSuggestion, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		if result.Result == nil {
+			continue
+		}
+		if result.Result.Gamertag == "" {
+			result.Result.Gamertag = result.Text
+		}
+		suggestions = append(suggestions, result.Result)
+	}
+
+	return suggestions, nil
+}
+
+// PersonMatch is a people search result ranked against a keyword
+type PersonMatch struct {
+	Profile *Profile `json:"profile"`
+
+	// Field is the profile field that best matched the keyword: gamertag, modernGamertag, displayName, or realName
+	Field string `json:"field"`
+
+	// Score ranks the match: 3 exact, 2 prefix, 1 substring, 0 matched by the service only
+	Score int `json:"score"`
+}
+
+// FindPeople searches for people by keyword (gamertag, display name, or real name where visible),
+// returning results ranked by how closely they match. Results with equal scores keep the service's order
+func (c *Client) FindPeople(ctx context.Context, keyword string, opts SearchOptions) ([]*PersonMatch, string, error) {
+	result, err := c.SearchPeople(ctx, keyword, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matches := make([]*PersonMatch, 0, len(result.People))
+	for _, p := range result.People {
+		matches = append(matches, rankPerson(p, keyword))
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches, result.ContinuationToken, nil
+}
+
+// rankPerson scores how well a profile matches a keyword
+func rankPerson(p *Profile, keyword string) *PersonMatch {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"gamertag", p.Gamertag},
+		{"modernGamertag", p.ModernGamertag},
+		{"displayName", p.DisplayName},
+		{"realName", p.RealName},
+	}
+
+	best := &PersonMatch{Profile: p}
+	k := partialFoldGamertag(keyword)
+	for _, f := range fields {
+		v := partialFoldGamertag(f.value)
+		if v == "" {
+			continue
+		}
+
+		score := 0
+		switch {
+		case v == k:
+			score = 3
+		case strings.HasPrefix(v, k):
+			score = 2
+		case strings.Contains(v, k):
+			score = 1
+		}
+
+		if score > best.Score {
+			best.Score = score
+			best.Field = f.name
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,96 @@
+package xblive
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Multiplayer Session Directory endpoint
+	sessionDirectoryEndpoint = "https://sessiondirectory.xboxlive.com"
+)
+
+// SendGameInvite invites a user into a multiplayer session of a title by creating an MPSD invite handle
+// The title ID routes the invite to the title on the invitee's device. Returns the created handle
+func (c *Client) SendGameInvite(ctx context.Context, xuid string, sessionRef SessionRef, titleID TitleID) (*SessionHandle, error) {
+	if titleID == 0 {
+		return nil, fmt.Errorf("title ID is required")
+	}
+	return c.sendInvite(ctx, xuid, sessionRef, map[string]string{"titleId": titleID.String()})
+}
+
+// sendInvite creates an MPSD invite handle with the given invite attributes
+func (c *Client) sendInvite(ctx context.Context, xuid string, sessionRef SessionRef, attributes map[string]string) (*SessionHandle, error) {
+	if xuid == "" {
+		return nil, fmt.Errorf("XUID is required")
+	}
+	if err := validateSessionRef(sessionRef); err != nil {
+		return nil, err
+	}
+
+	reqBody := SessionHandleRequest{
+		Type:             "invite",
+		Version:          1,
+		SessionRef:       sessionRef,
+		InvitedXUID:      xuid,
+		InviteAttributes: attributes,
+	}
+
+	var handle SessionHandle
+	err := c.mutate(ctx, "send_game_invite", xuid, func(ctx context.Context) error {
+		if err := c.xblRequest(ctx, "POST", sessionDirectoryEndpoint+"/handles", ServiceSessionDirectory, reqBody, &handle); err != nil {
+			return fmt.Errorf("failed to send game invite: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &handle, nil
+}
+
+// validateSessionRef checks that all parts of a session reference are present
+func validateSessionRef(ref SessionRef) error {
+	if ref.SCID == "" || ref.TemplateName == "" || ref.Name == "" {
+		return fmt.Errorf("session reference requires SCID, template name, and session name")
+	}
+	return nil
+}
+
+// sessionURL builds the MPSD URL for a session
+func sessionURL(ref SessionRef) string {
+	return fmt.Sprintf("%s/serviceconfigs/%s/sessionTemplates/%s/sessions/%s",
+		sessionDirectoryEndpoint, url.PathEscape(ref.SCID), url.PathEscape(ref.TemplateName), url.PathEscape(ref.Name))
+}
+
+// getSession fetches an MPSD session document
+func (c *Client) getSession(ctx context.Context, ref SessionRef) (*MultiplayerSession, error) {
+	var session MultiplayerSession
+	if err := c.xblRequest(ctx, "GET", sessionURL(ref), ServiceSessionDirectory, nil, &session); err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+// putSession writes an update to an MPSD session document, creating it if necessary, and returns the resulting session
+func (c *Client) putSession(ctx context.Context, ref SessionRef, update interface{}) (*MultiplayerSession, error) {
+	var session MultiplayerSession
+	if err := c.xblRequest(ctx, "PUT", sessionURL(ref), ServiceSessionDirectory, update, &session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	return &session, nil
+}
+
+// newSessionName generates a random (version 4 UUID) session name
+func newSessionName() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session name: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
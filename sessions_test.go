@@ -0,0 +1,47 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendGameInviteTitleID(t *testing.T) {
+	var got SessionHandleRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/handles" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("bad handle request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(SessionHandle{ID: "handle-1", Type: "invite"})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewMemoryTokenCache()
+	if err := cache.SetXSTSToken(ctx, "xsts-token", "user-hash", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, server, cache)
+
+	ref := SessionRef{SCID: "00000000-0000-0000-0000-000000000000", TemplateName: "lobby", Name: "my-session"}
+	if _, err := client.SendGameInvite(ctx, "2533274792093503", ref, 0); err == nil {
+		t.Error("SendGameInvite accepted a zero title ID")
+	}
+
+	handle, err := client.SendGameInvite(ctx, "2533274792093503", ref, TitleID(1144039928))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handle.ID != "handle-1" {
+		t.Errorf("handle ID = %q; want handle-1", handle.ID)
+	}
+	if got.InviteAttributes["titleId"] != "1144039928" || got.InvitedXUID != "2533274792093503" || got.SessionRef != ref {
+		t.Errorf("handle request = %+v; want the invitee, session, and titleId 1144039928", got)
+	}
+}
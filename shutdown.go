@@ -0,0 +1,101 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClientClosed is returned by calls made after Close or Shutdown
+var ErrClientClosed = errors.New("client is closed")
+
+// Flusher is implemented by token caches, HTTP caches, audit sinks, alias stores, and state stores that buffer writes
+// Shutdown flushes every one the client uses
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// addFlusher registers a component to flush on shutdown if it buffers writes
+func (c *Client) addFlusher(v interface{}) {
+	f, ok := v.(Flusher)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushers = append(c.flushers, f)
+}
+
+// track registers a background loop (KeepAlive, a watcher's Run) so Shutdown can stop it and wait for it
+// The returned context is cancelled by Shutdown as well as by the caller; call done when the loop returns
+func (c *Client) track(ctx context.Context) (context.Context, func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeCtx.Err() != nil {
+		return nil, nil, ErrClientClosed
+	}
+	c.background.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.closeCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		c.background.Done()
+	}, nil
+}
+
+// stopped returns the error a background loop returns when its context ends: ErrClientClosed after Shutdown, else the context's error
+func (c *Client) stopped(ctx context.Context) error {
+	if c.closeCtx.Err() != nil {
+		return ErrClientClosed
+	}
+	return ctx.Err()
+}
+
+// Shutdown stops the client: background loops (KeepAlive, watchers) are cancelled and waited for, buffered caches and
+// stores are flushed, and idle connections are closed (except for ClientPool clients, whose connections are shared).
+// Calls made afterwards fail with ErrClientClosed; requests already in flight complete normally. If ctx ends before
+// the background loops have returned, Shutdown still flushes and closes connections, and returns the context's error.
+// Calling it again has no further effect
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closeCtx.Err() != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closeCancel()
+	flushers := c.flushers
+	c.mu.Unlock()
+
+	var errs []error
+
+	stopped := make(chan struct{})
+	go func() {
+		c.background.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background loops did not stop: %w", ctx.Err()))
+	}
+
+	for _, f := range flushers {
+		if err := f.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush %T: %w", f, err))
+		}
+	}
+
+	if !c.sharedHTTPClient {
+		c.httpClient.CloseIdleConnections()
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close shuts the client down, waiting for background loops to stop; see Shutdown
+func (c *Client) Close() error {
+	return c.Shutdown(context.Background())
+}
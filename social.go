@@ -0,0 +1,174 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// PeopleHub endpoint
+	peopleHubEndpoint = "https://peoplehub.xboxlive.com/users"
+)
+
+// GetFriends returns the authenticated user's friends list
+func (c *Client) GetFriends(ctx context.Context) ([]*Profile, error) {
+	return c.getSocialList(ctx, "me", nil)
+}
+
+// GetFriendsWithPresence returns the authenticated user's friends with profile details, presence,
+// and multiplayer activity in a single request
+// Presence is in each profile's PresenceState, PresenceText, and PresenceDetails fields
+func (c *Client) GetFriendsWithPresence(ctx context.Context) ([]*Profile, error) {
+	return c.getSocialList(ctx, "me", []Decoration{DecorationDetail, DecorationPresenceDetail, DecorationMultiplayerSummary})
+}
+
+// GetFriendsOf returns the friends list of another user by XUID
+// Returns ErrForbidden if the user's privacy settings don't allow the caller to see their friends
+func (c *Client) GetFriendsOf(ctx context.Context, xuid string) ([]*Profile, error) {
+	if xuid == "" {
+		return nil, fmt.Errorf("XUID is required")
+	}
+	return c.getSocialList(ctx, fmt.Sprintf("xuid(%s)", url.PathEscape(xuid)), nil)
+}
+
+// GetRecentPlayers returns the users the authenticated user recently played with, most recent first
+// Each profile's RecentPlayer field lists the shared titles; presence is in PresenceState, PresenceText, and PresenceDetails
+func (c *Client) GetRecentPlayers(ctx context.Context) ([]*Profile, error) {
+	endpoint := fmt.Sprintf("%s/me/people/recentplayers%s", peopleHubEndpoint, decorationPath([]Decoration{DecorationDetail, DecorationPresenceDetail}))
+
+	var resp SearchResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServicePeopleHub, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get recent players: %w", err)
+	}
+
+	return resp.People, nil
+}
+
+// Recommendations is the authenticated user's list of suggested friends
+type Recommendations struct {
+	// People are the suggested users; each profile's Recommendation field says why
+	People []*Profile
+
+	// Summary counts the suggestions available from each source
+	Summary *RecommendationSummary
+}
+
+// GetRecommendations returns suggested friends for the authenticated user
+// decorations selects additional data for each person (nil for DefaultDecorations)
+func (c *Client) GetRecommendations(ctx context.Context, decorations []Decoration) (*Recommendations, error) {
+	endpoint := fmt.Sprintf("%s/me/people/recommendations%s", peopleHubEndpoint, decorationPath(decorations))
+
+	var resp SearchResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServicePeopleHub, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+
+	return &Recommendations{People: resp.People, Summary: resp.RecommendationSummary}, nil
+}
+
+// getSocialList fetches the social list for a peoplehub user selector (me or xuid(...))
+func (c *Client) getSocialList(ctx context.Context, user string, decorations []Decoration) ([]*Profile, error) {
+	endpoint := fmt.Sprintf("%s/%s/people/social%s", peopleHubEndpoint, user, decorationPath(decorations))
+
+	var resp SearchResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServicePeopleHub, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+
+	return resp.People, nil
+}
+
+const (
+	// Social endpoint
+	socialEndpoint = "https://social.xboxlive.com/users/me/people"
+)
+
+// AddFriend adds a user to the authenticated user's friends list
+func (c *Client) AddFriend(ctx context.Context, xuid string) error {
+	if xuid == "" {
+		return fmt.Errorf("XUID is required")
+	}
+
+	return c.mutate(ctx, "add_friend", xuid, func(ctx context.Context) error {
+		endpoint := fmt.Sprintf("%s/xuid(%s)", socialEndpoint, url.PathEscape(xuid))
+		if err := c.xblRequest(ctx, "PUT", endpoint, ServiceSocial, nil, nil); err != nil {
+			return fmt.Errorf("failed to add friend: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveFriend removes a user from the authenticated user's friends list
+func (c *Client) RemoveFriend(ctx context.Context, xuid string) error {
+	if xuid == "" {
+		return fmt.Errorf("XUID is required")
+	}
+
+	return c.mutate(ctx, "remove_friend", xuid, func(ctx context.Context) error {
+		endpoint := fmt.Sprintf("%s/xuid(%s)", socialEndpoint, url.PathEscape(xuid))
+		if err := c.xblRequest(ctx, "DELETE", endpoint, ServiceSocial, nil, nil); err != nil {
+			return fmt.Errorf("failed to remove friend: %w", err)
+		}
+		return nil
+	})
+}
+
+// Relationship describes the social relationship between the authenticated user and another user
+type Relationship struct {
+	XUID             string `json:"xuid"`
+	Gamertag         string `json:"gamertag"`
+	FollowsCaller    bool   `json:"followsCaller"`
+	FollowedByCaller bool   `json:"followedByCaller"`
+	IsFavorite       bool   `json:"isFavorite"`
+	FollowerCount    int    `json:"followerCount"`
+	FollowingCount   int    `json:"followingCount"`
+}
+
+// GetRelationship returns whether a user and the authenticated user follow each other, plus the user's follower counts
+func (c *Client) GetRelationship(ctx context.Context, xuid string) (*Relationship, error) {
+	if xuid == "" {
+		return nil, fmt.Errorf("XUID is required")
+	}
+
+	people, err := c.getPeopleByXUIDs(ctx, []string{xuid}, []Decoration{DecorationDetail})
+	if err != nil {
+		return nil, err
+	}
+	if len(people) == 0 {
+		return nil, fmt.Errorf("%w: XUID '%s'", ErrNotFound, xuid)
+	}
+
+	p := people[0]
+	rel := &Relationship{
+		XUID:             p.XUID,
+		Gamertag:         p.Gamertag,
+		FollowsCaller:    p.IsFollowingCaller,
+		FollowedByCaller: p.IsFollowedByCaller,
+		IsFavorite:       p.IsFavorite,
+	}
+	if p.Detail != nil {
+		rel.FollowerCount = p.Detail.FollowerCount
+		rel.FollowingCount = p.Detail.FollowingCount
+	}
+
+	return rel, nil
+}
+
+// getPeopleByXUIDs fetches peoplehub entries for specific users by XUID
+func (c *Client) getPeopleByXUIDs(ctx context.Context, xuids []string, decorations []Decoration) ([]*Profile, error) {
+	escaped := make([]string, len(xuids))
+	for i, xuid := range xuids {
+		escaped[i] = url.PathEscape(xuid)
+	}
+
+	endpoint := fmt.Sprintf("%s/me/people/xuids(%s)%s", peopleHubEndpoint, strings.Join(escaped, ","), decorationPath(decorations))
+
+	var resp SearchResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServicePeopleHub, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get people: %w", err)
+	}
+
+	return resp.People, nil
+}
@@ -0,0 +1,207 @@
+// Package social keeps an Xbox Live friend list in sync with an externally maintained roster,
+// computing the adds and removes needed and applying them in paced batches
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tadhunt/xblive"
+)
+
+const (
+	// DefaultBatchSize is the number of changes applied before pausing
+	DefaultBatchSize = 10
+
+	// DefaultBatchPause is the pause between batches
+	DefaultBatchPause = 5 * time.Second
+
+	// DefaultPacing is the delay between individual changes within a batch
+	DefaultPacing = 500 * time.Millisecond
+)
+
+// FriendList is the subset of the xblive client used to read and change the friend list
+type FriendList interface {
+	GetFriends(ctx context.Context) ([]*xblive.Profile, error)
+	AddFriend(ctx context.Context, xuid string) error
+	RemoveFriend(ctx context.Context, xuid string) error
+}
+
+// Action is a change to the friend list
+type Action string
+
+const (
+	ActionAdd    Action = "add"
+	ActionRemove Action = "remove"
+)
+
+// Change is a single add or remove
+type Change struct {
+	Action Action `json:"action"`
+	XUID   string `json:"xuid"`
+
+	// Gamertag is known for removals (from the current friend list), empty for additions
+	Gamertag string `json:"gamertag,omitempty"`
+}
+
+// Plan is the set of changes that brings the friend list to the desired roster
+type Plan struct {
+	Add       []Change `json:"add"`
+	Remove    []Change `json:"remove"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Changes returns the plan's removals followed by its additions
+// Removing first frees room on friend lists that are at their size limit
+func (p *Plan) Changes() []Change {
+	changes := make([]Change, 0, len(p.Remove)+len(p.Add))
+	changes = append(changes, p.Remove...)
+	return append(changes, p.Add...)
+}
+
+// Empty reports whether the friend list already matches the roster
+func (p *Plan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Remove) == 0
+}
+
+// Options controls how Sync applies a plan
+type Options struct {
+	// DryRun computes the plan without changing the friend list
+	DryRun bool
+
+	// BatchSize is the number of changes applied before pausing (optional, defaults to DefaultBatchSize)
+	BatchSize int
+
+	// BatchPause is the pause between batches (optional, defaults to DefaultBatchPause)
+	BatchPause time.Duration
+
+	// Pacing is the delay between changes within a batch (optional, defaults to DefaultPacing)
+	Pacing time.Duration
+
+	// Keep lists XUIDs that are never removed even when missing from the roster, e.g. the admins of a community account (optional)
+	Keep []string
+
+	// OnChange is called after each change is applied, with its error if it failed (optional)
+	OnChange func(change Change, err error)
+}
+
+// Result reports what Sync did
+type Result struct {
+	Plan    *Plan    `json:"plan"`
+	Applied []Change `json:"applied"`
+	Failed  []Change `json:"failed"`
+}
+
+// Diff computes the changes that bring the current friend list to the desired roster of XUIDs
+// XUIDs in keep are never removed
+func Diff(current []*xblive.Profile, desired []string, keep []string) *Plan {
+	want := make(map[string]bool, len(desired))
+	for _, xuid := range desired {
+		if xuid != "" {
+			want[xuid] = true
+		}
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, xuid := range keep {
+		kept[xuid] = true
+	}
+
+	plan := &Plan{Add: []Change{}, Remove: []Change{}}
+	have := make(map[string]bool, len(current))
+	for _, friend := range current {
+		have[friend.XUID] = true
+		if want[friend.XUID] {
+			plan.Unchanged++
+			continue
+		}
+		if kept[friend.XUID] {
+			continue
+		}
+		plan.Remove = append(plan.Remove, Change{Action: ActionRemove, XUID: friend.XUID, Gamertag: friend.Gamertag})
+	}
+	for xuid := range want {
+		if !have[xuid] {
+			plan.Add = append(plan.Add, Change{Action: ActionAdd, XUID: xuid})
+		}
+	}
+
+	sort.Slice(plan.Add, func(i, j int) bool { return plan.Add[i].XUID < plan.Add[j].XUID })
+	sort.Slice(plan.Remove, func(i, j int) bool { return plan.Remove[i].XUID < plan.Remove[j].XUID })
+	return plan
+}
+
+// Sync brings the live friend list to the desired roster of XUIDs, applying removals then additions in paced batches
+// A failed change doesn't stop the others; the returned error joins every failure. With DryRun only the plan is computed
+func Sync(ctx context.Context, friends FriendList, desired []string, opts Options) (*Result, error) {
+	current, err := friends.GetFriends(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+
+	result := &Result{Plan: Diff(current, desired, opts.Keep)}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	batchPause := opts.BatchPause
+	if batchPause <= 0 {
+		batchPause = DefaultBatchPause
+	}
+	pacing := opts.Pacing
+	if pacing <= 0 {
+		pacing = DefaultPacing
+	}
+
+	var errs []error
+	for i, change := range result.Plan.Changes() {
+		if i > 0 {
+			pause := pacing
+			if i%batchSize == 0 {
+				pause = batchPause
+			}
+			if err := sleep(ctx, pause); err != nil {
+				return result, errors.Join(append(errs, err)...)
+			}
+		}
+
+		var err error
+		switch change.Action {
+		case ActionAdd:
+			err = friends.AddFriend(ctx, change.XUID)
+		case ActionRemove:
+			err = friends.RemoveFriend(ctx, change.XUID)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, change)
+			errs = append(errs, fmt.Errorf("failed to %s %s: %w", change.Action, change.XUID, err))
+		} else {
+			result.Applied = append(result.Applied, change)
+		}
+
+		if opts.OnChange != nil {
+			opts.OnChange(change, err)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// sleep waits for d or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,267 @@
+package xblive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StateStore is a small key-value store shared by the stateful subsystems (watchers, presence logging)
+// Keys are namespaced by subsystem with a "/"-separated prefix, e.g. "presence/2533274...", so one store can hold them all
+type StateStore interface {
+	// Get returns the value stored under a key
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Put stores a value under a key, replacing any existing value
+	Put(ctx context.Context, key string, value []byte) error
+
+	// List returns every entry whose key starts with prefix, ordered by key
+	List(ctx context.Context, prefix string) ([]StateEntry, error)
+}
+
+// StateEntry is a key and its value in a StateStore
+type StateEntry struct {
+	Key   string
+	Value []byte
+}
+
+// getState reads a JSON value from a state store
+func getState(ctx context.Context, store StateStore, key string, v interface{}) (bool, error) {
+	data, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("invalid state for %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// putState writes a JSON value to a state store
+func putState(ctx context.Context, store StateStore, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state for %s: %w", key, err)
+	}
+	return store.Put(ctx, key, data)
+}
+
+// MemoryStateStore is a StateStore held in memory, lost when the process exits
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// NewMemoryStateStore creates an empty in-memory state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{entries: make(map[string][]byte)}
+}
+
+// Get returns the value stored under a key
+func (m *MemoryStateStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.entries[key]
+	return value, ok, nil
+}
+
+// Put stores a value under a key
+func (m *MemoryStateStore) Put(ctx context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// List returns every entry whose key starts with prefix, ordered by key
+func (m *MemoryStateStore) List(ctx context.Context, prefix string) ([]StateEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return listEntries(m.entries, prefix), nil
+}
+
+// FileStateStore is a StateStore persisted as a JSON file, rewritten on every change
+// It suits small amounts of state such as watcher positions; use a SQLStateStore for long histories
+type FileStateStore struct {
+	mu       sync.Mutex
+	filePath string
+	entries  map[string][]byte
+}
+
+// NewFileStateStore creates a state store backed by a JSON file, loading any state it already holds
+func NewFileStateStore(filePath string) (*FileStateStore, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	store := &FileStateStore{
+		filePath: filePath,
+		entries:  make(map[string][]byte),
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.entries); err != nil {
+			return nil, fmt.Errorf("failed to parse state: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Get returns the value stored under a key
+func (f *FileStateStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.entries[key]
+	return value, ok, nil
+}
+
+// Put stores a value under a key and writes the file
+func (f *FileStateStore) Put(ctx context.Context, key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.entries[key] = append([]byte(nil), value...)
+
+	data, err := json.MarshalIndent(f.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := os.WriteFile(f.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// List returns every entry whose key starts with prefix, ordered by key
+func (f *FileStateStore) List(ctx context.Context, prefix string) ([]StateEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return listEntries(f.entries, prefix), nil
+}
+
+// listEntries returns copies of the entries of a map whose key starts with prefix, ordered by key
+func listEntries(entries map[string][]byte, prefix string) []StateEntry {
+	var list []StateEntry
+	for key, value := range entries {
+		if strings.HasPrefix(key, prefix) {
+			list = append(list, StateEntry{Key: key, Value: append([]byte(nil), value...)})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// DefaultStateTable is the table a SQLStateStore uses when none is given
+const DefaultStateTable = "xblive_state"
+
+// SQLStateStore is a StateStore kept in a SQLite table through database/sql
+// Open the database with the SQLite driver of your choice (e.g. modernc.org/sqlite or github.com/mattn/go-sqlite3);
+// this package doesn't import one so the core library stays dependency-free
+type SQLStateStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStateStore creates a state store in a table of a SQLite database, creating the table if needed
+// table is optional and defaults to DefaultStateTable
+func NewSQLStateStore(ctx context.Context, db *sql.DB, table string) (*SQLStateStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+	if table == "" {
+		table = DefaultStateTable
+	}
+	for _, r := range table {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return nil, fmt.Errorf("invalid table name %q", table)
+		}
+	}
+
+	schema := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, value BLOB NOT NULL)", table)
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return nil, fmt.Errorf("failed to create state table: %w", err)
+	}
+
+	return &SQLStateStore{db: db, table: table}, nil
+}
+
+// Get returns the value stored under a key
+func (s *SQLStateStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT value FROM %s WHERE key = ?", s.table), key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read state: %w", err)
+	}
+	return value, true, nil
+}
+
+// Put stores a value under a key
+func (s *SQLStateStore) Put(ctx context.Context, key string, value []byte) error {
+	query := fmt.Sprintf("INSERT INTO %s (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value", s.table)
+	if _, err := s.db.ExecContext(ctx, query, key, value); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// List returns every entry whose key starts with prefix, ordered by key
+// Prefixes are matched as a key range rather than with LIKE, which is case-insensitive in SQLite
+func (s *SQLStateStore) List(ctx context.Context, prefix string) ([]StateEntry, error) {
+	query := fmt.Sprintf("SELECT key, value FROM %s WHERE key >= ?", s.table)
+	args := []interface{}{prefix}
+	if end, ok := prefixEnd(prefix); ok {
+		query += " AND key < ?"
+		args = append(args, end)
+	}
+	query += " ORDER BY key"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state: %w", err)
+	}
+	defer rows.Close()
+
+	var list []StateEntry
+	for rows.Next() {
+		var e StateEntry
+		if err := rows.Scan(&e.Key, &e.Value); err != nil {
+			return nil, fmt.Errorf("failed to list state: %w", err)
+		}
+		list = append(list, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list state: %w", err)
+	}
+	return list, nil
+}
+
+// prefixEnd returns the smallest string greater than every string with the prefix, if there is one
+func prefixEnd(prefix string) (string, bool) {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Title-managed stats write endpoint, formatted with the XUID and SCID
+	statsWriteEndpoint = "https://statswrite.xboxlive.com/stats/users/%s/scids/%s"
+
+	// statsSchema2017 identifies the 2017 stats model in stats writes
+	statsSchema2017 = "http://stats.xboxlive.com/2017-1/schema#"
+)
+
+// StatsUpdate is a set of stats to publish for a user in a title
+type StatsUpdate struct {
+	// XUID is the user the stats belong to (optional, defaults to the authenticated user)
+	XUID string
+
+	// Revision numbers the write; it must be greater than the revision of the user's previous write for the title
+	// Game servers typically use a counter they persist alongside the player's data, or a Unix timestamp (required)
+	Revision int64
+
+	// PreviousRevision is the revision this write replaces, 0 for a user's first write (optional)
+	PreviousRevision int64
+
+	// Stats maps stat names, as configured for the title in Partner Center, to integer, float, or string values (required)
+	Stats map[string]interface{}
+}
+
+// WriteTitleStats publishes a user's stats for a title (SCID) using the 2017 stats model, where the title owns the values
+// Leaderboards configured over the stats update from them. A write whose revision isn't newer than the stored one
+// is rejected with an error wrapping ErrConflict
+func (c *Client) WriteTitleStats(ctx context.Context, scid SCID, update StatsUpdate) error {
+	if scid == "" {
+		return fmt.Errorf("SCID is required")
+	}
+	if len(update.Stats) == 0 {
+		return fmt.Errorf("at least one stat is required")
+	}
+	if update.Revision <= update.PreviousRevision {
+		return fmt.Errorf("revision %d must be greater than previous revision %d", update.Revision, update.PreviousRevision)
+	}
+
+	values := make(map[string]StatsWriteValue, len(update.Stats))
+	for name, value := range update.Stats {
+		switch value.(type) {
+		case int, int32, int64, uint, uint32, uint64, float32, float64, string:
+		default:
+			return fmt.Errorf("stat %s has unsupported type %T: expected a number or string", name, value)
+		}
+		values[name] = StatsWriteValue{Value: value}
+	}
+
+	xuid := update.XUID
+	if xuid == "" {
+		claims, err := c.Identity(ctx)
+		if err != nil {
+			return err
+		}
+		xuid = claims.XUID
+	}
+
+	reqBody := StatsWriteRequest{
+		Schema:           statsSchema2017,
+		PreviousRevision: update.PreviousRevision,
+		Revision:         update.Revision,
+		Timestamp:        c.clock.Now().UTC(),
+		Stats:            StatsWriteStats{Title: values},
+	}
+	endpoint := fmt.Sprintf(statsWriteEndpoint, url.PathEscape(xuid), url.PathEscape(string(scid)))
+
+	return c.mutate(ctx, "write_title_stats", xuid, func(ctx context.Context) error {
+		err := c.xblRequest(ctx, "POST", endpoint, ServiceStats, reqBody, nil)
+		if errors.Is(err, ErrConflict) {
+			return fmt.Errorf("stats revision %d is not newer than the stored revision: %w", update.Revision, err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write title stats: %w", err)
+		}
+		return nil
+	})
+}
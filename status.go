@@ -0,0 +1,120 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// Xbox network status endpoint; unauthenticated
+	serviceStatusEndpoint = "https://xnotify.xboxlive.com/servicestatusv6/US/en-US"
+)
+
+// ServiceState is the health of the Xbox network, a service, or a scenario
+type ServiceState string
+
+const (
+	// ServiceStateUp means no known problems ("None" in the status feed)
+	ServiceStateUp ServiceState = "None"
+
+	// ServiceStateImpacted means some functionality is degraded
+	ServiceStateImpacted ServiceState = "Impacted"
+
+	// ServiceStateMajorOutage means the service is largely unavailable
+	ServiceStateMajorOutage ServiceState = "MajorOutage"
+)
+
+// ServiceStatus is the current health of the Xbox network as published on the Xbox status page
+type ServiceStatus struct {
+	Status struct {
+		Overall struct {
+			State       ServiceState `json:"State"`
+			LastUpdated time.Time    `json:"LastUpdated"`
+		} `json:"Overall"`
+	} `json:"Status"`
+	CoreServices []*ServiceCategory `json:"CoreServices"`
+	Titles       []*ServiceCategory `json:"Titles"`
+}
+
+// ServiceCategory is a core service (e.g. "Account & profile") or a title with its own status
+type ServiceCategory struct {
+	ID        int64              `json:"Id"`
+	Name      string             `json:"Name"`
+	Status    ServiceStateName   `json:"Status"`
+	Scenarios []*ServiceScenario `json:"Scenarios"`
+}
+
+// ServiceScenario is a piece of functionality within a service (e.g. "Signing in")
+type ServiceScenario struct {
+	ID        int64              `json:"Id"`
+	Name      string             `json:"Name"`
+	Status    ServiceStateName   `json:"Status"`
+	Incidents []*ServiceIncident `json:"Incidents"`
+}
+
+// ServiceStateName wraps a ServiceState as it appears in the status feed
+type ServiceStateName struct {
+	Name ServiceState `json:"Name"`
+}
+
+// ServiceIncident is a known problem affecting a scenario
+type ServiceIncident struct {
+	ID      int64     `json:"Id"`
+	Stage   string    `json:"Stage"`
+	Begin   time.Time `json:"Begin"`
+	Message string    `json:"Message"`
+}
+
+// ServiceStatus returns the current health of the Xbox network
+// It needs no authentication, so it can tell an Xbox outage apart from broken credentials
+func (c *Client) ServiceStatus(ctx context.Context) (*ServiceStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", serviceStatusEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, truncated := captureErrorBody(resp.Body)
+		return nil, newXboxAPIError("service status request", resp, body, truncated)
+	}
+
+	var status ServiceStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode service status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// Overall returns the overall state of the Xbox network
+func (s *ServiceStatus) Overall() ServiceState {
+	return s.Status.Overall.State
+}
+
+// Healthy reports whether every core service and title is up
+func (s *ServiceStatus) Healthy() bool {
+	return s.Overall() == ServiceStateUp && len(s.Impacted()) == 0
+}
+
+// Impacted returns the core services and titles that are not up
+func (s *ServiceStatus) Impacted() []*ServiceCategory {
+	var impacted []*ServiceCategory
+	for _, categories := range [][]*ServiceCategory{s.CoreServices, s.Titles} {
+		for _, category := range categories {
+			if category.Status.Name != ServiceStateUp && category.Status.Name != "" {
+				impacted = append(impacted, category)
+			}
+		}
+	}
+	return impacted
+}
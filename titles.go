@@ -0,0 +1,141 @@
+package xblive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TitleID identifies an Xbox Live title
+// Services disagree on the format: most JSON APIs use decimal, while MPSD, title storage, and the portals use hex
+type TitleID uint32
+
+// ParseTitleID parses a title ID in decimal, or in hex when prefixed with 0x
+func ParseTitleID(s string) (TitleID, error) {
+	s = strings.TrimSpace(s)
+	if hex, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		return ParseTitleIDHex(hex)
+	}
+
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid title ID '%s': %w", s, err)
+	}
+	return TitleID(id), nil
+}
+
+// ParseTitleIDHex parses a title ID in hex, with or without a 0x prefix
+func ParseTitleIDHex(s string) (TitleID, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToLower(s), "0x")
+
+	id, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex title ID '%s': %w", s, err)
+	}
+	return TitleID(id), nil
+}
+
+// String returns the decimal form used by most Xbox Live JSON APIs
+func (t TitleID) String() string {
+	return strconv.FormatUint(uint64(t), 10)
+}
+
+// Hex returns the 8-digit lowercase hex form, without a prefix
+func (t TitleID) Hex() string {
+	return fmt.Sprintf("%08x", uint32(t))
+}
+
+// ByteSwapped returns the title ID with its byte order reversed
+// Some services and save formats store title IDs little-endian; this converts between the two readings
+func (t TitleID) ByteSwapped() TitleID {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(t))
+	return TitleID(binary.LittleEndian.Uint32(b[:]))
+}
+
+// SCID is a service configuration ID, the GUID identifying a title's Xbox Live configuration
+type SCID string
+
+var scidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// ParseSCID validates a service configuration ID and returns it in canonical lowercase form
+// Surrounding braces, as shown by some tools, are removed
+func ParseSCID(s string) (SCID, error) {
+	canonical := strings.ToLower(strings.Trim(strings.TrimSpace(s), "{}"))
+	if !scidPattern.MatchString(canonical) {
+		return "", fmt.Errorf("invalid SCID '%s'", s)
+	}
+	return SCID(canonical), nil
+}
+
+// TitleID returns the title ID embedded in the last 8 hex digits of the SCID
+// This holds for SCIDs generated by the Xbox developer portals; older hand-assigned SCIDs don't embed one
+func (s SCID) TitleID() TitleID {
+	str := string(s)
+	if len(str) < 8 {
+		return 0
+	}
+	id, err := strconv.ParseUint(str[len(str)-8:], 16, 32)
+	if err != nil {
+		return 0
+	}
+	return TitleID(id)
+}
+
+// String returns the SCID
+func (s SCID) String() string {
+	return string(s)
+}
+
+// ProductID is a Microsoft Store product ID (e.g. 9NBLGGH2JHXJ)
+type ProductID string
+
+var productIDPattern = regexp.MustCompile(`^[0-9A-Z]{12}$`)
+
+// ParseProductID validates a Store product ID and returns it in canonical uppercase form
+func ParseProductID(s string) (ProductID, error) {
+	canonical := strings.ToUpper(strings.TrimSpace(s))
+	if !productIDPattern.MatchString(canonical) {
+		return "", fmt.Errorf("invalid product ID '%s'", s)
+	}
+	return ProductID(canonical), nil
+}
+
+// String returns the product ID
+func (p ProductID) String() string {
+	return string(p)
+}
+
+// Title describes the IDs of a well-known title
+type Title struct {
+	Name      string
+	TitleID   TitleID
+	SCID      SCID
+	ProductID ProductID
+}
+
+// KnownTitles is a curated list of popular titles
+// Use LookupTitle to search it by name or title ID
+var KnownTitles = []Title{
+	{Name: "Minecraft for Windows", TitleID: 896928775, SCID: "4fc10100-5f7a-4470-899b-280835760c07", ProductID: "9NBLGGH2JHXJ"},
+	{Name: "Minecraft for Xbox", TitleID: 1828326430, SCID: "4fc10100-5f7a-4470-899b-280835760c07"},
+}
+
+// LookupTitle returns the known titles whose name contains the query (case-insensitive) or whose title ID matches it
+// The query may be a decimal or 0x-prefixed hex title ID
+func LookupTitle(query string) []Title {
+	var matches []Title
+
+	id, idErr := ParseTitleID(query)
+	lower := strings.ToLower(strings.TrimSpace(query))
+	for _, title := range KnownTitles {
+		if (idErr == nil && title.TitleID == id) || (lower != "" && strings.Contains(strings.ToLower(title.Name), lower)) {
+			matches = append(matches, title)
+		}
+	}
+
+	return matches
+}
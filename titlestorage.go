@@ -0,0 +1,100 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Title storage endpoint
+	titleStorageEndpoint = "https://titlestorage.xboxlive.com"
+)
+
+// TitleStorageType is a title storage area
+type TitleStorageType string
+
+const (
+	// TitleStorageTrustedPlatform is per-user storage that only the title can write, such as save data
+	TitleStorageTrustedPlatform TitleStorageType = "trustedplatform"
+
+	// TitleStorageUniversal is per-user storage shared by a title's versions across devices
+	TitleStorageUniversal TitleStorageType = "universalplatform"
+
+	// TitleStorageGlobal is storage shared by all of a title's users, written by the publisher
+	TitleStorageGlobal TitleStorageType = "global"
+)
+
+// TitleStorageTypes lists the title storage areas
+var TitleStorageTypes = []TitleStorageType{TitleStorageTrustedPlatform, TitleStorageUniversal, TitleStorageGlobal}
+
+// perUser reports whether each user has their own area of this type
+func (t TitleStorageType) perUser() bool {
+	return t != TitleStorageGlobal
+}
+
+// TitleStorageQuota is the space used in and allowed for a title storage area
+type TitleStorageQuota struct {
+	StorageType TitleStorageType `json:"storageType"`
+	UsedBytes   int64            `json:"usedBytes"`
+	QuotaBytes  int64            `json:"quotaBytes"`
+}
+
+// RemainingBytes returns the space left before the quota is reached
+func (q *TitleStorageQuota) RemainingBytes() int64 {
+	return max(q.QuotaBytes-q.UsedBytes, 0)
+}
+
+// GetTitleStorageQuota returns a title's storage quota and usage for a storage type
+// For the per-user types the quota is that of the given user (optional, defaults to the authenticated user);
+// TitleStorageGlobal ignores the XUID
+func (c *Client) GetTitleStorageQuota(ctx context.Context, scid SCID, storageType TitleStorageType, xuid string) (*TitleStorageQuota, error) {
+	if scid == "" {
+		return nil, fmt.Errorf("SCID is required")
+	}
+
+	if storageType == "" {
+		return nil, fmt.Errorf("storage type is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/global/scids/%s", titleStorageEndpoint, url.PathEscape(string(scid)))
+	if storageType.perUser() {
+		if xuid == "" {
+			claims, err := c.Identity(ctx)
+			if err != nil {
+				return nil, err
+			}
+			xuid = claims.XUID
+		}
+		endpoint = fmt.Sprintf("%s/%s/users/xuid(%s)/scids/%s", titleStorageEndpoint,
+			url.PathEscape(string(storageType)), url.PathEscape(xuid), url.PathEscape(string(scid)))
+	}
+
+	var resp TitleStorageQuotaResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceTitleStorage, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get %s title storage quota: %w", storageType, err)
+	}
+
+	return &TitleStorageQuota{
+		StorageType: storageType,
+		UsedBytes:   resp.QuotaInfo.UsedBytes,
+		QuotaBytes:  resp.QuotaInfo.QuotaBytes,
+	}, nil
+}
+
+// GetTitleStorageQuotas returns a user's quota and usage in each per-user title storage type
+// The XUID is optional and defaults to the authenticated user
+func (c *Client) GetTitleStorageQuotas(ctx context.Context, scid SCID, xuid string) ([]*TitleStorageQuota, error) {
+	var quotas []*TitleStorageQuota
+	for _, storageType := range TitleStorageTypes {
+		if !storageType.perUser() {
+			continue
+		}
+		quota, err := c.GetTitleStorageQuota(ctx, scid, storageType, xuid)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
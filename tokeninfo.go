@@ -0,0 +1,72 @@
+package xblive
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RefreshTokenInactivityWindow is how long a Microsoft refresh token remains usable without being redeemed
+const RefreshTokenInactivityWindow = 90 * 24 * time.Hour
+
+// TokenInfo describes the validity and expiry of each token in the cached chain
+// Expiry timestamps are zero when the cache doesn't implement TokenSnapshotter
+type TokenInfo struct {
+	AccessTokenValid  bool      `json:"access_token_valid"`
+	AccessTokenExpiry time.Time `json:"access_token_expiry"`
+
+	RefreshTokenPresent bool `json:"refresh_token_present"`
+
+	// RefreshTokenIssued is when the refresh token was last stored; it goes stale RefreshTokenInactivityWindow later
+	RefreshTokenIssued     time.Time `json:"refresh_token_issued"`
+	RefreshTokenStaleAfter time.Time `json:"refresh_token_stale_after"`
+
+	UserTokenValid  bool      `json:"user_token_valid"`
+	UserTokenExpiry time.Time `json:"user_token_expiry"`
+
+	XSTSTokenValid  bool      `json:"xsts_token_valid"`
+	XSTSTokenExpiry time.Time `json:"xsts_token_expiry"`
+}
+
+// TokenInfo reports the validity and expiry of each cached token without contacting any service
+func (c *Client) TokenInfo(ctx context.Context) (*TokenInfo, error) {
+	info := &TokenInfo{}
+
+	_, info.AccessTokenValid = c.cache.GetAccessToken(ctx)
+	_, info.RefreshTokenPresent = c.cache.GetRefreshToken(ctx)
+	_, info.UserTokenValid = c.cache.GetUserToken(ctx)
+	_, _, info.XSTSTokenValid = c.cache.GetXSTSToken(ctx)
+
+	snapshotter, ok := c.cache.(TokenSnapshotter)
+	if !ok {
+		return info, nil
+	}
+
+	tokens, err := snapshotter.Snapshot(ctx)
+	if errors.Is(err, errNoSnapshot) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info.AccessTokenExpiry = tokens.AccessTokenExpiry
+	info.UserTokenExpiry = tokens.UserTokenExpiry
+	info.XSTSTokenExpiry = tokens.XSTSTokenExpiry
+	if info.RefreshTokenPresent && !tokens.RefreshTokenIssued.IsZero() {
+		info.RefreshTokenIssued = tokens.RefreshTokenIssued
+		info.RefreshTokenStaleAfter = tokens.RefreshTokenIssued.Add(RefreshTokenInactivityWindow)
+	}
+
+	return info, nil
+}
+
+// Warmup resolves the full token chain up front and returns its expiry info
+// Services call this at startup to fail fast on missing or broken credentials instead of on the first request
+func (c *Client) Warmup(ctx context.Context) (*TokenInfo, error) {
+	ctx = ensureCorrelationVector(ctx)
+	if _, _, err := c.ensureXSTSToken(ctx); err != nil {
+		return nil, err
+	}
+	return c.TokenInfo(ctx)
+}
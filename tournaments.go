@@ -0,0 +1,67 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// Tournaments Hub endpoint
+	tournamentsEndpoint = "https://tournamentshub.xboxlive.com/tournaments"
+)
+
+// ListTournaments returns the Arena tournaments for a title
+func (c *Client) ListTournaments(ctx context.Context, titleID string) ([]*Tournament, error) {
+	if titleID == "" {
+		return nil, fmt.Errorf("title ID is required")
+	}
+
+	endpoint := fmt.Sprintf("%s?titleId=%s", tournamentsEndpoint, url.QueryEscape(titleID))
+
+	var resp TournamentsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceTournaments, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list tournaments: %w", err)
+	}
+
+	return resp.Value, nil
+}
+
+// GetTournamentTeams returns the teams registered for a tournament
+func (c *Client) GetTournamentTeams(ctx context.Context, ref TournamentRef) ([]*TournamentTeam, error) {
+	endpoint, err := tournamentURL(ref, "teams")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TournamentTeamsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceTournaments, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get tournament teams: %w", err)
+	}
+
+	return resp.Value, nil
+}
+
+// GetTournamentMatches returns the matches and their results for a tournament
+func (c *Client) GetTournamentMatches(ctx context.Context, ref TournamentRef) ([]*TournamentMatch, error) {
+	endpoint, err := tournamentURL(ref, "matches")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TournamentMatchesResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceTournaments, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get tournament matches: %w", err)
+	}
+
+	return resp.Value, nil
+}
+
+// tournamentURL builds the URL for a tournament sub-resource
+func tournamentURL(ref TournamentRef, resource string) (string, error) {
+	if ref.Organizer == "" || ref.TournamentID == "" {
+		return "", fmt.Errorf("tournament organizer and ID are required")
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s", tournamentsEndpoint, url.PathEscape(ref.Organizer), url.PathEscape(ref.TournamentID), resource), nil
+}
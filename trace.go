@@ -0,0 +1,32 @@
+package xblive
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TraceIDHeader is the header that carries the trace ID on outbound requests
+const TraceIDHeader = "X-Trace-Id"
+
+// traceIDKey is the context key for the trace ID
+type traceIDKey struct{}
+
+// WithTraceID returns a context carrying a trace ID
+// Requests made with the context send it in TraceIDHeader and include it in their log records
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or an empty string
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// NewTraceID generates a random 128-bit trace ID encoded as hex
+func NewTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,6 +1,9 @@
 package xblive
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // DeviceCodeResponse represents the response from the device code flow
 type DeviceCodeResponse struct {
@@ -75,8 +78,57 @@ type XSTSTokenDisplayClaims struct {
 }
 
 // SearchResponse represents the response from people search endpoint
+// Every peoplehub list (search, friends, recent players, ...) returns this shape
 type SearchResponse struct {
-	People []*Profile `json:"people"`
+	People                []*Profile             `json:"people"`
+	RecommendationSummary *RecommendationSummary `json:"recommendationSummary"`
+	FriendFinderState     *FriendFinderState     `json:"friendFinderState"`
+	AccountLinkDetails    []*AccountLinkDetail   `json:"accountLinkDetails"`
+	ContinuationToken     string                 `json:"continuationToken"`
+}
+
+// RecommendationSummary counts the friend recommendations available from each source
+type RecommendationSummary struct {
+	FriendOfFriend     int  `json:"friendOfFriend"`
+	FacebookFriend     int  `json:"facebookFriend"`
+	PhoneContact       int  `json:"phoneContact"`
+	Follower           int  `json:"follower"`
+	VIP                int  `json:"VIP"`
+	SteamFriend        int  `json:"steamFriend"`
+	PromoteSuggestions bool `json:"promoteSuggestions"`
+}
+
+// FriendFinderState reports the caller's opt-in and token status for each external friend finder network
+type FriendFinderState struct {
+	FacebookOptInStatus  string `json:"facebookOptInStatus"`
+	FacebookTokenStatus  string `json:"facebookTokenStatus"`
+	PhoneOptInStatus     string `json:"phoneOptInStatus"`
+	PhoneTokenStatus     string `json:"phoneTokenStatus"`
+	SteamOptInStatus     string `json:"steamOptInStatus"`
+	SteamTokenStatus     string `json:"steamTokenStatus"`
+	DiscordOptInStatus   string `json:"discordOptInStatus"`
+	DiscordTokenStatus   string `json:"discordTokenStatus"`
+	InstagramOptInStatus string `json:"instagramOptInStatus"`
+	InstagramTokenStatus string `json:"instagramTokenStatus"`
+	MixerOptInStatus     string `json:"mixerOptInStatus"`
+	MixerTokenStatus     string `json:"mixerTokenStatus"`
+	RedditOptInStatus    string `json:"redditOptInStatus"`
+	RedditTokenStatus    string `json:"redditTokenStatus"`
+	TwitchOptInStatus    string `json:"twitchOptInStatus"`
+	TwitchTokenStatus    string `json:"twitchTokenStatus"`
+	TwitterOptInStatus   string `json:"twitterOptInStatus"`
+	TwitterTokenStatus   string `json:"twitterTokenStatus"`
+	YouTubeOptInStatus   string `json:"youTubeOptInStatus"`
+	YouTubeTokenStatus   string `json:"youTubeTokenStatus"`
+}
+
+// AccountLinkDetail describes an external account linked to the caller's Xbox account
+type AccountLinkDetail struct {
+	NetworkName      string `json:"networkName"`
+	DisplayName      string `json:"displayName"`
+	ShowOnProfile    bool   `json:"showOnProfile"`
+	IsFamilyFriendly bool   `json:"isFamilyFriendly"`
+	Deeplink         string `json:"deeplink"`
 }
 
 // Profile represents an Xbox Live user profile
@@ -90,8 +142,8 @@ type Profile struct {
 	ModernGamertag       string         `json:"modernGamertag"`
 	ModernGamertagSuffix string         `json:"modernGamertagSuffix"`
 	UniqueModernGamertag string         `json:"uniqueModernGamertag"`
-	XboxOneRep           string         `json:"xboxOneRep"`
-	PresenceState        string         `json:"presenceState"`
+	XboxOneRep           Reputation     `json:"xboxOneRep"`
+	PresenceState        PresenceState  `json:"presenceState"`
 	PresenceText         string         `json:"presenceText"`
 	IsFavorite           bool           `json:"isFavorite"`
 	IsFollowingCaller    bool           `json:"isFollowingCaller"`
@@ -100,32 +152,100 @@ type Profile struct {
 	IsQuarantined        bool           `json:"isQuarantined"`
 	IsXbox360Gamerpic    bool           `json:"isXbox360Gamerpic"`
 	Detail               *ProfileDetail `json:"detail"`
+
+	// Populated only when the matching decoration is requested
+	PreferredColor     *PreferredColor     `json:"preferredColor,omitempty"`
+	PresenceDetails    []*PresenceDetail   `json:"presenceDetails,omitempty"`
+	MultiplayerSummary *MultiplayerSummary `json:"multiplayerSummary,omitempty"`
+
+	// Populated only by GetRecentPlayers
+	RecentPlayer *RecentPlayer `json:"recentPlayer,omitempty"`
+
+	// Populated only by GetRecommendations
+	Recommendation *Recommendation `json:"recommendation,omitempty"`
+}
+
+// Recommendation explains why a user is suggested as a friend
+type Recommendation struct {
+	Type    string   `json:"Type"`
+	Reasons []string `json:"Reasons"`
+}
+
+// RecentPlayer describes when and in which titles the caller played with a user
+type RecentPlayer struct {
+	Titles []*RecentPlayerTitle `json:"titles"`
+	Text   string               `json:"text"`
+}
+
+// RecentPlayerTitle is a title the caller recently played with a user
+type RecentPlayerTitle struct {
+	TitleID                string    `json:"titleId"`
+	TitleName              string    `json:"titleName"`
+	LastPlayedWithDateTime time.Time `json:"lastPlayedWithDateTime"`
+}
+
+// PreferredColor contains a user's chosen profile colors
+type PreferredColor struct {
+	PrimaryColor   string `json:"primaryColor"`
+	SecondaryColor string `json:"secondaryColor"`
+	TertiaryColor  string `json:"tertiaryColor"`
+}
+
+// PresenceDetail describes a title a user is active in, as returned by the presenceDetail decoration
+type PresenceDetail struct {
+	IsBroadcasting   bool   `json:"IsBroadcasting"`
+	Device           string `json:"Device"`
+	PresenceText     string `json:"PresenceText"`
+	State            string `json:"State"`
+	TitleID          string `json:"TitleId"`
+	TitleType        string `json:"TitleType"`
+	IsPrimary        bool   `json:"IsPrimary"`
+	IsGame           bool   `json:"IsGame"`
+	RichPresenceText string `json:"RichPresenceText"`
+}
+
+// MultiplayerSummary contains a user's multiplayer activity, as returned by the multiplayerSummary decoration
+type MultiplayerSummary struct {
+	InMultiplayerSession int `json:"InMultiplayerSession"`
+	InParty              int `json:"InParty"`
 }
 
 // ProfileDetail contains additional profile details
 type ProfileDetail struct {
-	AccountTier    string `json:"accountTier"`
-	Bio            string `json:"bio"`
-	IsVerified     bool   `json:"isVerified"`
-	Location       string `json:"location"`
-	Tenure         string `json:"tenure"`
-	Blocked        bool   `json:"blocked"`
-	Mute           bool   `json:"mute"`
-	FollowerCount  int    `json:"followerCount"`
-	FollowingCount int    `json:"followingCount"`
-	HasGamePass    bool   `json:"hasGamePass"`
+	AccountTier    AccountTier `json:"accountTier"`
+	Bio            string      `json:"bio"`
+	IsVerified     bool        `json:"isVerified"`
+	Location       string      `json:"location"`
+	Tenure         string      `json:"tenure"`
+	Watermarks     []string    `json:"watermarks"`
+	Blocked        bool        `json:"blocked"`
+	Mute           bool        `json:"mute"`
+	FollowerCount  int         `json:"followerCount"`
+	FollowingCount int         `json:"followingCount"`
+	HasGamePass    bool        `json:"hasGamePass"`
 }
 
 // CachedTokens represents cached authentication tokens
 type CachedTokens struct {
-	AccessToken       string    `json:"access_token"`
-	RefreshToken      string    `json:"refresh_token"`
-	AccessTokenExpiry time.Time `json:"access_token_expiry"`
-	UserToken         string    `json:"user_token"`
-	UserTokenExpiry   time.Time `json:"user_token_expiry"`
-	XSTSToken         string    `json:"xsts_token"`
-	XSTSTokenExpiry   time.Time `json:"xsts_token_expiry"`
-	UserHash          string    `json:"user_hash"`
+	AccessToken        string    `json:"access_token"`
+	RefreshToken       string    `json:"refresh_token"`
+	RefreshTokenIssued time.Time `json:"refresh_token_issued"`
+	AccessTokenExpiry  time.Time `json:"access_token_expiry"`
+	UserToken          string    `json:"user_token"`
+	UserTokenExpiry    time.Time `json:"user_token_expiry"`
+	XSTSToken          string    `json:"xsts_token"`
+	XSTSTokenExpiry    time.Time `json:"xsts_token_expiry"`
+	UserHash           string    `json:"user_hash"`
+
+	// AudienceXSTSTokens holds XSTS tokens for non-default audiences, keyed by XSTSAudience.Key()
+	AudienceXSTSTokens map[string]*CachedXSTSToken `json:"audience_xsts_tokens,omitempty"`
+}
+
+// CachedXSTSToken represents a cached XSTS token for a specific audience
+type CachedXSTSToken struct {
+	Token    string    `json:"token"`
+	Expiry   time.Time `json:"expiry"`
+	UserHash string    `json:"user_hash"`
 }
 
 // XboxErrorResponse represents an error response from Xbox services
@@ -135,3 +255,527 @@ type XboxErrorResponse struct {
 	Message  string `json:"Message"`
 	Redirect string `json:"Redirect"`
 }
+
+// TournamentRef identifies a tournament within the Tournaments Hub
+type TournamentRef struct {
+	Organizer    string `json:"organizer"`
+	TournamentID string `json:"tournamentId"`
+}
+
+// Tournament represents an Arena tournament
+type Tournament struct {
+	TournamentRef   TournamentRef `json:"tournamentRef"`
+	Name            string        `json:"name"`
+	Description     string        `json:"description"`
+	TitleID         string        `json:"titleId"`
+	State           string        `json:"state"`
+	Status          string        `json:"status"`
+	MinTeamSize     int           `json:"minTeamSize"`
+	MaxTeamSize     int           `json:"maxTeamSize"`
+	NumTeams        int           `json:"numTeams"`
+	MaxTeams        int           `json:"maxTeams"`
+	RegistrationURL string        `json:"registrationUrl"`
+	StartTime       time.Time     `json:"startTime"`
+	EndTime         time.Time     `json:"endTime"`
+}
+
+// TournamentsResponse represents the response from the tournaments list endpoint
+type TournamentsResponse struct {
+	Value []*Tournament `json:"value"`
+}
+
+// TournamentTeam represents a team registered for a tournament
+type TournamentTeam struct {
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Members            []string  `json:"members"`
+	RegistrationState  string    `json:"registrationState"`
+	RegistrationDate   time.Time `json:"registrationDate"`
+	Standing           string    `json:"standing"`
+	Ranking            int       `json:"ranking"`
+	CompletedTimestamp time.Time `json:"completedTimestamp"`
+}
+
+// TournamentTeamsResponse represents the response from the tournament teams endpoint
+type TournamentTeamsResponse struct {
+	Value []*TournamentTeam `json:"value"`
+}
+
+// TournamentMatch represents a single match played within a tournament
+type TournamentMatch struct {
+	ID        string                   `json:"id"`
+	Round     int                      `json:"round"`
+	State     string                   `json:"state"`
+	StartTime time.Time                `json:"startTime"`
+	EndTime   time.Time                `json:"endTime"`
+	Results   []*TournamentMatchResult `json:"results"`
+}
+
+// TournamentMatchResult contains a team's outcome for a match
+type TournamentMatchResult struct {
+	TeamID  string `json:"teamId"`
+	Outcome string `json:"outcome"`
+	Ranking int    `json:"ranking"`
+	Score   int64  `json:"score"`
+}
+
+// TournamentMatchesResponse represents the response from the tournament matches endpoint
+type TournamentMatchesResponse struct {
+	Value []*TournamentMatch `json:"value"`
+}
+
+// PresenceBatchRequest represents a request for the presence of multiple users
+type PresenceBatchRequest struct {
+	Users      []string `json:"users"`
+	Level      string   `json:"level"`
+	Titles     []string `json:"titles,omitempty"`
+	OnlineOnly bool     `json:"onlineOnly,omitempty"`
+}
+
+// PresenceStateRequest represents a request to change the authenticated user's presence state
+type PresenceStateRequest struct {
+	State string `json:"state"`
+}
+
+// Presence represents a user's current presence
+type Presence struct {
+	XUID     string            `json:"xuid"`
+	State    PresenceState     `json:"state"`
+	Devices  []*PresenceDevice `json:"devices"`
+	LastSeen *PresenceLastSeen `json:"lastSeen"`
+}
+
+// PresenceDevice represents a device a user is currently active on
+type PresenceDevice struct {
+	Type   string           `json:"type"`
+	Titles []*PresenceTitle `json:"titles"`
+}
+
+// PresenceTitle represents a title a user is currently running
+type PresenceTitle struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Placement    string            `json:"placement"`
+	State        string            `json:"state"`
+	LastModified time.Time         `json:"lastModified"`
+	Activity     *PresenceActivity `json:"activity"`
+}
+
+// PresenceActivity contains rich presence for a title
+type PresenceActivity struct {
+	RichPresence string             `json:"richPresence"`
+	Broadcast    *PresenceBroadcast `json:"broadcast"`
+}
+
+// PresenceBroadcast describes a broadcast (stream) of a title
+type PresenceBroadcast struct {
+	ID       string    `json:"id"`
+	Session  string    `json:"session"`
+	Provider string    `json:"provider"`
+	Viewers  int       `json:"viewers"`
+	Started  time.Time `json:"started"`
+}
+
+// PresenceLastSeen contains the last title a user was seen in
+type PresenceLastSeen struct {
+	DeviceType string    `json:"deviceType"`
+	TitleID    string    `json:"titleId"`
+	TitleName  string    `json:"titleName"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// SuggestResponse represents the response from the user search suggest endpoint
+type SuggestResponse struct {
+	Results []*SuggestResult `json:"results"`
+}
+
+// SuggestResult is a single entry in a suggest response
+type SuggestResult struct {
+	Text   string              `json:"text"`
+	Result *GamertagSuggestion `json:"result"`
+}
+
+// GamertagSuggestion is a lightweight typeahead suggestion for a gamertag prefix
+type GamertagSuggestion struct {
+	XUID          string  `json:"id"`
+	Gamertag      string  `json:"gamertag"`
+	DisplayPicURI string  `json:"displayPicUri"`
+	Score         float64 `json:"score"`
+}
+
+// SessionRef identifies a multiplayer session in the Multiplayer Session Directory (MPSD)
+type SessionRef struct {
+	SCID         string `json:"scid"`
+	TemplateName string `json:"templateName"`
+	Name         string `json:"name"`
+}
+
+// StatsWriteRequest represents a write of a user's title stats in the 2017 stats model
+type StatsWriteRequest struct {
+	Schema           string          `json:"$schema"`
+	PreviousRevision int64           `json:"previousRevision"`
+	Revision         int64           `json:"revision"`
+	Timestamp        time.Time       `json:"timestamp"`
+	Stats            StatsWriteStats `json:"stats"`
+}
+
+// StatsWriteStats contains the stats written for a title
+type StatsWriteStats struct {
+	Title map[string]StatsWriteValue `json:"title"`
+}
+
+// StatsWriteValue is the value of a single stat
+type StatsWriteValue struct {
+	Value interface{} `json:"value"`
+}
+
+// LeaderboardResponse represents a page of a leaderboard
+type LeaderboardResponse struct {
+	PagingInfo      *PagingInfo         `json:"pagingInfo"`
+	LeaderboardInfo *LeaderboardInfo    `json:"leaderboardInfo"`
+	UserList        []*LeaderboardEntry `json:"userList"`
+}
+
+// LeaderboardInfo describes a leaderboard's size and columns
+type LeaderboardInfo struct {
+	TotalCount int                  `json:"totalCount"`
+	Columns    []*LeaderboardColumn `json:"columns"`
+}
+
+// LeaderboardColumn describes a stat shown in a leaderboard
+type LeaderboardColumn struct {
+	DisplayName string `json:"displayName"`
+	StatName    string `json:"statName"`
+	Type        string `json:"type"`
+}
+
+// LeaderboardEntry is a user's position in a leaderboard
+// Values holds the user's stats in the order of the leaderboard's columns
+type LeaderboardEntry struct {
+	XUID       string   `json:"xuid"`
+	Gamertag   string   `json:"gamertag"`
+	Rank       int      `json:"rank"`
+	GlobalRank int      `json:"globalrank"`
+	Percentile float64  `json:"percentile"`
+	Values     []string `json:"values"`
+}
+
+// SessionHandleRequest represents a request to create an MPSD handle
+type SessionHandleRequest struct {
+	Type             string            `json:"type"`
+	Version          int               `json:"version"`
+	SessionRef       SessionRef        `json:"sessionRef"`
+	InvitedXUID      string            `json:"invitedXuid,omitempty"`
+	InviteAttributes map[string]string `json:"inviteAttributes,omitempty"`
+}
+
+// SessionHandle represents an MPSD handle
+type SessionHandle struct {
+	ID               string            `json:"id"`
+	Type             string            `json:"type"`
+	Version          int               `json:"version"`
+	SessionRef       SessionRef        `json:"sessionRef"`
+	InvitedXUID      string            `json:"invitedXuid"`
+	InviteAttributes map[string]string `json:"inviteAttributes"`
+	Expiration       time.Time         `json:"expiration"`
+}
+
+// SessionHandleQuery represents a query for the MPSD handles owned by a set of users
+type SessionHandleQuery struct {
+	Type   string                   `json:"type"`
+	Owners SessionHandleQueryOwners `json:"owners"`
+}
+
+// SessionHandleQueryOwners selects the users whose handles are queried
+type SessionHandleQueryOwners struct {
+	XUIDs []string `json:"xuids"`
+}
+
+// InviteHandleQuery represents a query for the MPSD invite handles addressed to a user
+type InviteHandleQuery struct {
+	Type        string `json:"type"`
+	InvitedXUID string `json:"invitedXuid"`
+}
+
+// InviteHandleQueryResponse represents the response from an MPSD invite handle query
+type InviteHandleQueryResponse struct {
+	Results []*SessionHandle `json:"results"`
+}
+
+// SessionHandleQueryResponse represents the response from an MPSD handle query
+type SessionHandleQueryResponse struct {
+	Results []*ActivityHandle `json:"results"`
+}
+
+// ActivityHandle is an MPSD activity handle: the session a user is currently playing in
+type ActivityHandle struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	SessionRef SessionRef `json:"sessionRef"`
+	TitleID    string     `json:"titleId"`
+	OwnerXUID  string     `json:"ownerXuid"`
+}
+
+// MultiplayerSession represents an MPSD session document
+type MultiplayerSession struct {
+	Members map[string]*SessionMember `json:"members"`
+}
+
+// SessionMember represents a member of an MPSD session
+// A nil member in a session update removes that member
+type SessionMember struct {
+	Constants  *SessionMemberConstants  `json:"constants,omitempty"`
+	Properties *SessionMemberProperties `json:"properties,omitempty"`
+	Gamertag   string                   `json:"gamertag,omitempty"`
+}
+
+// SessionMemberConstants contains a member's immutable system constants
+type SessionMemberConstants struct {
+	System *SessionMemberConstantsSystem `json:"system,omitempty"`
+}
+
+// SessionMemberConstantsSystem contains the system constants of a member
+type SessionMemberConstantsSystem struct {
+	XUID       string `json:"xuid,omitempty"`
+	Initialize bool   `json:"initialize,omitempty"`
+
+	// InviteHandle is the ID of the invite handle the member joined through
+	InviteHandle string `json:"inviteHandle,omitempty"`
+}
+
+// SessionMemberProperties contains a member's mutable properties
+type SessionMemberProperties struct {
+	System *SessionMemberPropertiesSystem `json:"system,omitempty"`
+}
+
+// SessionMemberPropertiesSystem contains the system properties of a member
+type SessionMemberPropertiesSystem struct {
+	Active bool `json:"active"`
+}
+
+// ProfileSettingsRequest represents a batch request for profile settings
+type ProfileSettingsRequest struct {
+	UserIDs  []string `json:"userIds"`
+	Settings []string `json:"settings"`
+}
+
+// ProfileSettingsResponse represents the response from the profile settings endpoint
+type ProfileSettingsResponse struct {
+	ProfileUsers []*ProfileUser `json:"profileUsers"`
+}
+
+// ProfileUser contains the requested settings for one user
+type ProfileUser struct {
+	ID       string            `json:"id"`
+	HostID   string            `json:"hostId"`
+	Settings []*ProfileSetting `json:"settings"`
+}
+
+// ProfileSetting is a single profile setting value
+type ProfileSetting struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// Setting returns the value of a setting, or an empty string if it wasn't returned
+func (u *ProfileUser) Setting(id string) string {
+	for _, s := range u.Settings {
+		if s.ID == id {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// PagingInfo contains the continuation state of a paged list response
+type PagingInfo struct {
+	ContinuationToken string `json:"continuationToken"`
+	TotalRecords      int    `json:"totalRecords"`
+}
+
+// Achievement represents an achievement and a user's progress toward it
+type Achievement struct {
+	ID                string                   `json:"id"`
+	ServiceConfigID   string                   `json:"serviceConfigId"`
+	Name              string                   `json:"name"`
+	TitleAssociations []*AchievementTitle      `json:"titleAssociations"`
+	ProgressState     string                   `json:"progressState"`
+	Progression       *AchievementProgression  `json:"progression"`
+	Description       string                   `json:"description"`
+	LockedDescription string                   `json:"lockedDescription"`
+	IsSecret          bool                     `json:"isSecret"`
+	Rewards           []*AchievementReward     `json:"rewards"`
+	MediaAssets       []*AchievementMediaAsset `json:"mediaAssets"`
+	Rarity            *AchievementRarity       `json:"rarity"`
+}
+
+// AchievementTitle identifies a title an achievement belongs to
+type AchievementTitle struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+}
+
+// AchievementProgression contains a user's progress toward an achievement
+type AchievementProgression struct {
+	TimeUnlocked time.Time `json:"timeUnlocked"`
+}
+
+// AchievementReward is a reward (usually gamerscore) granted by an achievement
+type AchievementReward struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Type        string `json:"type"`
+	ValueType   string `json:"valueType"`
+}
+
+// AchievementMediaAsset is an image associated with an achievement
+type AchievementMediaAsset struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// AchievementRarity describes how many players have unlocked an achievement
+type AchievementRarity struct {
+	CurrentCategory   string  `json:"currentCategory"`
+	CurrentPercentage float64 `json:"currentPercentage"`
+}
+
+// AchievementsResponse represents the response from the achievements endpoint
+type AchievementsResponse struct {
+	Achievements []*Achievement `json:"achievements"`
+	PagingInfo   PagingInfo     `json:"pagingInfo"`
+}
+
+// Screenshot represents the metadata of a captured screenshot
+type Screenshot struct {
+	ScreenshotID string        `json:"screenshotId"`
+	TitleID      int64         `json:"titleId"`
+	TitleName    string        `json:"titleName"`
+	DateTaken    time.Time     `json:"dateTaken"`
+	UserCaption  string        `json:"userCaption"`
+	Views        int           `json:"views"`
+	Thumbnails   []*CaptureURI `json:"thumbnails"`
+	URIs         []*CaptureURI `json:"screenshotUris"`
+}
+
+// ScreenshotsResponse represents the response from the screenshots endpoint
+type ScreenshotsResponse struct {
+	Screenshots []*Screenshot `json:"screenshots"`
+	PagingInfo  PagingInfo    `json:"pagingInfo"`
+}
+
+// GameClip represents the metadata of a recorded game clip
+type GameClip struct {
+	GameClipID      string        `json:"gameClipId"`
+	TitleID         int64         `json:"titleId"`
+	TitleName       string        `json:"titleName"`
+	DateRecorded    time.Time     `json:"dateRecorded"`
+	DurationSeconds int           `json:"durationInSeconds"`
+	UserCaption     string        `json:"userCaption"`
+	Views           int           `json:"views"`
+	Thumbnails      []*CaptureURI `json:"thumbnails"`
+	URIs            []*CaptureURI `json:"gameClipUris"`
+}
+
+// GameClipsResponse represents the response from the game clips endpoint
+type GameClipsResponse struct {
+	GameClips  []*GameClip `json:"gameClips"`
+	PagingInfo PagingInfo  `json:"pagingInfo"`
+}
+
+// CaptureURI is a download location for a capture or its thumbnail
+type CaptureURI struct {
+	URI        string    `json:"uri"`
+	FileSize   int64     `json:"fileSize"`
+	URIType    string    `json:"uriType"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// ActivityItem is an entry in a user's activity feed
+type ActivityItem struct {
+	Date             time.Time `json:"date"`
+	ActivityItemType string    `json:"activityItemType"`
+	ContentType      string    `json:"contentType"`
+	Description      string    `json:"description"`
+	ShortDescription string    `json:"shortDescription"`
+	ItemText         string    `json:"itemText"`
+	TitleID          string    `json:"titleId"`
+	ContentTitle     string    `json:"contentTitle"`
+	GamerscoreReward int       `json:"gamerscore"`
+}
+
+// ActivityResponse represents the response from the activity history endpoint
+type ActivityResponse struct {
+	ActivityItems     []*ActivityItem `json:"activityItems"`
+	ContinuationToken string          `json:"contToken"`
+}
+
+// Conversation is a summary of a message conversation
+type Conversation struct {
+	ConversationID string   `json:"conversationId"`
+	Type           string   `json:"conversationType"`
+	Participants   []string `json:"participants"`
+	LastMessage    *Message `json:"lastMessage"`
+}
+
+// ConversationsResponse represents the response from the inbox endpoint
+type ConversationsResponse struct {
+	Primary struct {
+		Conversations     []*Conversation `json:"conversations"`
+		ContinuationToken string          `json:"continuationToken"`
+	} `json:"primary"`
+}
+
+// Message is a single message in a conversation
+type Message struct {
+	MessageID      string          `json:"messageId"`
+	Sender         string          `json:"sender"`
+	Timestamp      time.Time       `json:"timestamp"`
+	ContentPayload json.RawMessage `json:"contentPayload"`
+}
+
+// MessagesResponse represents the response from the conversation messages endpoint
+type MessagesResponse struct {
+	Messages []*Message `json:"messages"`
+}
+
+// TitleStorageQuotaResponse represents the response from a title storage quota query
+type TitleStorageQuotaResponse struct {
+	QuotaInfo TitleStorageQuotaInfo `json:"quotaInfo"`
+}
+
+// TitleStorageQuotaInfo contains the bytes used and allowed in a title storage area
+type TitleStorageQuotaInfo struct {
+	UsedBytes  int64 `json:"usedBytes"`
+	QuotaBytes int64 `json:"quotaBytes"`
+}
+
+// ConsoleListResponse represents the response from the remote management device list
+type ConsoleListResponse struct {
+	Result []*Console `json:"result"`
+}
+
+// ConsoleCommandRequest represents a command sent to a console through remote management
+type ConsoleCommandRequest struct {
+	Destination  string        `json:"destination"`
+	Type         string        `json:"type"`
+	Command      string        `json:"command"`
+	SessionID    string        `json:"sessionId"`
+	SourceID     string        `json:"sourceId"`
+	Parameters   []interface{} `json:"parameters"`
+	LinkedXboxID string        `json:"linkedXboxId"`
+}
+
+// ConsoleCommandResponse represents the response to a remote management command
+type ConsoleCommandResponse struct {
+	OpID   string                `json:"opId"`
+	Status *ConsoleCommandStatus `json:"status"`
+}
+
+// ConsoleCommandStatus reports whether a console accepted a command
+type ConsoleCommandStatus struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
@@ -0,0 +1,49 @@
+package xblive
+
+import (
+	"net/http"
+)
+
+// libraryUserAgent identifies this library in the User-Agent header
+const libraryUserAgent = "xblive-go"
+
+// userAgent builds the User-Agent sent with every request: "AppName/AppVersion xblive-go", or just the library name when no app is configured
+func userAgent(config Config) string {
+	if config.AppName == "" {
+		return libraryUserAgent
+	}
+	app := config.AppName
+	if config.AppVersion != "" {
+		app += "/" + config.AppVersion
+	}
+	return app + " " + libraryUserAgent
+}
+
+// headerTransport sets the User-Agent header on every request that doesn't already have one,
+// and the MS-CV header from the request context's correlation vector
+type headerTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+// RoundTrip adds the identifying headers and sends the request
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	cv := CorrelationVectorFromContext(req.Context())
+	if req.Header.Get("User-Agent") != "" && cv == nil {
+		return base.RoundTrip(req)
+	}
+
+	out := req.Clone(req.Context())
+	if out.Header.Get("User-Agent") == "" {
+		out.Header.Set("User-Agent", t.userAgent)
+	}
+	if cv != nil {
+		out.Header.Set(CorrelationVectorHeader, cv.Increment())
+	}
+	return base.RoundTrip(out)
+}
@@ -0,0 +1,69 @@
+package xblive
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// XUIDValidation is the result of ValidateXUIDs
+// Each input XUID appears in exactly one set, in input order
+type XUIDValidation struct {
+	// Active XUIDs resolve to accounts in good standing
+	Active []string `json:"active"`
+
+	// Missing XUIDs are malformed or no longer resolve to an account
+	Missing []string `json:"missing"`
+
+	// Quarantined XUIDs resolve to accounts Xbox Live has quarantined
+	Quarantined []string `json:"quarantined"`
+}
+
+// ValidateXUIDs checks which XUIDs still resolve to active accounts, in batches
+// Duplicate XUIDs are checked once. Use it to prune stale entries from allowlists and databases
+func (c *Client) ValidateXUIDs(ctx context.Context, xuids []string) (*XUIDValidation, error) {
+	result := &XUIDValidation{}
+
+	seen := make(map[string]bool)
+	var wellFormed []string
+	for _, xuid := range xuids {
+		if seen[xuid] {
+			continue
+		}
+		seen[xuid] = true
+
+		if _, err := strconv.ParseUint(xuid, 10, 64); err != nil {
+			result.Missing = append(result.Missing, xuid)
+			continue
+		}
+		wellFormed = append(wellFormed, xuid)
+	}
+
+	for start := 0; start < len(wellFormed); start += maxProfileBatchSize {
+		batch := wellFormed[start:min(start+maxProfileBatchSize, len(wellFormed))]
+
+		profiles, err := c.getPeopleByXUIDs(ctx, batch, []Decoration{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate XUIDs: %w", err)
+		}
+
+		found := make(map[string]*Profile, len(profiles))
+		for _, profile := range profiles {
+			found[profile.XUID] = profile
+		}
+
+		for _, xuid := range batch {
+			profile, ok := found[xuid]
+			switch {
+			case !ok:
+				result.Missing = append(result.Missing, xuid)
+			case profile.IsQuarantined:
+				result.Quarantined = append(result.Quarantined, xuid)
+			default:
+				result.Active = append(result.Active, xuid)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,290 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval is the polling interval used when none is configured
+	DefaultWatchInterval = time.Minute
+
+	// watchPageSize is the number of unlocks fetched per page; a poll pages back until it reaches unlocks already seen
+	watchPageSize = 25
+
+	// achievementStatePrefix namespaces the AchievementWatcher's unlock times in a StateStore
+	achievementStatePrefix = "achievements/"
+)
+
+// AchievementUnlock is emitted when a watched user unlocks an achievement
+type AchievementUnlock struct {
+	XUID            string    `json:"xuid"`
+	TitleID         int64     `json:"titleId"`
+	TitleName       string    `json:"titleName"`
+	AchievementID   string    `json:"achievementId"`
+	AchievementName string    `json:"achievementName"`
+	Description     string    `json:"description"`
+	Gamerscore      int       `json:"gamerscore"`
+	UnlockedAt      time.Time `json:"unlockedAt"`
+}
+
+// AchievementWatcherConfig configures an AchievementWatcher
+type AchievementWatcherConfig struct {
+	// XUIDs is the set of users to watch (required)
+	XUIDs []string
+
+	// OnUnlock is called for each newly unlocked achievement, oldest first (required)
+	OnUnlock func(AchievementUnlock)
+
+	// Interval is the time between polls (optional, defaults to DefaultWatchInterval)
+	Interval time.Duration
+
+	// OnError is called when polling a user fails (optional)
+	// Failures don't stop the watcher; the user is polled again on the next interval
+	OnError func(xuid string, err error)
+
+	// Store persists each user's most recent unlocks seen across restarts (optional, defaults to memory only)
+	// A restarted watcher then reports unlocks that happened while it was down instead of starting over
+	Store StateStore
+}
+
+// AchievementWatcher polls watched users' achievements and emits an event for each new unlock
+// Achievements unlocked before the watcher's first poll are not reported
+type AchievementWatcher struct {
+	client   *Client
+	xuids    []string
+	onUnlock func(AchievementUnlock)
+	onError  func(string, error)
+	interval time.Duration
+	store    StateStore
+
+	// seen is the most recent unlocks seen per user; users without an entry haven't been polled successfully yet
+	seen map[string]*achievementWatchState
+}
+
+// achievementWatchState is the most recent unlock time seen for a user, with the achievements unlocked at that time
+// Several achievements can share an unlock time, and one of them may only show up in a later poll
+type achievementWatchState struct {
+	Since time.Time `json:"since"`
+	IDs   []string  `json:"ids,omitempty"`
+
+	// timeOnly marks state saved by earlier versions, which recorded only the time; everything unlocked then was seen
+	timeOnly bool
+}
+
+// UnmarshalJSON also accepts the bare unlock time stored by earlier versions
+func (s *achievementWatchState) UnmarshalJSON(data []byte) error {
+	var since time.Time
+	if err := json.Unmarshal(data, &since); err == nil {
+		*s = achievementWatchState{Since: since, timeOnly: true}
+		return nil
+	}
+	type plain achievementWatchState
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// has reports whether an achievement unlocked at a time was already seen
+func (s *achievementWatchState) has(unlocked time.Time, id string) bool {
+	if unlocked.Before(s.Since) || (s.timeOnly && unlocked.Equal(s.Since)) {
+		return true
+	}
+	return unlocked.Equal(s.Since) && slices.Contains(s.IDs, id)
+}
+
+// achievementKey identifies an achievement across titles
+func achievementKey(a *Achievement) string {
+	return a.ServiceConfigID + "/" + a.ID
+}
+
+// NewAchievementWatcher creates a watcher for achievement unlocks
+func (c *Client) NewAchievementWatcher(config AchievementWatcherConfig) (*AchievementWatcher, error) {
+	if len(config.XUIDs) == 0 {
+		return nil, fmt.Errorf("at least one XUID is required")
+	}
+	if config.OnUnlock == nil {
+		return nil, fmt.Errorf("unlock callback is required")
+	}
+
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	if config.Store != nil {
+		c.addFlusher(config.Store)
+	}
+
+	return &AchievementWatcher{
+		client:   c,
+		xuids:    config.XUIDs,
+		onUnlock: config.OnUnlock,
+		onError:  config.OnError,
+		interval: interval,
+		store:    config.Store,
+		seen:     make(map[string]*achievementWatchState),
+	}, nil
+}
+
+// Run polls until the context is cancelled or the client is shut down
+func (w *AchievementWatcher) Run(ctx context.Context) error {
+	ctx, done, err := w.client.track(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	for {
+		w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return w.client.stopped(ctx)
+		case <-w.client.clock.After(w.interval):
+		}
+	}
+}
+
+// Poll checks every watched user once and emits events for new unlocks
+// Run calls it on each interval; call it directly to drive the watcher from your own scheduler
+func (w *AchievementWatcher) Poll(ctx context.Context) {
+	for _, xuid := range w.xuids {
+		if err := w.poll(ctx, xuid); err != nil {
+			w.client.logger.Warn("achievement poll failed", "xuid", xuid, "error", err)
+			if w.onError != nil {
+				w.onError(xuid, err)
+			}
+		}
+	}
+}
+
+// poll checks one user for new unlocks
+func (w *AchievementWatcher) poll(ctx context.Context, xuid string) error {
+	state, polled := w.seen[xuid]
+	if !polled && w.store != nil {
+		state = &achievementWatchState{}
+		found, err := getState(ctx, w.store, achievementStatePrefix+xuid, state)
+		if err != nil {
+			return fmt.Errorf("failed to load achievement state: %w", err)
+		}
+		polled = found
+	}
+
+	// On the first poll only the latest unlocks are needed, to know where to start; after that, page back until
+	// reaching unlocks from before the last one seen, so a burst of unlocks (or a restart) loses none
+	var unlocks []*Achievement
+	token := ""
+	for {
+		page, more, err := w.client.getUnlocksPage(ctx, xuid, watchPageSize, token)
+		if err != nil {
+			return err
+		}
+		reachedSeen := false
+		for _, a := range page {
+			if a.Progression == nil {
+				continue
+			}
+			if polled && a.Progression.TimeUnlocked.Before(state.Since) {
+				reachedSeen = true
+				break
+			}
+			unlocks = append(unlocks, a)
+		}
+		if !polled || reachedSeen || more == "" {
+			break
+		}
+		token = more
+	}
+
+	next := &achievementWatchState{}
+	if polled {
+		*next = *state
+		next.IDs = slices.Clone(state.IDs)
+	}
+
+	// Achievements are returned most recent first; emit oldest first
+	for i := len(unlocks) - 1; i >= 0; i-- {
+		a := unlocks[i]
+		unlocked := a.Progression.TimeUnlocked
+		key := achievementKey(a)
+		if polled && state.has(unlocked, key) {
+			continue
+		}
+		switch {
+		case unlocked.After(next.Since):
+			next.Since = unlocked
+			next.IDs = []string{key}
+			next.timeOnly = false
+		case unlocked.Equal(next.Since):
+			next.IDs = append(next.IDs, key)
+		}
+		if polled {
+			w.onUnlock(newAchievementUnlock(xuid, a))
+		}
+	}
+
+	if !polled && next.Since.IsZero() {
+		// No unlocks yet; anything unlocked from now on is new
+		next.Since = w.client.clock.Now()
+	}
+	changed := !polled || !next.Since.Equal(state.Since) || len(next.IDs) != len(state.IDs)
+	if changed && w.store != nil {
+		if err := putState(ctx, w.store, achievementStatePrefix+xuid, next); err != nil {
+			return fmt.Errorf("failed to save achievement state: %w", err)
+		}
+	}
+	w.seen[xuid] = next
+
+	return nil
+}
+
+// getUnlocksPage returns one page of a user's unlocked achievements across all titles, most recent first
+func (c *Client) getUnlocksPage(ctx context.Context, xuid string, maxItems int, token string) ([]*Achievement, string, error) {
+	params := url.Values{}
+	params.Set("unlockedOnly", "true")
+	params.Set("orderBy", "unlockTime")
+	params.Set("maxItems", strconv.Itoa(maxItems))
+	if token != "" {
+		params.Set("continuationToken", token)
+	}
+
+	endpoint := fmt.Sprintf("%s/xuid(%s)/achievements?%s", achievementsEndpoint, url.PathEscape(xuid), params.Encode())
+
+	var resp AchievementsResponse
+	if err := c.xblRequest(ctx, "GET", endpoint, ServiceAchievements, nil, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to get achievements: %w", err)
+	}
+
+	return resp.Achievements, resp.PagingInfo.ContinuationToken, nil
+}
+
+// newAchievementUnlock builds an unlock event from an unlocked achievement
+func newAchievementUnlock(xuid string, a *Achievement) AchievementUnlock {
+	unlock := AchievementUnlock{
+		XUID:            xuid,
+		AchievementID:   a.ID,
+		AchievementName: a.Name,
+		Description:     a.Description,
+		UnlockedAt:      a.Progression.TimeUnlocked,
+	}
+
+	if len(a.TitleAssociations) > 0 {
+		unlock.TitleID = a.TitleAssociations[0].ID
+		unlock.TitleName = a.TitleAssociations[0].Name
+	}
+
+	for _, reward := range a.Rewards {
+		if strings.EqualFold(reward.Type, "Gamerscore") {
+			if score, err := strconv.Atoi(reward.Value); err == nil {
+				unlock.Gamerscore += score
+			}
+		}
+	}
+
+	return unlock
+}
@@ -0,0 +1,141 @@
+package xblive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// unlockServer serves a user's unlocked achievements, most recent first, in pages like the achievements service
+type unlockServer struct {
+	mu      sync.Mutex
+	unlocks []*Achievement
+}
+
+// unlock adds an achievement unlocked at a time
+func (s *unlockServer) unlock(id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := &Achievement{ID: id, ServiceConfigID: "scid", Name: id, Progression: &AchievementProgression{TimeUnlocked: at}}
+	i := 0
+	for i < len(s.unlocks) && !s.unlocks[i].Progression.TimeUnlocked.Before(at) {
+		i++
+	}
+	s.unlocks = append(s.unlocks[:i], append([]*Achievement{a}, s.unlocks[i:]...)...)
+}
+
+func (s *unlockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maxItems, _ := strconv.Atoi(r.URL.Query().Get("maxItems"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("continuationToken"))
+	end := min(offset+maxItems, len(s.unlocks))
+
+	resp := AchievementsResponse{Achievements: s.unlocks[offset:end]}
+	if end < len(s.unlocks) {
+		resp.PagingInfo.ContinuationToken = strconv.Itoa(end)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// newTestWatcher creates an achievement watcher for one user whose unlocks are served by server
+func newTestWatcher(t *testing.T, server *httptest.Server, store StateStore, emitted *[]string) *AchievementWatcher {
+	t.Helper()
+
+	cache := NewMemoryTokenCache()
+	if err := cache.SetXSTSToken(context.Background(), "xsts-token", "user-hash", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := newTestClient(t, server, cache).NewAchievementWatcher(AchievementWatcherConfig{
+		XUIDs:    []string{"2533274792093503"},
+		OnUnlock: func(u AchievementUnlock) { *emitted = append(*emitted, u.AchievementID) },
+		OnError:  func(xuid string, err error) { t.Errorf("poll of %s failed: %v", xuid, err) },
+		Store:    store,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return watcher
+}
+
+func TestAchievementWatcherPagesAndBoundary(t *testing.T) {
+	unlocks := &unlockServer{}
+	server := httptest.NewServer(unlocks)
+	defer server.Close()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	unlocks.unlock("old", start)
+
+	store := NewMemoryStateStore()
+	var emitted []string
+	watcher := newTestWatcher(t, server, store, &emitted)
+
+	// The first poll only records where to start
+	watcher.Poll(ctx)
+	if len(emitted) != 0 {
+		t.Fatalf("first poll emitted %v; want nothing", emitted)
+	}
+
+	// More unlocks than fit in a page are all reported, oldest first
+	var want []string
+	for i := 1; i <= 2*watchPageSize+5; i++ {
+		id := fmt.Sprintf("a%d", i)
+		unlocks.unlock(id, start.Add(time.Duration(i)*time.Minute))
+		want = append(want, id)
+	}
+	watcher.Poll(ctx)
+	if fmt.Sprint(emitted) != fmt.Sprint(want) {
+		t.Fatalf("emitted %v; want %v", emitted, want)
+	}
+
+	// An achievement sharing the last unlock time, showing up in a later poll, is still reported, once
+	last := start.Add(time.Duration(2*watchPageSize+5) * time.Minute)
+	unlocks.unlock("same-time", last)
+	emitted = nil
+	watcher.Poll(ctx)
+	watcher.Poll(ctx)
+	if fmt.Sprint(emitted) != "[same-time]" {
+		t.Errorf("emitted %v; want [same-time]", emitted)
+	}
+
+	// A restarted watcher picks up where the stored state left off
+	var restarted []string
+	watcher = newTestWatcher(t, server, store, &restarted)
+	unlocks.unlock("while-down", last.Add(time.Minute))
+	watcher.Poll(ctx)
+	if fmt.Sprint(restarted) != "[while-down]" {
+		t.Errorf("restarted watcher emitted %v; want [while-down]", restarted)
+	}
+}
+
+func TestAchievementWatcherTimeOnlyState(t *testing.T) {
+	unlocks := &unlockServer{}
+	server := httptest.NewServer(unlocks)
+	defer server.Close()
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	unlocks.unlock("seen", start)
+	unlocks.unlock("new", start.Add(time.Minute))
+
+	// State saved by earlier versions is just the last unlock time
+	store := NewMemoryStateStore()
+	if err := putState(ctx, store, achievementStatePrefix+"2533274792093503", start); err != nil {
+		t.Fatal(err)
+	}
+
+	var emitted []string
+	newTestWatcher(t, server, store, &emitted).Poll(ctx)
+	if fmt.Sprint(emitted) != "[new]" {
+		t.Errorf("emitted %v; want [new]", emitted)
+	}
+}
@@ -0,0 +1,163 @@
+// Package xblivetest provides utilities for testing code built on xblive, such as a
+// fault-injecting transport for checking retry and backoff behavior under failure
+package xblivetest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the transport error returned for requests failed by a FaultTransport
+var ErrInjected = errors.New("xblivetest: injected transport error")
+
+// malformedJSON replaces response bodies chosen for corruption
+const malformedJSON = `{"people":[{"xuid":"2533274`
+
+// FaultTransport is an http.RoundTripper that injects latency, transport errors, malformed JSON, and 429 rate limiting
+// Set it as xblive.Config.Transport to verify retry and backoff configuration. Rates are probabilities in [0, 1]
+type FaultTransport struct {
+	// Base performs requests that aren't failed outright (optional, defaults to http.DefaultTransport)
+	Base http.RoundTripper
+
+	// Latency is added before every request, plus a random amount up to Jitter
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ErrorRate is the fraction of requests that fail with ErrInjected without being sent
+	ErrorRate float64
+
+	// MalformedRate is the fraction of successful responses whose body is replaced with truncated JSON
+	MalformedRate float64
+
+	// RateLimitRate is the fraction of requests answered with 429 Too Many Requests without being sent
+	RateLimitRate float64
+
+	// StormEvery and StormLength inject 429 storms: after every StormEvery requests, the next StormLength requests are rate limited
+	StormEvery  int
+	StormLength int
+
+	// RetryAfter is sent in the Retry-After header of injected 429 responses (optional)
+	RetryAfter time.Duration
+
+	// Rand is the random source (optional); seed one for reproducible runs
+	Rand *rand.Rand
+
+	mu       sync.Mutex
+	requests int
+	stats    FaultStats
+}
+
+// FaultStats counts the faults a FaultTransport has injected
+type FaultStats struct {
+	Requests    int
+	Errors      int
+	Malformed   int
+	RateLimited int
+}
+
+// RoundTrip sends the request, injecting faults according to the transport's configuration
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay, fail, rateLimit, malform := t.decide()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if fail {
+		return nil, ErrInjected
+	}
+	if rateLimit {
+		return t.rateLimited(req), nil
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || !malform || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	t.mu.Lock()
+	t.stats.Malformed++
+	t.mu.Unlock()
+
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader([]byte(malformedJSON)))
+	resp.ContentLength = int64(len(malformedJSON))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// Stats returns the number of requests seen and faults injected so far
+func (t *FaultTransport) Stats() FaultStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// decide picks the faults for the next request
+func (t *FaultTransport) decide() (delay time.Duration, fail bool, rateLimit bool, malform bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Rand == nil {
+		t.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	n := t.requests
+	t.requests++
+	t.stats.Requests++
+
+	delay = t.Latency
+	if t.Jitter > 0 {
+		delay += time.Duration(t.Rand.Int63n(int64(t.Jitter)))
+	}
+
+	inStorm := t.StormEvery > 0 && t.StormLength > 0 && n%(t.StormEvery+t.StormLength) >= t.StormEvery
+	switch {
+	case t.Rand.Float64() < t.ErrorRate:
+		fail = true
+		t.stats.Errors++
+	case inStorm || t.Rand.Float64() < t.RateLimitRate:
+		rateLimit = true
+		t.stats.RateLimited++
+	case t.Rand.Float64() < t.MalformedRate:
+		malform = true
+	}
+	return delay, fail, rateLimit, malform
+}
+
+// rateLimited builds an injected 429 response
+func (t *FaultTransport) rateLimited(req *http.Request) *http.Response {
+	body := `{"code":429,"description":"injected rate limit"}`
+	resp := &http.Response{
+		Status:        "429 Too Many Requests",
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	if t.RetryAfter > 0 {
+		resp.Header.Set("Retry-After", strconv.Itoa(int((t.RetryAfter+time.Second-1)/time.Second)))
+	}
+	return resp
+}
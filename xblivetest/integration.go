@@ -0,0 +1,40 @@
+package xblivetest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/tadhunt/xblive"
+)
+
+// IntegrationClient returns a client for tests against the real Xbox Live services, or skips the test
+// It skips unless XBLIVE_CLIENT_ID is set and the token cache holds a refresh token (run the CLI's "auth login" first),
+// then resolves the full token chain so that a broken refresh fails the test rather than a later request
+// Configure the client with the usual XBLIVE_* environment variables; see xblive.NewFromEnv
+func IntegrationClient(tb testing.TB) *xblive.Client {
+	tb.Helper()
+
+	if os.Getenv(xblive.EnvClientID) == "" {
+		tb.Skipf("integration test skipped: %s is not set", xblive.EnvClientID)
+	}
+
+	client, err := xblive.NewFromEnv()
+	if err != nil {
+		tb.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	info, err := client.TokenInfo(ctx)
+	if err != nil {
+		tb.Fatalf("failed to read token cache: %v", err)
+	}
+	if !info.RefreshTokenPresent {
+		tb.Skip("integration test skipped: no cached credentials")
+	}
+
+	if _, err := client.Warmup(ctx); err != nil {
+		tb.Fatalf("failed to refresh credentials: %v", err)
+	}
+	return client
+}
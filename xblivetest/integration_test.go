@@ -0,0 +1,150 @@
+//go:build integration
+
+package xblivetest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tadhunt/xblive"
+	"github.com/tadhunt/xblive/xblivetest"
+)
+
+// Run with: go test -tags integration ./xblivetest
+// Needs XBLIVE_CLIENT_ID and credentials cached by the CLI's "auth login"
+
+// integrationTimeout bounds each test's calls to the live services
+const integrationTimeout = time.Minute
+
+// identity returns the authenticated user's claims
+func identity(t *testing.T, ctx context.Context, client *xblive.Client) *xblive.XSTSClaims {
+	t.Helper()
+
+	claims, err := client.Identity(ctx)
+	if err != nil {
+		t.Fatalf("Identity: %v", err)
+	}
+	if claims.XUID == "" || claims.Gamertag == "" {
+		t.Fatalf("Identity returned no XUID or gamertag: %+v", claims)
+	}
+	return claims
+}
+
+func TestIntegrationRefresh(t *testing.T) {
+	xblivetest.IntegrationClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	config, err := xblive.ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := config.Cache
+	if cache == nil {
+		if config.CachePath != "" {
+			cache, err = xblive.NewFileTokenCacheWithPath(config.CachePath)
+		} else {
+			cache, err = xblive.NewFileTokenCache()
+		}
+		if err != nil {
+			t.Fatalf("failed to open token cache: %v", err)
+		}
+	}
+	snapshotter, ok := cache.(xblive.TokenSnapshotter)
+	if !ok {
+		t.Skip("token cache can't be snapshotted")
+	}
+	tokens, err := snapshotter.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a client from the refresh token alone, so every link of the chain is redeemed again
+	fresh := xblive.NewMemoryTokenCache()
+	if err := fresh.SetRefreshToken(ctx, tokens.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	client, err := xblive.New(xblive.Config{ClientID: config.ClientID, Tenant: config.Tenant, Cache: fresh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	info, err := client.Warmup(ctx)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if !info.AccessTokenValid || !info.XSTSTokenValid {
+		t.Errorf("tokens not valid after refresh: %+v", info)
+	}
+
+	// Keep the rotated refresh token so the original cache stays current
+	if rotated, ok := fresh.GetRefreshToken(ctx); ok && rotated != tokens.RefreshToken {
+		if err := cache.SetRefreshToken(ctx, rotated); err != nil {
+			t.Logf("failed to save rotated refresh token: %v", err)
+		}
+	}
+}
+
+func TestIntegrationLookup(t *testing.T) {
+	client := xblivetest.IntegrationClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	claims := identity(t, ctx, client)
+
+	profile, err := client.LookupProfileByGamertag(ctx, claims.Gamertag)
+	if err != nil {
+		t.Fatalf("LookupProfileByGamertag(%q): %v", claims.Gamertag, err)
+	}
+	if profile.XUID != claims.XUID {
+		t.Errorf("LookupProfileByGamertag(%q) XUID = %s; want %s", claims.Gamertag, profile.XUID, claims.XUID)
+	}
+
+	xuids, _, err := client.GamertagsToXUIDs(ctx, []string{claims.Gamertag})
+	if err != nil {
+		t.Fatalf("GamertagsToXUIDs: %v", err)
+	}
+	if xuids[claims.Gamertag] != claims.XUID {
+		t.Errorf("GamertagsToXUIDs = %v; want %s", xuids, claims.XUID)
+	}
+}
+
+func TestIntegrationProfile(t *testing.T) {
+	client := xblivetest.IntegrationClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	claims := identity(t, ctx, client)
+
+	profile, err := client.GetProfile(ctx, claims.XUID)
+	if err != nil {
+		t.Fatalf("GetProfile(%s): %v", claims.XUID, err)
+	}
+	if profile.XUID != claims.XUID || !xblive.GamertagsEqual(profile.Gamertag, claims.Gamertag) {
+		t.Errorf("GetProfile = %s %q; want %s %q", profile.XUID, profile.Gamertag, claims.XUID, claims.Gamertag)
+	}
+	if profile.GamerScore == "" {
+		t.Errorf("GetProfile returned no gamerscore")
+	}
+}
+
+func TestIntegrationPresence(t *testing.T) {
+	client := xblivetest.IntegrationClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	claims := identity(t, ctx, client)
+
+	presence, err := client.GetPresence(ctx, []string{claims.XUID})
+	if err != nil {
+		t.Fatalf("GetPresence: %v", err)
+	}
+	if len(presence) != 1 || presence[0].XUID != claims.XUID {
+		t.Fatalf("GetPresence returned %d records; want one for %s", len(presence), claims.XUID)
+	}
+	if presence[0].State == "" {
+		t.Errorf("GetPresence returned no state")
+	}
+}
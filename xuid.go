@@ -0,0 +1,81 @@
+package xblive
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// floodgatePrefix is the all-zero high half of a Floodgate UUID, whose low 64 bits are the XUID
+const floodgatePrefix = "00000000-0000-0000-"
+
+// parseXUIDNumber parses a decimal XUID
+func parseXUIDNumber(xuid string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(xuid), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid XUID '%s'", xuid)
+	}
+	return n, nil
+}
+
+// XUIDToHex returns a decimal XUID as 16 upper-case hex digits, the form shown by Xbox developer tools
+func XUIDToHex(xuid string) (string, error) {
+	n, err := parseXUIDNumber(xuid)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016X", n), nil
+}
+
+// XUIDFromHex returns the decimal XUID for a hex XUID, with or without a 0x prefix, in either case
+func XUIDFromHex(hex string) (string, error) {
+	digits := strings.TrimSpace(hex)
+	digits = strings.TrimPrefix(strings.TrimPrefix(digits, "0x"), "0X")
+	n, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil || n == 0 {
+		return "", fmt.Errorf("invalid hex XUID '%s'", hex)
+	}
+	return strconv.FormatUint(n, 10), nil
+}
+
+// XUIDToFloodgateUUID returns the UUID Geyser's Floodgate gives a Bedrock player on a Java server:
+// zero in the high 64 bits and the XUID in the low 64 bits, e.g. 00000000-0000-0000-0009-01f00bbb28c0
+// This is not a PlayFab ID (PFID), which is assigned by PlayFab and can only be looked up, not derived from the XUID
+func XUIDToFloodgateUUID(xuid string) (string, error) {
+	n, err := parseXUIDNumber(xuid)
+	if err != nil {
+		return "", err
+	}
+	hex := fmt.Sprintf("%016x", n)
+	return floodgatePrefix + hex[:4] + "-" + hex[4:], nil
+}
+
+// XUIDFromFloodgateUUID returns the decimal XUID of a Floodgate UUID, with or without dashes
+func XUIDFromFloodgateUUID(uuid string) (string, error) {
+	digits := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(uuid), "-", ""))
+	if len(digits) != 32 || digits[:16] != "0000000000000000" {
+		return "", fmt.Errorf("invalid Floodgate UUID '%s'", uuid)
+	}
+	xuid, err := XUIDFromHex(digits[16:])
+	if err != nil {
+		return "", fmt.Errorf("invalid Floodgate UUID '%s'", uuid)
+	}
+	return xuid, nil
+}
+
+// ParseXUID returns the decimal XUID for any of the forms other tools use: decimal, hex with a 0x prefix,
+// or a Floodgate UUID. Hex without the prefix is rejected because it can't be told apart from decimal
+func ParseXUID(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		return XUIDFromHex(s)
+	case strings.Contains(s, "-"), len(s) == 32:
+		return XUIDFromFloodgateUUID(s)
+	}
+	n, err := parseXUIDNumber(s)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(n, 10), nil
+}